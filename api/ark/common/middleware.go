@@ -26,13 +26,17 @@ import (
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/internal/ark"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	"github.com/banzaicloud/pipeline/pkg/providers"
+	"github.com/banzaicloud/pipeline/secret"
 )
 
 const (
 	arkServiceName = "arkService"
+	clusterName    = "cluster"
 )
 
 // ARKMiddleware is a middleware for initializing a CommonCluster and an ARKService
@@ -73,6 +77,7 @@ func ARKMiddleware(db *gorm.DB, logger logrus.FieldLogger) gin.HandlerFunc {
 
 		svc := ark.NewARKService(org, cluster, db, logger)
 		c.Request = setVariableToContext(c.Request, arkServiceName, svc)
+		c.Request = setVariableToContext(c.Request, clusterName, cluster)
 
 		c.Next()
 	}
@@ -86,6 +91,26 @@ func GetARKService(req *http.Request) *ark.Service {
 	return nil
 }
 
+// GetCluster returns the cluster of the current request
+func GetCluster(req *http.Request) cluster.CommonCluster {
+	if cl := req.Context().Value(clusterName); cl != nil {
+		return cl.(cluster.CommonCluster)
+	}
+	return nil
+}
+
+// GetClusterManager returns a cluster manager for running operations on the cluster of the current request
+func GetClusterManager(db *gorm.DB, logger logrus.FieldLogger) *cluster.Manager {
+	return cluster.NewManager(
+		cluster.NewRepositories(db),
+		providers.NewSecretValidator(secret.Store),
+		cluster.NewNopClusterEvents(),
+		config.PriceCatalog(),
+		logger,
+		config.ErrorHandler(),
+	)
+}
+
 func setVariableToContext(req *http.Request, key interface{}, val interface{}) *http.Request {
 
 	return req.WithContext(context.WithValue(req.Context(), key, val))
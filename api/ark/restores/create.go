@@ -15,14 +15,20 @@
 package restores
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/goph/emperror"
+	"github.com/pkg/errors"
 
 	"github.com/banzaicloud/pipeline/api/ark/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/config"
 	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 )
 
 // Create creates a new ARK restore
@@ -45,9 +51,33 @@ func Create(c *gin.Context) {
 		return
 	}
 
-	restore, err := common.GetARKService(c.Request).GetRestoresService().Create(req)
+	// restoring a backup must not run in parallel with other operations on the cluster
+	var restore *arkAPI.Restore
+	clusterManager := common.GetClusterManager(config.DB(), logger)
+	ctx := ginutils.Context(context.Background(), c)
+	err = clusterManager.RunClusterOperation(
+		ctx,
+		common.GetCluster(c.Request),
+		pkgCluster.OperationRestore,
+		auth.GetCurrentUser(c.Request).ID,
+		func() error {
+			var err error
+			restore, err = common.GetARKService(c.Request).GetRestoresService().Create(req)
+
+			return err
+		},
+	)
 	if err != nil {
 		logger.Error(emperror.Wrap(err, "could not create restore"))
+		if e, ok := errors.Cause(err).(*pkgCluster.OperationInProgressError); ok {
+			c.AbortWithStatusJSON(http.StatusConflict, pkgCluster.OperationConflictResponse{
+				Code:          http.StatusConflict,
+				Message:       e.Error(),
+				OperationID:   e.OperationID,
+				OperationType: e.OperationType,
+			})
+			return
+		}
 		common.ErrorResponse(c, err)
 		return
 	}
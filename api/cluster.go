@@ -25,13 +25,13 @@ import (
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/config"
-	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/k8sutil"
+	"github.com/banzaicloud/pipeline/pkg/pricing"
 	"github.com/banzaicloud/pipeline/pkg/providers"
 	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
 	"github.com/banzaicloud/pipeline/secret"
@@ -62,15 +62,22 @@ const (
 // ClusterAPI implements the Cluster API actions.
 type ClusterAPI struct {
 	clusterManager *cluster.Manager
+	prices         pricing.Source
 
 	logger       logrus.FieldLogger
 	errorHandler emperror.Handler
 }
 
 // NewClusterAPI returns a new ClusterAPI instance.
-func NewClusterAPI(clusterManager *cluster.Manager, logger logrus.FieldLogger, errorHandler emperror.Handler) *ClusterAPI {
+func NewClusterAPI(
+	clusterManager *cluster.Manager,
+	prices pricing.Source,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterAPI {
 	return &ClusterAPI{
 		clusterManager: clusterManager,
+		prices:         prices,
 
 		logger:       logger,
 		errorHandler: errorHandler,
@@ -85,9 +92,8 @@ func getClusterFromRequest(c *gin.Context) (cluster.CommonCluster, bool) {
 	logger := correlationid.Logger(log, c)
 
 	// TODO: move these to a struct and create them only once upon application init
-	clusters := intCluster.NewClusters(config.DB())
 	secretValidator := providers.NewSecretValidator(secret.Store)
-	clusterManager := cluster.NewManager(clusters, secretValidator, cluster.NewNopClusterEvents(), logger, errorHandler)
+	clusterManager := cluster.NewManager(cluster.NewRepositories(config.DB()), secretValidator, cluster.NewNopClusterEvents(), config.PriceCatalog(), logger, errorHandler)
 
 	ctx := ginutils.Context(context.Background(), c)
 
@@ -142,36 +148,39 @@ func getClusterFromRequest(c *gin.Context) (cluster.CommonCluster, bool) {
 	return cl, true
 }
 
-func getPostHookFunctions(postHooks pkgCluster.PostHooks) (ph []cluster.PostFunctioner) {
-
-	log.Info("Get posthook function(s)")
+// getPostHookFunctions returns the requested built-in and user-defined posthook functions.
+// It responds with an error and returns false if they cannot be resolved.
+func (a *ClusterAPI) getPostHookFunctions(c *gin.Context, organizationID uint, postHooks pkgCluster.PostHooks) ([]cluster.PostFunctioner, bool) {
+	ctx := ginutils.Context(context.Background(), c)
 
-	for postHookName, param := range postHooks {
+	functions, err := a.clusterManager.GetPostHookFunctions(ctx, organizationID, postHooks)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid posthooks",
+			Error:   err.Error(),
+		})
 
-		function := cluster.HookMap[postHookName]
-		if function != nil {
+		return nil, false
+	} else if err != nil {
+		a.errorHandler.Handle(err)
 
-			if f, isOk := function.(*cluster.PostFunctionWithParam); isOk {
-				fa := *f
-				fa.SetParams(param)
-				function = &fa
-			}
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting posthooks",
+			Error:   err.Error(),
+		})
 
-			log.Infof("posthook function: %s", function)
-			log.Infof("posthook params: %#v", param)
-			ph = append(ph, function)
-		} else {
-			log.Warnf("there's no function with this name [%s]", postHookName)
-		}
+		return nil, false
 	}
 
-	log.Infof("Found posthooks: %v", ph)
+	a.logger.Infof("Found posthooks: %v", functions)
 
-	return
+	return functions, true
 }
 
 // GetClusterStatus retrieves the cluster status
-func GetClusterStatus(c *gin.Context) {
+func (a *ClusterAPI) GetClusterStatus(c *gin.Context) {
 
 	commonCluster, ok := getClusterFromRequest(c)
 	if ok != true {
@@ -188,6 +197,16 @@ func GetClusterStatus(c *gin.Context) {
 		})
 		return
 	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	metadata, err := a.clusterManager.GetClusterMetadata(ctx, commonCluster.GetOrganizationId(), commonCluster.GetID())
+	if err != nil {
+		log.Errorf("Error during getting cluster metadata: %s", err.Error())
+	} else {
+		metadata.AddToStatus(response)
+	}
+
 	c.JSON(http.StatusOK, response)
 	return
 }
@@ -262,8 +281,18 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 
 	logger.Info("fetching clusters")
 
-	clusters, err := a.clusterManager.GetClusters(context.Background(), organizationID)
-	if err != nil {
+	ctx := ginutils.Context(context.Background(), c)
+
+	clusters, err := a.clusterManager.ListClusters(ctx, organizationID, c.Query("labelSelector"))
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid label selector",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
 		logger.Errorf("error listing clusters: %s", err.Error())
 
 		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
@@ -284,47 +313,15 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 		if err != nil {
 			//TODO we want skip or return error?
 			logger.Errorf("get cluster status failed: %s", err.Error())
-		} else {
-			response = append(response, *status)
+			continue
 		}
-	}
-
-	c.JSON(http.StatusOK, response)
-}
 
-// ReRunPostHooks handles {cluster_id}/posthooks API request
-func ReRunPostHooks(c *gin.Context) {
-
-	log.Info("Get common cluster")
-	commonCluster, ok := getClusterFromRequest(c)
-	if ok != true {
-		return
-	}
-
-	var ph pkgCluster.PostHooks
-	if err := c.BindJSON(&ph); err != nil {
-		log.Errorf("error during binding request: %s", err.Error())
-		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: "error during binding request",
-			Error:   err.Error(),
-		})
-		return
-	}
+		c.Metadata.AddToStatus(status)
 
-	var posthooks []cluster.PostFunctioner
-	if len(ph) == 0 {
-		posthooks = cluster.BasePostHookFunctions
-	} else {
-		posthooks = getPostHookFunctions(ph)
+		response = append(response, *status)
 	}
 
-	log.Infof("Cluster id: %d", commonCluster.GetID())
-	log.Infof("Run posthook(s): %v", posthooks)
-
-	go cluster.RunPostHooks(posthooks, commonCluster)
-
-	c.Status(http.StatusOK)
+	c.JSON(http.StatusOK, response)
 }
 
 // ClusterHEAD checks the cluster ready
@@ -808,7 +805,7 @@ func calculatePodsTotalRequestsAndLimits(podList []v1.Pod) (reqs map[v1.Resource
 }
 
 // InstallSecretsToCluster add all secrets from a repo to a cluster's namespace combined into one global secret named as the repo
-func InstallSecretsToCluster(c *gin.Context) {
+func (a *ClusterAPI) InstallSecretsToCluster(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
@@ -838,6 +835,15 @@ func InstallSecretsToCluster(c *gin.Context) {
 		return
 	}
 
+	for _, secretSource := range secretSources {
+		secretRequest := cluster.InstallSecretRequest{
+			SourceSecretName: secretSource.Name,
+			Namespace:        request.Namespace,
+		}
+
+		a.recordSecretInstallation(c, commonCluster, secretSource.Name, secretRequest, false)
+	}
+
 	c.JSON(http.StatusOK, secretSources)
 }
 
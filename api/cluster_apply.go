@@ -0,0 +1,291 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/ghodss/yaml"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// GetClusterSpec exports the stored spec of a cluster in the shape of a create request (YAML).
+func (a *ClusterAPI) GetClusterSpec(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	spec, err := cluster.GetClusterSpec(commonCluster)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting cluster spec",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	ctx := ginutils.Context(context.Background(), c)
+
+	spec.Labels, err = a.clusterManager.GetClusterLabels(ctx, organizationID, commonCluster.GetID())
+	if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting cluster labels",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	spec.DeletionProtection, err = a.clusterManager.GetClusterDeletionProtection(ctx, organizationID, commonCluster.GetID())
+	if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting cluster deletion protection",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	out, err := yaml.Marshal(spec)
+	if err != nil {
+		errorHandler.Handle(errors.Wrap(err, "could not marshal cluster spec"))
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error marshaling cluster spec",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/x-yaml; charset=utf-8", out)
+}
+
+// ApplyCluster creates a cluster by name if it is missing or updates it to match the requested spec.
+// The request body is a create request either in JSON or (with a YAML content type) in YAML.
+func (a *ClusterAPI) ApplyCluster(c *gin.Context) {
+	clusterName := c.Param("name")
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": organizationID,
+		"user":         userID,
+		"cluster":      clusterName,
+	})
+
+	spec, err := bindClusterSpec(c)
+	if err != nil {
+		logger.Errorf("error parsing request: %s", err.Error())
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if spec.Name == "" {
+		spec.Name = clusterName
+	} else if spec.Name != clusterName {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "cluster name in the request does not match the name in the path",
+		})
+		return
+	}
+
+	if spec.SecretId == "" && spec.SecretName != "" {
+		spec.SecretId = secret.GenerateSecretIDFromName(spec.SecretName)
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	commonCluster, err := a.clusterManager.GetClusterByName(ctx, organizationID, clusterName)
+	if isNotFound(err) {
+		if spec.SecretId == "" {
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "either secretId or secretName has to be set",
+			})
+			return
+		}
+
+		logger.Info("cluster does not exist, creating it")
+
+		postHooks, ok := a.getPostHookFunctions(c, organizationID, spec.PostHooks)
+		if !ok {
+			return
+		}
+
+		commonCluster, errResp := a.CreateCluster(ctx, spec, organizationID, userID, postHooks)
+		if errResp != nil {
+			c.JSON(errResp.Code, errResp)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, pkgCluster.ApplyClusterResponse{
+			Name:       commonCluster.GetName(),
+			ResourceID: commonCluster.GetID(),
+			Action:     pkgCluster.ApplyCreated,
+		})
+		return
+	} else if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	updateRequest, err := cluster.NewUpdateRequestFromSpec(commonCluster, spec)
+	if err != nil {
+		a.handleUpdateError(c, err)
+		return
+	}
+
+	labelsChanged := false
+	if spec.Labels != nil {
+		if err := cluster.ValidateLabels(spec.Labels); err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+
+		currentLabels, err := a.clusterManager.GetClusterLabels(ctx, organizationID, commonCluster.GetID())
+		if err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+
+		labelsChanged = !reflect.DeepEqual(currentLabels, spec.Labels)
+	}
+
+	// applying a spec can enable deletion protection, but disabling it requires an explicit update
+	enableDeletionProtection := false
+	if spec.DeletionProtection {
+		protected, err := a.clusterManager.GetClusterDeletionProtection(ctx, organizationID, commonCluster.GetID())
+		if err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+
+		enableDeletionProtection = !protected
+	}
+
+	updateCtx := cluster.UpdateContext{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		ClusterID:      commonCluster.GetID(),
+	}
+
+	updater := cluster.NewCommonClusterUpdater(updateRequest, commonCluster, userID)
+
+	err = a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
+	if isUnchanged(err) && !labelsChanged && !enableDeletionProtection {
+		logger.Info("cluster matches the requested spec")
+
+		c.JSON(http.StatusOK, pkgCluster.ApplyClusterResponse{
+			Name:       commonCluster.GetName(),
+			ResourceID: commonCluster.GetID(),
+			Action:     pkgCluster.ApplyUnchanged,
+		})
+		return
+	} else if err != nil && !isUnchanged(err) {
+		a.handleUpdateError(c, err)
+		return
+	}
+
+	if labelsChanged {
+		if err := a.clusterManager.SetClusterLabels(ctx, commonCluster, spec.Labels); err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+	}
+
+	if enableDeletionProtection {
+		if _, err := a.clusterManager.SetClusterDeletionProtection(ctx, commonCluster, true, userID); err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, pkgCluster.ApplyClusterResponse{
+		Name:       commonCluster.GetName(),
+		ResourceID: commonCluster.GetID(),
+		Action:     pkgCluster.ApplyUpdated,
+	})
+}
+
+// bindClusterSpec reads a create request from the request body either in JSON or in YAML.
+func bindClusterSpec(c *gin.Context) (*pkgCluster.CreateClusterRequest, error) {
+	var spec pkgCluster.CreateClusterRequest
+
+	if strings.Contains(c.ContentType(), "yaml") {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read request body")
+		}
+
+		if err := yaml.Unmarshal(body, &spec); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal YAML request")
+		}
+	} else if err := json.NewDecoder(c.Request.Body).Decode(&spec); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal JSON request")
+	}
+
+	if spec.Cloud == "" {
+		return nil, errors.New("cloud field is required")
+	}
+
+	if spec.Properties == nil {
+		return nil, errors.New("properties field is required")
+	}
+
+	return &spec, nil
+}
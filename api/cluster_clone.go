@@ -0,0 +1,97 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// CloneCluster creates a new cluster with the same shape as an existing one.
+func (a *ClusterAPI) CloneCluster(c *gin.Context) {
+	var request pkgCluster.CloneClusterRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+		"clone":        request.Name,
+	})
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	createClusterRequest, err := a.clusterManager.NewCloneRequest(ctx, commonCluster, &request)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid clone request",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		logger.Errorf("error creating clone request: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error creating clone request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	postHooks, ok := a.getPostHookFunctions(c, commonCluster.GetOrganizationId(), createClusterRequest.PostHooks)
+	if !ok {
+		return
+	}
+
+	clone, errResponse := a.CreateCluster(
+		ctx,
+		createClusterRequest,
+		commonCluster.GetOrganizationId(),
+		auth.GetCurrentUser(c.Request).ID,
+		postHooks,
+	)
+	if errResponse != nil {
+		c.JSON(errResponse.Code, errResponse)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, pkgCluster.CreateClusterResponse{
+		Name:       clone.GetName(),
+		ResourceID: clone.GetID(),
+	})
+}
@@ -30,6 +30,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// createClusterErrorResponse describes Pipeline's response when a cluster cannot be created,
+// including the exceeded limits when the cluster would exceed the quota of the organization
+type createClusterErrorResponse struct {
+	pkgCommon.ErrorResponse
+	Violations []pkgCluster.QuotaViolation `json:"violations,omitempty"`
+}
+
 //CreateClusterRequest gin handler
 func (a *ClusterAPI) CreateClusterRequest(c *gin.Context) {
 	a.logger.Info("Cluster creation started")
@@ -62,7 +69,11 @@ func (a *ClusterAPI) CreateClusterRequest(c *gin.Context) {
 	orgID := auth.GetCurrentOrganization(c.Request).ID
 	userID := auth.GetCurrentUser(c.Request).ID
 
-	ph := getPostHookFunctions(createClusterRequest.PostHooks)
+	ph, ok := a.getPostHookFunctions(c, orgID, createClusterRequest.PostHooks)
+	if !ok {
+		return
+	}
+
 	ctx := ginutils.Context(context.Background(), c)
 	commonCluster, err := a.CreateCluster(ctx, &createClusterRequest, orgID, userID, ph)
 	if err != nil {
@@ -83,7 +94,7 @@ func (a *ClusterAPI) CreateCluster(
 	organizationID uint,
 	userID uint,
 	postHooks []cluster.PostFunctioner,
-) (cluster.CommonCluster, *pkgCommon.ErrorResponse) {
+) (cluster.CommonCluster, *createClusterErrorResponse) {
 	logger := a.logger.WithFields(logrus.Fields{
 		"organization": organizationID,
 		"user":         userID,
@@ -96,13 +107,22 @@ func (a *ClusterAPI) CreateCluster(
 
 		logger.Info("fill data from profile")
 
-		profile, err := defaults.GetProfile(createClusterRequest.Cloud, createClusterRequest.ProfileName)
+		provider, err := defaults.GetCloudProfileProvider(createClusterRequest.Cloud)
 		if err != nil {
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "error during getting profile",
+				Error:   err.Error(),
+			}}
+		}
+
+		profile, err := provider.GetProfile(createClusterRequest.ProfileName)
+		if err != nil {
+			return nil, &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
 				Code:    http.StatusNotFound,
 				Message: "error during getting profile",
 				Error:   err.Error(),
-			}
+			}}
 		}
 
 		logger.Info("create profile response")
@@ -113,11 +133,11 @@ func (a *ClusterAPI) CreateCluster(
 		if err != nil {
 			logger.Errorf("error during getting cluster request from profile: %s", err.Error())
 
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
 				Code:    http.StatusBadRequest,
 				Message: "Error creating request from profile",
 				Error:   err.Error(),
-			}
+			}}
 		}
 
 		createClusterRequest = newRequest
@@ -132,11 +152,11 @@ func (a *ClusterAPI) CreateCluster(
 	commonCluster, err := cluster.CreateCommonClusterFromRequest(createClusterRequest, organizationID, userID)
 	if err != nil {
 		log.Errorf("error during create common cluster from request: %s", err.Error())
-		return nil, &pkgCommon.ErrorResponse{
+		return nil, &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 			Error:   err.Error(),
-		}
+		}}
 	}
 
 	creationCtx := cluster.CreationContext{
@@ -146,6 +166,11 @@ func (a *ClusterAPI) CreateCluster(
 		SecretID:       createClusterRequest.SecretId,
 		Provider:       createClusterRequest.Cloud,
 		PostHooks:      postHooks,
+		Labels:         createClusterRequest.Labels,
+		TTL:            createClusterRequest.TTL,
+		ExpiresAt:      createClusterRequest.ExpiresAt,
+
+		DeletionProtection: createClusterRequest.DeletionProtection,
 	}
 
 	creator := cluster.NewCommonClusterCreator(createClusterRequest, commonCluster)
@@ -155,19 +180,32 @@ func (a *ClusterAPI) CreateCluster(
 	if err == cluster.ErrAlreadyExists || isInvalid(err) {
 		logger.Debugf("invalid cluster creation: %s", err.Error())
 
-		return nil, &pkgCommon.ErrorResponse{
+		return nil, &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 			Error:   err.Error(),
+		}}
+	} else if isForbidden(err) {
+		logger.Debugf("cluster creation exceeds the organization quota: %s", err.Error())
+
+		response := &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: errors.Cause(err).Error(),
+		}}
+
+		if e, ok := errors.Cause(err).(*pkgCluster.QuotaExceededError); ok {
+			response.Violations = e.Violations
 		}
+
+		return nil, response
 	} else if err != nil {
 		logger.Errorf("error during cluster creation: %s", err.Error())
 
-		return nil, &pkgCommon.ErrorResponse{
+		return nil, &createClusterErrorResponse{ErrorResponse: pkgCommon.ErrorResponse{
 			Code:    http.StatusInternalServerError,
 			Message: err.Error(),
 			Error:   err.Error(),
-		}
+		}}
 	}
 
 	return commonCluster, nil
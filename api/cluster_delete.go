@@ -18,8 +18,10 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	"github.com/banzaicloud/pipeline/internal/security"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/gin-gonic/gin"
 )
 
@@ -45,7 +47,29 @@ func (a *ClusterAPI) DeleteCluster(c *gin.Context) {
 
 	ctx := ginutils.Context(c.Request.Context(), c)
 
-	a.clusterManager.DeleteCluster(ctx, commonCluster, force, &kubeProxyCache)
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	err := a.clusterManager.DeleteCluster(ctx, commonCluster, userID, force, &kubeProxyCache)
+	if isPreconditionFailed(err) {
+		c.JSON(http.StatusPreconditionFailed, pkgCommon.ErrorResponse{
+			Code:    http.StatusPreconditionFailed,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return
+	} else if isConflict(err) {
+		respondOperationConflict(c, err)
+		return
+	} else if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error deleting cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
 
 	anchore.RemoveAnchoreUser(commonCluster.GetOrganizationId(), commonCluster.GetUID())
 
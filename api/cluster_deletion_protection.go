@@ -0,0 +1,75 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/audit"
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+// deletionProtectionDisabledEvent is recorded in the audit log when the deletion protection of a cluster is disabled.
+type deletionProtectionDisabledEvent struct {
+	Event          string `json:"event"`
+	OrganizationID uint   `json:"organizationId"`
+	ClusterID      uint   `json:"clusterId"`
+	ClusterName    string `json:"clusterName"`
+}
+
+// setDeletionProtection enables or disables the deletion protection of a cluster
+// and records disabling it in the audit log.
+func (a *ClusterAPI) setDeletionProtection(
+	ctx context.Context,
+	c *gin.Context,
+	commonCluster cluster.CommonCluster,
+	enabled bool,
+	userID uint,
+) error {
+	changed, err := a.clusterManager.SetClusterDeletionProtection(ctx, commonCluster, enabled, userID)
+	if err != nil {
+		return err
+	}
+
+	if !changed || enabled {
+		return nil
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+		"user":         userID,
+	}).Warn("cluster deletion protection disabled")
+
+	event := deletionProtectionDisabledEvent{
+		Event:          "ClusterDeletionProtectionDisabled",
+		OrganizationID: commonCluster.GetOrganizationId(),
+		ClusterID:      commonCluster.GetID(),
+		ClusterName:    commonCluster.GetName(),
+	}
+
+	// the protection is already disabled at this point, so a failing audit log should not fail the request
+	err = audit.LogEvent(config.DB(), c, http.StatusAccepted, event)
+	if err != nil {
+		a.errorHandler.Handle(emperror.Wrap(err, "could not record disabling deletion protection in the audit log"))
+	}
+
+	return nil
+}
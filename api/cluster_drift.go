@@ -0,0 +1,96 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+)
+
+// GetClusterDrift returns the latest drift report of a cluster.
+func (a *ClusterAPI) GetClusterDrift(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	drift, err := a.clusterManager.GetClusterDrift(ctx, commonCluster)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting cluster drift",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, drift)
+}
+
+// CheckClusterDrift checks a cluster for drift immediately and returns the new drift report.
+func (a *ClusterAPI) CheckClusterDrift(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	drift, err := a.clusterManager.CheckClusterDrift(ctx, commonCluster, time.Now())
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error checking cluster drift",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, drift)
+}
+
+// CorrectClusterDrift applies the recorded spec of a cluster to the provider again.
+func (a *ClusterAPI) CorrectClusterDrift(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.CorrectClusterDrift(ctx, commonCluster, auth.GetCurrentUser(c.Request).ID)
+	if err != nil {
+		a.handleUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, UpdateClusterResponse{
+		Status: http.StatusAccepted,
+	})
+}
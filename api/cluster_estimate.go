@@ -0,0 +1,84 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// EstimateClusterCost estimates the hourly and monthly cost of a cluster to be created,
+// or of an existing cluster after applying an update request.
+func (a *ClusterAPI) EstimateClusterCost(c *gin.Context) {
+	// see the route registration for the reason of matching the path here
+	if c.Param("id") != "estimate" {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "not found",
+		})
+
+		return
+	}
+
+	var request pkgCluster.EstimateClusterRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	estimate, err := a.clusterManager.EstimateClusterCost(ctx, auth.GetCurrentOrganization(c.Request).ID, &request, a.prices)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+
+		return
+	} else if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "cluster not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error estimating cluster cost",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
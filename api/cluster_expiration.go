@@ -0,0 +1,111 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// SetClusterExpiration sets (or extends) the time when a cluster expires and gets deleted.
+func (a *ClusterAPI) SetClusterExpiration(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+	})
+
+	var request pkgCluster.ClusterExpirationRequest
+	if err := c.BindJSON(&request); err != nil {
+		logger.Errorf("error during binding request: %s", err.Error())
+
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error during binding request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	expiresAt, err := cluster.NewExpiration(request.TTL, request.ExpiresAt, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid cluster expiration",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if expiresAt == nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid cluster expiration",
+			Error:   "either ttl or expiresAt has to be set",
+		})
+
+		return
+	}
+
+	a.setClusterExpiration(c, commonCluster, expiresAt, logger)
+}
+
+// DeleteClusterExpiration removes the expiration of a cluster, so that it is not deleted automatically.
+func (a *ClusterAPI) DeleteClusterExpiration(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+	})
+
+	a.setClusterExpiration(c, commonCluster, nil, logger)
+}
+
+func (a *ClusterAPI) setClusterExpiration(c *gin.Context, commonCluster cluster.CommonCluster, expiresAt *time.Time, logger logrus.FieldLogger) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.SetClusterExpiration(ctx, commonCluster, expiresAt)
+	if err != nil {
+		logger.Errorf("error setting cluster expiration: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error setting cluster expiration",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, pkgCluster.ClusterExpirationResponse{
+		ExpiresAt: expiresAt,
+	})
+}
@@ -0,0 +1,121 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetClusterFeatures returns the features of a cluster and whether they are enabled.
+func (a *ClusterAPI) GetClusterFeatures(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	features, err := a.clusterManager.GetClusterFeatures(ctx, commonCluster)
+	if err != nil {
+		a.logger.WithFields(logrus.Fields{
+			"organization": commonCluster.GetOrganizationId(),
+			"cluster":      commonCluster.GetID(),
+		}).Errorf("error listing cluster features: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing cluster features",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, features)
+}
+
+// SetClusterFeature enables or disables a feature of a cluster.
+func (a *ClusterAPI) SetClusterFeature(c *gin.Context) {
+	var request pkgCluster.FeatureRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	feature := c.Param("feature")
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+		"feature":      feature,
+	})
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	logger.Infof("setting cluster feature enabled: %t", request.Enabled)
+
+	err := a.clusterManager.SetClusterFeature(ctx, commonCluster, feature, &request, auth.GetCurrentUser(c.Request).ID)
+	if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "feature not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "cannot set cluster feature",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if isConflict(err) {
+		respondOperationConflict(c, err)
+
+		return
+	} else if err != nil {
+		logger.Errorf("error setting cluster feature: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error setting cluster feature",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
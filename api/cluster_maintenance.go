@@ -0,0 +1,255 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ListOrganizationMaintenanceWindows lists the maintenance windows applying to the clusters of an organization
+// without maintenance windows of their own.
+func (a *ClusterAPI) ListOrganizationMaintenanceWindows(c *gin.Context) {
+	a.listMaintenanceWindows(c, auth.GetCurrentOrganization(c.Request).ID, 0)
+}
+
+// CreateOrganizationMaintenanceWindow adds a maintenance window to an organization.
+func (a *ClusterAPI) CreateOrganizationMaintenanceWindow(c *gin.Context) {
+	a.createMaintenanceWindow(c, auth.GetCurrentOrganization(c.Request).ID, 0)
+}
+
+// DeleteOrganizationMaintenanceWindow removes a maintenance window of an organization.
+func (a *ClusterAPI) DeleteOrganizationMaintenanceWindow(c *gin.Context) {
+	a.deleteMaintenanceWindow(c, auth.GetCurrentOrganization(c.Request).ID, 0)
+}
+
+// ListClusterMaintenanceWindows lists the maintenance windows of a cluster.
+func (a *ClusterAPI) ListClusterMaintenanceWindows(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	a.listMaintenanceWindows(c, commonCluster.GetOrganizationId(), commonCluster.GetID())
+}
+
+// CreateClusterMaintenanceWindow adds a maintenance window to a cluster.
+// The windows of a cluster take precedence over the windows of its organization.
+func (a *ClusterAPI) CreateClusterMaintenanceWindow(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	a.createMaintenanceWindow(c, commonCluster.GetOrganizationId(), commonCluster.GetID())
+}
+
+// DeleteClusterMaintenanceWindow removes a maintenance window of a cluster.
+func (a *ClusterAPI) DeleteClusterMaintenanceWindow(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	a.deleteMaintenanceWindow(c, commonCluster.GetOrganizationId(), commonCluster.GetID())
+}
+
+func (a *ClusterAPI) listMaintenanceWindows(c *gin.Context, organizationID uint, clusterID uint) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	windows, err := a.clusterManager.GetMaintenanceWindows(ctx, organizationID, clusterID)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing maintenance windows",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, windows)
+}
+
+func (a *ClusterAPI) createMaintenanceWindow(c *gin.Context, organizationID uint, clusterID uint) {
+	var request pkgCluster.MaintenanceWindowRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	window, err := a.clusterManager.CreateMaintenanceWindow(
+		ctx,
+		organizationID,
+		clusterID,
+		&request,
+		auth.GetCurrentUser(c.Request).ID,
+	)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error creating maintenance window",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, window)
+}
+
+func (a *ClusterAPI) deleteMaintenanceWindow(c *gin.Context, organizationID uint, clusterID uint) {
+	windowID, ok := ginutils.UintParam(c, "windowid")
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.DeleteMaintenanceWindow(ctx, organizationID, clusterID, windowID)
+	if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "maintenance window not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error deleting maintenance window",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListQueuedOperations lists the operations of a cluster queued until its next maintenance window.
+func (a *ClusterAPI) ListQueuedOperations(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	operations, err := a.clusterManager.GetQueuedOperations(ctx, commonCluster)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing queued cluster operations",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, operations)
+}
+
+// CancelQueuedOperation cancels an operation of a cluster still waiting for a maintenance window.
+func (a *ClusterAPI) CancelQueuedOperation(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	operationID, ok := ginutils.UintParam(c, "opid")
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.CancelQueuedOperation(ctx, commonCluster, operationID)
+	if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "queued cluster operation not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if isPreconditionFailed(err) {
+		c.JSON(http.StatusPreconditionFailed, pkgCommon.ErrorResponse{
+			Code:    http.StatusPreconditionFailed,
+			Message: errors.Cause(err).Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error cancelling queued cluster operation",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// isUrgent tells whether a disruptive operation should run right away, regardless of the maintenance windows.
+func isUrgent(c *gin.Context) (bool, bool) {
+	urgent, err := strconv.ParseBool(c.DefaultQuery("urgent", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid urgent parameter",
+			Error:   err.Error(),
+		})
+
+		return false, false
+	}
+
+	return urgent, true
+}
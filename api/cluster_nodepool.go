@@ -0,0 +1,238 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+)
+
+// ListNodePools lists the node pools of a cluster.
+func (a *ClusterAPI) ListNodePools(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting node pools",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	nodePools := status.NodePools
+	if nodePools == nil {
+		nodePools = make(map[string]*pkgCluster.NodePoolStatus)
+	}
+
+	c.JSON(http.StatusOK, nodePools)
+}
+
+// GetNodePool returns a single node pool of a cluster.
+func (a *ClusterAPI) GetNodePool(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	name := c.Param("name")
+
+	status, err := commonCluster.GetStatus()
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting node pool",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	nodePool, ok := status.NodePools[name]
+	if !ok {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("node pool [%s] not found", name),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, nodePool)
+}
+
+// CreateNodePool adds a new node pool to a cluster.
+func (a *ClusterAPI) CreateNodePool(c *gin.Context) {
+	var request pkgCluster.NodePoolRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	if commonCluster.NodePoolExists(request.Name) {
+		c.JSON(http.StatusConflict, pkgCommon.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("node pool [%s] already exists", request.Name),
+		})
+
+		return
+	}
+
+	a.updateNodePool(c, commonCluster, request.Name, &request)
+}
+
+// UpdateNodePool resizes a node pool of a cluster.
+func (a *ClusterAPI) UpdateNodePool(c *gin.Context) {
+	var request pkgCluster.NodePoolRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	name := c.Param("name")
+	if request.Name != "" && request.Name != name {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "node pool name in the request does not match the name in the path",
+		})
+
+		return
+	}
+
+	if !a.assertNodePoolExists(c, commonCluster, name) {
+		return
+	}
+
+	a.updateNodePool(c, commonCluster, name, &request)
+}
+
+// DeleteNodePool removes a node pool from a cluster.
+func (a *ClusterAPI) DeleteNodePool(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	name := c.Param("name")
+
+	if !a.assertNodePoolExists(c, commonCluster, name) {
+		return
+	}
+
+	a.updateNodePool(c, commonCluster, name, nil)
+}
+
+func (a *ClusterAPI) assertNodePoolExists(c *gin.Context, commonCluster cluster.CommonCluster, name string) bool {
+	if !commonCluster.NodePoolExists(name) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("node pool [%s] not found", name),
+		})
+
+		return false
+	}
+
+	return true
+}
+
+// updateNodePool updates a cluster with a single node pool being added, changed or removed (nil node pool).
+func (a *ClusterAPI) updateNodePool(
+	c *gin.Context,
+	commonCluster cluster.CommonCluster,
+	name string,
+	nodePool *pkgCluster.NodePoolRequest,
+) {
+	updateRequest, err := cluster.NewNodePoolUpdateRequest(commonCluster, name, nodePool)
+	if err != nil {
+		a.handleUpdateError(c, err)
+		return
+	}
+
+	updateCtx := cluster.UpdateContext{
+		OrganizationID: auth.GetCurrentOrganization(c.Request).ID,
+		UserID:         auth.GetCurrentUser(c.Request).ID,
+		ClusterID:      commonCluster.GetID(),
+	}
+
+	urgent, ok := isUrgent(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	// shrinking node pools outside the maintenance windows of the cluster is queued, unless it is urgent
+	if !urgent {
+		queued, err := a.clusterManager.QueueNodePoolUpdate(ctx, commonCluster, name, nodePool, updateCtx.UserID)
+		if err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+
+		if queued != nil {
+			c.JSON(http.StatusAccepted, queued)
+			return
+		}
+	}
+
+	updater := cluster.NewCommonClusterUpdater(updateRequest, commonCluster, updateCtx.UserID)
+
+	if err := a.clusterManager.UpdateCluster(ctx, updateCtx, updater); err != nil {
+		a.handleUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, pkgCluster.NodePoolResponse{
+		Status: http.StatusAccepted,
+		Name:   name,
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ListNodePoolSchedules lists the scaling schedules of a node pool.
+func (a *ClusterAPI) ListNodePoolSchedules(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	name := c.Param("name")
+
+	if !a.assertNodePoolExists(c, commonCluster, name) {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	schedules, err := a.clusterManager.GetNodePoolSchedules(ctx, commonCluster, name)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing node pool schedules",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// CreateNodePoolSchedule adds a scaling schedule to a node pool.
+func (a *ClusterAPI) CreateNodePoolSchedule(c *gin.Context) {
+	var request pkgCluster.NodePoolScheduleRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	name := c.Param("name")
+
+	if !a.assertNodePoolExists(c, commonCluster, name) {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	schedule, err := a.clusterManager.CreateNodePoolSchedule(ctx, commonCluster, name, &request, auth.GetCurrentUser(c.Request).ID)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error creating node pool schedule",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// DeleteNodePoolSchedule removes a scaling schedule of a node pool.
+func (a *ClusterAPI) DeleteNodePoolSchedule(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	scheduleID, ok := ginutils.UintParam(c, "scheduleid")
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.DeleteNodePoolSchedule(ctx, commonCluster, c.Param("name"), scheduleID)
+	if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "node pool schedule not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error deleting node pool schedule",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,105 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ListOperations lists the create/update/delete operations of a cluster.
+// The cluster is looked up by ID only, so that operations of deleted clusters remain available.
+func (a *ClusterAPI) ListOperations(c *gin.Context) {
+	clusterID, ok := ginutils.UintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": organizationID,
+		"cluster":      clusterID,
+	})
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	operations, err := a.clusterManager.GetOperations(ctx, organizationID, clusterID)
+	if err != nil {
+		logger.Errorf("error listing cluster operations: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing cluster operations",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, operations)
+}
+
+// GetOperation returns a single operation of a cluster with its step log.
+func (a *ClusterAPI) GetOperation(c *gin.Context) {
+	clusterID, ok := ginutils.UintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	operationID, ok := ginutils.UintParam(c, "opid")
+	if !ok {
+		return
+	}
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": organizationID,
+		"cluster":      clusterID,
+		"operation":    operationID,
+	})
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	operation, err := a.clusterManager.GetOperation(ctx, organizationID, clusterID, operationID)
+	if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "cluster operation not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		logger.Errorf("error getting cluster operation: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting cluster operation",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, operation)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetPostHooks returns the posthook states of a cluster.
+func (a *ClusterAPI) GetPostHooks(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+	})
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHooks, err := a.clusterManager.GetPostHooks(ctx, commonCluster)
+	if err != nil {
+		logger.Errorf("error listing cluster posthooks: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing cluster posthooks",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, postHooks)
+}
+
+// ReRunPostHooks handles {cluster_id}/posthooks API request.
+// With the resume query parameter set the posthooks which have not succeeded yet are run again,
+// otherwise the posthooks in the request body (or all base posthooks if there are none) are run.
+func (a *ClusterAPI) ReRunPostHooks(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"cluster":      commonCluster.GetID(),
+	})
+
+	resume, err := strconv.ParseBool(c.DefaultQuery("resume", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid resume parameter",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	if resume {
+		logger.Info("resuming posthooks")
+
+		err = a.clusterManager.ResumePostHooks(ctx, commonCluster, auth.GetCurrentUser(c.Request).ID)
+	} else {
+		var ph pkgCluster.PostHooks
+		if err := c.BindJSON(&ph); err != nil {
+			logger.Errorf("error during binding request: %s", err.Error())
+
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "error during binding request",
+				Error:   err.Error(),
+			})
+
+			return
+		}
+
+		posthooks, ok := a.getPostHookFunctions(c, commonCluster.GetOrganizationId(), ph)
+		if !ok {
+			return
+		}
+
+		if len(ph) != 0 && len(posthooks) == 0 {
+			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "none of the requested posthooks exist",
+				Error:   "none of the requested posthooks exist",
+			})
+
+			return
+		}
+
+		logger.Infof("run posthook(s): %v", posthooks)
+
+		err = a.clusterManager.ReRunPostHooks(ctx, commonCluster, posthooks, auth.GetCurrentUser(c.Request).ID)
+	}
+
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "cannot run posthooks",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if isConflict(err) {
+		respondOperationConflict(c, err)
+
+		return
+	} else if err != nil {
+		logger.Errorf("error running posthooks: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error running posthooks",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
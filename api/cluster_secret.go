@@ -15,6 +15,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/banzaicloud/pipeline/cluster"
@@ -44,7 +45,7 @@ type InstallSecretResponse struct {
 }
 
 // InstallSecretToCluster installs a particular secret to a cluster's namespace.
-func InstallSecretToCluster(c *gin.Context) {
+func (a *ClusterAPI) InstallSecretToCluster(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
@@ -118,6 +119,8 @@ func InstallSecretToCluster(c *gin.Context) {
 		return
 	}
 
+	a.recordSecretInstallation(c, commonCluster, secretName, secretRequest, false)
+
 	response := InstallSecretResponse{
 		Name:     secretName,
 		Sourcing: string(secretSource.Sourcing),
@@ -127,7 +130,7 @@ func InstallSecretToCluster(c *gin.Context) {
 }
 
 // MergeSecretInCluster installs a particular secret to a cluster's namespace.
-func MergeSecretInCluster(c *gin.Context) {
+func (a *ClusterAPI) MergeSecretInCluster(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
@@ -201,6 +204,8 @@ func MergeSecretInCluster(c *gin.Context) {
 		return
 	}
 
+	a.recordSecretInstallation(c, commonCluster, secretName, secretRequest, true)
+
 	response := InstallSecretResponse{
 		Name:     secretName,
 		Sourcing: string(secretSource.Sourcing),
@@ -208,3 +213,25 @@ func MergeSecretInCluster(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+// recordSecretInstallation records an installed secret so that rotating the secret updates it in the cluster as well.
+// Failing to record the installation does not fail the request.
+func (a *ClusterAPI) recordSecretInstallation(
+	c *gin.Context,
+	commonCluster cluster.CommonCluster,
+	secretName string,
+	secretRequest cluster.InstallSecretRequest,
+	merge bool,
+) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.RecordSecretInstallation(ctx, commonCluster, secretName, secretRequest, merge)
+	if err != nil {
+		a.errorHandler.Handle(emperror.With(
+			emperror.Wrap(err, "failed to record secret installation"),
+			"clusterId", commonCluster.GetID(),
+			"organizationId", commonCluster.GetOrganizationId(),
+			"secret", secretName,
+		))
+	}
+}
@@ -17,6 +17,7 @@ package api
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
@@ -56,39 +57,142 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 		ClusterID:      commonCluster.GetID(),
 	}
 
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid dryRun parameter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	urgent, ok := isUrgent(c)
+	if !ok {
+		return
+	}
+
 	updater := cluster.NewCommonClusterUpdater(updateRequest, commonCluster, updateCtx.UserID)
 
 	ctx := ginutils.Context(context.Background(), c)
 
-	err := a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
-	if err != nil {
-		if isInvalid(err) {
-			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
-				Code:    http.StatusBadRequest,
-				Message: errors.Cause(err).Error(),
-			})
+	if dryRun {
+		plan, err := a.clusterManager.PlanClusterUpdate(ctx, updateCtx, updater)
+		if err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
 
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	if updateRequest.Labels != nil {
+		if err := cluster.ValidateLabels(updateRequest.Labels); err != nil {
+			a.handleUpdateError(c, err)
 			return
-		} else if isPreconditionFailed(err) {
-			c.JSON(http.StatusPreconditionFailed, pkgCommon.ErrorResponse{
-				Code:    http.StatusPreconditionFailed,
-				Message: errors.Cause(err).Error(),
-			})
+		}
+	}
+
+	// labels and deletion protection can be updated without updating the cluster itself
+	metadataUpdate := updateRequest.Labels != nil || updateRequest.DeletionProtection != nil
+
+	// updates submitted outside the maintenance windows of the cluster are queued, unless they are urgent
+	var queued *pkgCluster.QueuedOperationResponse
 
+	if !metadataUpdate || !updateRequest.UpdateProperties.IsEmpty() {
+		if !urgent {
+			queued, err = a.clusterManager.QueueClusterUpdate(ctx, commonCluster, updateRequest, updateCtx.UserID)
+		}
+
+		if queued == nil && err == nil {
+			err = a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
+		}
+
+		if isUnchanged(err) && metadataUpdate {
+			// only the metadata is updated
+		} else if err != nil {
+			a.handleUpdateError(c, err)
 			return
-		} else {
-			errorHandler.Handle(err)
+		}
+	}
 
-			c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
-				Code:    http.StatusInternalServerError,
-				Message: "cluster update failed",
-			})
+	if updateRequest.Labels != nil {
+		if err := a.clusterManager.SetClusterLabels(ctx, commonCluster, updateRequest.Labels); err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+	}
 
+	if updateRequest.DeletionProtection != nil {
+		err := a.setDeletionProtection(ctx, c, commonCluster, *updateRequest.DeletionProtection, updateCtx.UserID)
+		if err != nil {
+			a.handleUpdateError(c, err)
 			return
 		}
 	}
 
+	if queued != nil {
+		c.JSON(http.StatusAccepted, queued)
+		return
+	}
+
 	c.JSON(http.StatusAccepted, UpdateClusterResponse{
 		Status: http.StatusAccepted,
 	})
 }
+
+// handleUpdateError responds with the status matching the error of a cluster update.
+func (a *ClusterAPI) handleUpdateError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+	} else if isPreconditionFailed(err) {
+		c.JSON(http.StatusPreconditionFailed, pkgCommon.ErrorResponse{
+			Code:    http.StatusPreconditionFailed,
+			Message: errors.Cause(err).Error(),
+		})
+	} else if isConflict(err) {
+		respondOperationConflict(c, err)
+	} else if isForbidden(err) {
+		respondQuotaExceeded(c, err)
+	} else {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "cluster update failed",
+		})
+	}
+}
+
+// respondOperationConflict responds with 409 and the ID of the operation in progress on the cluster.
+func respondOperationConflict(c *gin.Context, err error) {
+	response := pkgCluster.OperationConflictResponse{
+		Code:    http.StatusConflict,
+		Message: errors.Cause(err).Error(),
+	}
+
+	if e, ok := errors.Cause(err).(*pkgCluster.OperationInProgressError); ok {
+		response.OperationID = e.OperationID
+		response.OperationType = e.OperationType
+	}
+
+	c.JSON(http.StatusConflict, response)
+}
+
+// respondQuotaExceeded responds with 403 and the limits of the organization quota the operation would exceed.
+func respondQuotaExceeded(c *gin.Context, err error) {
+	response := pkgCluster.QuotaExceededResponse{
+		Code:    http.StatusForbidden,
+		Message: errors.Cause(err).Error(),
+	}
+
+	if e, ok := errors.Cause(err).(*pkgCluster.QuotaExceededError); ok {
+		response.Violations = e.Violations
+	}
+
+	c.JSON(http.StatusForbidden, response)
+}
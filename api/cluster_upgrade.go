@@ -0,0 +1,78 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+)
+
+// UpgradeCluster upgrades the Kubernetes version of a cluster.
+func (a *ClusterAPI) UpgradeCluster(c *gin.Context) {
+	var request pkgCluster.UpgradeClusterRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if ok != true {
+		return
+	}
+
+	urgent, ok := isUrgent(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	userID := auth.GetCurrentUser(c.Request).ID
+
+	if !urgent {
+		queued, err := a.clusterManager.QueueClusterUpgrade(ctx, commonCluster, request.Version, userID)
+		if err != nil {
+			a.handleUpdateError(c, err)
+			return
+		}
+
+		if queued != nil {
+			c.JSON(http.StatusAccepted, queued)
+			return
+		}
+	}
+
+	err := a.clusterManager.UpgradeCluster(ctx, commonCluster, request.Version, userID)
+	if err != nil {
+		a.handleUpdateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, pkgCluster.UpgradeClusterResponse{
+		Status:  http.StatusAccepted,
+		Version: request.Version,
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ListCustomPostHooks returns the user-defined posthooks of the current organization.
+func (a *ClusterAPI) ListCustomPostHooks(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHooks, err := a.clusterManager.GetCustomPostHooks(ctx, auth.GetCurrentOrganization(c.Request).ID)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing posthooks",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, postHooks)
+}
+
+// GetCustomPostHook returns a user-defined posthook of the current organization.
+func (a *ClusterAPI) GetCustomPostHook(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHook, err := a.clusterManager.GetCustomPostHook(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("name"))
+	if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "posthook not found",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting posthook",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, postHook)
+}
+
+// CreateCustomPostHook adds a user-defined posthook to the current organization.
+func (a *ClusterAPI) CreateCustomPostHook(c *gin.Context) {
+	var request pkgCluster.CustomPostHook
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHook, err := a.clusterManager.CreateCustomPostHook(
+		ctx,
+		auth.GetCurrentOrganization(c.Request).ID,
+		&request,
+		auth.GetCurrentUser(c.Request).ID,
+	)
+	if err != nil {
+		a.handleCustomPostHookError(c, err, "error creating posthook")
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, postHook)
+}
+
+// UpdateCustomPostHook replaces the definition of a user-defined posthook of the current organization.
+func (a *ClusterAPI) UpdateCustomPostHook(c *gin.Context) {
+	var request pkgCluster.CustomPostHook
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	request.Name = c.Param("name")
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHook, err := a.clusterManager.UpdateCustomPostHook(ctx, auth.GetCurrentOrganization(c.Request).ID, &request)
+	if err != nil {
+		a.handleCustomPostHookError(c, err, "error updating posthook")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, postHook)
+}
+
+// DeleteCustomPostHook removes a user-defined posthook of the current organization.
+func (a *ClusterAPI) DeleteCustomPostHook(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.DeleteCustomPostHook(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("name"))
+	if err != nil {
+		a.handleCustomPostHookError(c, err, "error deleting posthook")
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a *ClusterAPI) handleCustomPostHookError(c *gin.Context, err error, message string) {
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+	} else if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "posthook not found",
+			Error:   err.Error(),
+		})
+	} else if isConflict(err) {
+		c.JSON(http.StatusConflict, pkgCommon.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: errors.Cause(err).Error(),
+		})
+	} else {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -70,3 +70,45 @@ func isPreconditionFailed(err error) bool {
 
 	return false
 }
+
+// isConflict checks whether an error is about a conflicting operation being in progress.
+func isConflict(err error) bool {
+	// Check the root cause error.
+	err = errors.Cause(err)
+
+	if e, ok := err.(interface {
+		Conflict() bool
+	}); ok {
+		return e.Conflict()
+	}
+
+	return false
+}
+
+// isUnchanged checks whether an error is about an update request not changing anything.
+func isUnchanged(err error) bool {
+	// Check the root cause error.
+	err = errors.Cause(err)
+
+	if e, ok := err.(interface {
+		Unchanged() bool
+	}); ok {
+		return e.Unchanged()
+	}
+
+	return false
+}
+
+// isForbidden checks whether an error is about an operation not being allowed (eg. exceeding a quota).
+func isForbidden(err error) bool {
+	// Check the root cause error.
+	err = errors.Cause(err)
+
+	if e, ok := err.(interface {
+		Forbidden() bool
+	}); ok {
+		return e.Forbidden()
+	}
+
+	return false
+}
@@ -21,8 +21,6 @@ import (
 	"github.com/banzaicloud/pipeline/model/defaults"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
-	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
-	oracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/model"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 )
@@ -132,27 +130,16 @@ func getProfiles(distribution string) ([]pkgCluster.ClusterProfileResponse, erro
 // convertRequestToProfile converts a ClusterProfileRequest into ClusterProfile
 func convertRequestToProfile(request *pkgCluster.ClusterProfileRequest) (defaults.ClusterProfile, error) {
 
-	switch request.Cloud {
-	case pkgCluster.Amazon:
-		var eksProfile defaults.EKSProfile
-		eksProfile.UpdateProfile(request, false)
-		return &eksProfile, nil
-	case pkgCluster.Azure:
-		var aksProfile defaults.AKSProfile
-		aksProfile.UpdateProfile(request, false)
-		return &aksProfile, nil
-	case pkgCluster.Google:
-		var gkeProfile defaults.GKEProfile
-		gkeProfile.UpdateProfile(request, false)
-		return &gkeProfile, nil
-	case pkgCluster.Oracle:
-		var okeProfile oracle.Profile
-		okeProfile.UpdateProfile(request, false)
-		return &okeProfile, nil
-	default:
-		return nil, pkgErrors.ErrorNotSupportedCloudType
+	provider, err := defaults.GetCloudProfileProvider(request.Cloud)
+	if err != nil {
+		return nil, err
 	}
 
+	profile := provider.NewProfile()
+	profile.UpdateProfile(request, false)
+
+	return profile, nil
+
 }
 
 // UpdateClusterProfile handles /cluster/profiles/:type PUT api endpoint.
@@ -187,17 +174,8 @@ func UpdateClusterProfile(c *gin.Context) {
 
 	log.Infof("Load cluster from database: %s[%s]", profileRequest.Name, profileRequest.Cloud)
 
-	distribution := ""
-	switch profileRequest.Cloud {
-	case pkgCluster.Amazon:
-		distribution = pkgCluster.EKS
-	case pkgCluster.Azure:
-		distribution = pkgCluster.AKS
-	case pkgCluster.Google:
-		distribution = pkgCluster.GKE
-	case pkgCluster.Oracle:
-		distribution = pkgCluster.OKE
-	default:
+	provider, err := defaults.GetCloudProfileProvider(profileRequest.Cloud)
+	if err != nil {
 		log.Infoln("Not supported cloud type", profileRequest.Cloud)
 		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
 			Code:    http.StatusBadRequest,
@@ -208,7 +186,7 @@ func UpdateClusterProfile(c *gin.Context) {
 	}
 
 	// load cluster profile from database
-	if profile, err := defaults.GetProfile(distribution, profileRequest.Name); err != nil {
+	if profile, err := provider.GetProfile(profileRequest.Name); err != nil {
 		// load from db failed
 		log.Error(errors.Wrap(err, "Error during getting profile"))
 		sendBackGetProfileErrorResponse(c, err)
@@ -0,0 +1,159 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// GetQuota returns the quota of the current organization and the resources used by its clusters.
+func (a *ClusterAPI) GetQuota(c *gin.Context) {
+	a.getQuota(c, auth.GetCurrentOrganization(c.Request).ID)
+}
+
+// GetOrganizationQuota returns the quota of an organization (admin only).
+func (a *ClusterAPI) GetOrganizationQuota(c *gin.Context) {
+	organizationID, ok := getQuotaOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	a.getQuota(c, organizationID)
+}
+
+func (a *ClusterAPI) getQuota(c *gin.Context, organizationID uint) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	quota, err := a.clusterManager.GetQuota(ctx, organizationID)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting organization quota",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// SetOrganizationQuota replaces the quota of an organization (admin only).
+func (a *ClusterAPI) SetOrganizationQuota(c *gin.Context) {
+	organizationID, ok := getQuotaOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	var request pkgCluster.Quota
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	quota, err := a.clusterManager.SetQuota(ctx, organizationID, &request, auth.GetCurrentUser(c.Request).ID)
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error updating organization quota",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// DeleteOrganizationQuota removes the quota of an organization (admin only).
+func (a *ClusterAPI) DeleteOrganizationQuota(c *gin.Context) {
+	organizationID, ok := getQuotaOrganizationID(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	if err := a.clusterManager.DeleteQuota(ctx, organizationID); err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error deleting organization quota",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getQuotaOrganizationID returns the ID of the organization in the path of an admin request.
+func getQuotaOrganizationID(c *gin.Context) (uint, bool) {
+	organizationID, ok := ginutils.UintParam(c, "orgid")
+	if !ok {
+		return 0, false
+	}
+
+	_, err := auth.GetOrganizationById(organizationID)
+	if gorm.IsRecordNotFoundError(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "organization not found",
+		})
+
+		return 0, false
+	} else if err != nil {
+		errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error getting organization",
+			Error:   err.Error(),
+		})
+
+		return 0, false
+	}
+
+	return organizationID, true
+}
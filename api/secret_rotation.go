@@ -0,0 +1,152 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetSecretRotationPolicy returns the rotation policy of a secret.
+func (a *ClusterAPI) GetSecretRotationPolicy(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	policy, err := a.clusterManager.GetSecretRotationPolicy(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("id"))
+	if err != nil {
+		a.handleSecretRotationError(c, err, "error getting secret rotation policy")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// SetSecretRotationPolicy creates or updates the rotation policy of a secret.
+func (a *ClusterAPI) SetSecretRotationPolicy(c *gin.Context) {
+	var request pkgCluster.SecretRotationPolicy
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "error parsing request",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	policy, err := a.clusterManager.SetSecretRotationPolicy(
+		ctx,
+		auth.GetCurrentOrganization(c.Request).ID,
+		c.Param("id"),
+		&request,
+		auth.GetCurrentUser(c.Request).ID,
+	)
+	if err != nil {
+		a.handleSecretRotationError(c, err, "error saving secret rotation policy")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteSecretRotationPolicy deletes the rotation policy of a secret.
+func (a *ClusterAPI) DeleteSecretRotationPolicy(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.clusterManager.DeleteSecretRotationPolicy(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("id"))
+	if err != nil {
+		a.handleSecretRotationError(c, err, "error deleting secret rotation policy")
+
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RotateSecret regenerates the values of a secret and updates them in the clusters the secret is installed to.
+func (a *ClusterAPI) RotateSecret(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	user := auth.GetCurrentUser(c.Request)
+
+	rotation, err := a.clusterManager.RotateSecret(
+		ctx,
+		auth.GetCurrentOrganization(c.Request).ID,
+		c.Param("id"),
+		user.ID,
+		user.Login,
+	)
+	if err != nil {
+		a.handleSecretRotationError(c, err, "error rotating secret")
+
+		return
+	}
+
+	c.JSON(http.StatusAccepted, rotation)
+}
+
+// ListSecretRotations returns the rotations of a secret, the latest first.
+func (a *ClusterAPI) ListSecretRotations(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	rotations, err := a.clusterManager.GetSecretRotations(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("id"))
+	if err != nil {
+		a.handleSecretRotationError(c, err, "error listing secret rotations")
+
+		return
+	}
+
+	c.JSON(http.StatusOK, rotations)
+}
+
+func (a *ClusterAPI) handleSecretRotationError(c *gin.Context, err error, message string) {
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+		})
+	} else if errors.Cause(err) == secret.ErrSecretNotExists {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "secret not found",
+			Error:   err.Error(),
+		})
+	} else if isNotFound(err) {
+		c.JSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "secret rotation policy not found",
+			Error:   err.Error(),
+		})
+	} else {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSecretVersions returns the versions of a secret, the latest first
+func ListSecretVersions(c *gin.Context) {
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	secretID := c.Param("id")
+
+	versions, err := secret.RestrictedStore.ListVersions(organizationID, secretID)
+	if err != nil {
+		handleSecretVersionError(c, err, "Error during listing secret versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetSecretVersion returns a version of a secret
+func GetSecretVersion(c *gin.Context) {
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	secretID := c.Param("id")
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid secret version",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	secretItem, err := secret.RestrictedStore.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		handleSecretVersionError(c, err, "Error during getting secret version")
+		return
+	}
+
+	c.JSON(http.StatusOK, secretItem)
+}
+
+// RollbackSecret restores a previous version of a secret as its new version
+func RollbackSecret(c *gin.Context) {
+
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	secretID := c.Param("id")
+
+	var request secret.RollbackSecretRequest
+	if err := c.ShouldBind(&request); err != nil {
+		log.Errorf("Error during binding RollbackSecretRequest: %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error during binding",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	user := auth.GetCurrentUser(c.Request)
+
+	log.Infof("Rollback secret %d/%s to version %d by %s", organizationID, secretID, request.Version, user.Login)
+
+	if err := secret.RestrictedStore.Rollback(organizationID, secretID, request.Version, user.Login); err != nil {
+		handleSecretVersionError(c, err, "Error during rollback")
+		return
+	}
+
+	s, err := secret.RestrictedStore.Get(organizationID, secretID)
+	if err != nil {
+		log.Errorf("error during getting secret: %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, secret.CreateSecretResponse{
+		Name:      s.Name,
+		Type:      s.Type,
+		ID:        secretID,
+		UpdatedAt: s.UpdatedAt,
+		UpdatedBy: s.UpdatedBy,
+		Version:   s.Version,
+	})
+}
+
+func handleSecretVersionError(c *gin.Context, err error, message string) {
+	log.Errorf("%s: %s", message, err.Error())
+
+	statusCode := http.StatusInternalServerError
+
+	switch err.(type) {
+	case secret.ReadOnlyError, secret.ForbiddenError:
+		statusCode = http.StatusBadRequest
+	}
+
+	if err == secret.ErrSecretNotExists || err == secret.ErrSecretVersionNotExists {
+		statusCode = http.StatusNotFound
+	} else if secret.IsCASError(err) {
+		statusCode = http.StatusBadRequest
+	}
+
+	c.AbortWithStatusJSON(statusCode, common.ErrorResponse{
+		Code:    statusCode,
+		Message: message,
+		Error:   err.Error(),
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/casbin/casbin"
 	"github.com/casbin/gorm-adapter"
 	"github.com/gin-gonic/gin"
@@ -95,6 +96,28 @@ func (a *userIDAuthorizer) RequirePermission(c *gin.Context) {
 	c.AbortWithStatus(http.StatusForbidden)
 }
 
+// IsAdmin tells whether a user is a Pipeline administrator.
+func IsAdmin(user *User) bool {
+	if user == nil || user.Virtual {
+		return false
+	}
+
+	for _, login := range viper.GetStringSlice(config.AuthAdmins) {
+		if login == user.Login {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequireAdmin returns 403 Forbidden to the client unless the current user is a Pipeline administrator.
+func RequireAdmin(c *gin.Context) {
+	if !IsAdmin(GetCurrentUser(c.Request)) {
+		c.AbortWithStatus(http.StatusForbidden)
+	}
+}
+
 func addDefaultPolicies() {
 	basePath := viper.GetString("pipeline.basepath")
 	enforcer.AddPolicy("default", basePath+"/api/v1/allowed/secrets", "*")
@@ -102,6 +125,8 @@ func addDefaultPolicies() {
 	enforcer.AddPolicy("default", basePath+"/api/v1/orgs", "*")
 	enforcer.AddPolicy("default", basePath+"/api/v1/token", "*")
 	enforcer.AddPolicy("default", basePath+"/api/v1/tokens", "*")
+	// admin endpoints are authorized by RequireAdmin
+	enforcer.AddPolicy("default", basePath+"/api/v1/admin/*", "*")
 	enforcer.AddPolicy("defaultVirtual", basePath+"/api/v1/orgs", "GET")
 }
 
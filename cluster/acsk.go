@@ -28,6 +28,7 @@ import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/cs"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	"github.com/banzaicloud/pipeline/pkg/cluster/acsk"
@@ -173,6 +174,54 @@ func CreateACSKClusterFromModel(clusterModel *model.ClusterModel) (*ACSKCluster,
 	return &alibabaCluster, nil
 }
 
+// loadACSKClusterFromModel creates the cluster from the model and loads its Alibaba properties from the database
+func loadACSKClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	alibabaCluster, err := CreateACSKClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	db := config.DB()
+
+	log.Debug("Load Alibaba props from database")
+	err = db.Where(model.ACSKClusterModel{ID: alibabaCluster.modelCluster.ID}).First(&alibabaCluster.modelCluster.ACSK).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(&alibabaCluster.modelCluster.ACSK).Related(&alibabaCluster.modelCluster.ACSK.NodePools, "NodePools").Error
+	if err != nil {
+		return nil, err
+	}
+
+	return alibabaCluster, nil
+}
+
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Alibaba,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateACSKClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadACSKClusterFromModel,
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterACSK.Validate()
+		},
+		ValidateUpdate: func(request *pkgCluster.UpdateClusterRequest) error {
+			return request.ACSK.Validate()
+		},
+		GetSpec:          getACSKSpec,
+		UpdateFromSpec:   updateACSKFromSpec,
+		SetNodePool:      setACSKNodePool,
+		OverrideNodePool: overrideACSKNodePool,
+	})
+}
+
 func CreateACSKClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId, userId uint) (*ACSKCluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
 	cluster := ACSKCluster{
@@ -438,7 +487,10 @@ func (c *ACSKCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error)
 	for _, np := range c.modelCluster.ACSK.NodePools {
 		if np != nil {
 			nodePools[np.Name] = &pkgCluster.NodePoolStatus{
+				Autoscaling:  false,
 				Count:        np.Count,
+				MinCount:     np.Count,
+				MaxCount:     np.Count,
 				InstanceType: np.InstanceType,
 			}
 		}
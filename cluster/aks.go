@@ -42,6 +42,35 @@ const (
 	poolNameKey = "poolName"
 )
 
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Azure,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateAKSClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadAKSClusterFromModel,
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterAKS.Validate()
+		},
+		ValidateUpdate: func(request *pkgCluster.UpdateClusterRequest) error {
+			return request.AKS.Validate()
+		},
+		KubernetesVersions: func(cluster CommonCluster) ([]string, error) {
+			return GetKubernetesVersion(cluster.GetOrganizationId(), cluster.GetSecretId(), cluster.GetLocation())
+		},
+		GetSpec:          getAKSSpec,
+		UpdateFromSpec:   updateAKSFromSpec,
+		PlanUpdate:       planAKSUpdate,
+		SetNodePool:      setAKSNodePool,
+		OverrideNodePool: overrideAKSNodePool,
+	})
+}
+
 //CreateAKSClusterFromRequest creates ClusterModel struct from the request
 func CreateAKSClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId, userId uint) (*AKSCluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
@@ -58,6 +87,7 @@ func CreateAKSClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId
 				NodeMaxCount:     np.MaxCount,
 				Count:            np.Count,
 				NodeInstanceType: np.NodeInstanceType,
+				Version:          request.Properties.CreateClusterAKS.KubernetesVersion,
 			})
 		}
 	}
@@ -254,6 +284,7 @@ func (c *AKSCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
 				InstanceType: np.NodeInstanceType,
 				MinCount:     np.NodeMinCount,
 				MaxCount:     np.NodeMaxCount,
+				Version:      c.getNodePoolVersion(np),
 			}
 		}
 	}
@@ -348,6 +379,7 @@ func (c *AKSCluster) UpdateCluster(request *pkgCluster.UpdateClusterRequest, use
 					NodeMaxCount:     np.MaxCount,
 					Count:            np.Count,
 					NodeInstanceType: existNodePool.NodeInstanceType,
+					Version:          existNodePool.Version,
 				})
 
 				updatedCluster, err = c.updateWithPolling(client, &ccr)
@@ -402,6 +434,104 @@ func (c *AKSCluster) updateWithPolling(manager azureClient.ClusterManager, ccr *
 	return updatedCluster, nil
 }
 
+// getNodePoolVersion returns the Kubernetes version of a node pool,
+// node pools stored without a version run the version of the cluster
+func (c *AKSCluster) getNodePoolVersion(nodePool *model.AKSNodePoolModel) string {
+	if nodePool.Version != "" {
+		return nodePool.Version
+	}
+
+	return c.modelCluster.AKS.KubernetesVersion
+}
+
+// UpgradeControlPlane upgrades the cluster to the given Kubernetes version.
+// Azure needs at least one agent pool in the request, so the first agent pool is upgraded along with the control plane,
+// the rest of the agent pools are upgraded one by one by UpgradeNodePool.
+func (c *AKSCluster) UpgradeControlPlane(version string) error {
+	var first *model.AKSNodePoolModel
+	for _, np := range c.modelCluster.AKS.NodePools {
+		if np == nil {
+			continue
+		}
+
+		// the versions of the agent pools must be kept, they are no longer the same as the version of the cluster
+		np.Version = c.getNodePoolVersion(np)
+
+		if first == nil {
+			first = np
+		}
+	}
+
+	if first == nil {
+		return errors.New("there is no agent pool to upgrade the control plane with")
+	}
+
+	if err := c.upgradeAgentPool(first, version); err != nil {
+		return err
+	}
+
+	c.modelCluster.AKS.KubernetesVersion = version
+
+	return nil
+}
+
+// UpgradeNodePool upgrades a single agent pool of the cluster to the given Kubernetes version
+func (c *AKSCluster) UpgradeNodePool(name string, version string) error {
+	nodePool := c.getExistingNodePoolByName(name)
+	if nodePool == nil {
+		return errors.Errorf("there's no nodepool with this name[%s]", name)
+	}
+
+	return c.upgradeAgentPool(nodePool, version)
+}
+
+// upgradeAgentPool sends the given Kubernetes version to Azure with a single agent pool,
+// because Azure not supports multiple nodepool modification.
+// The stored node pool is updated in place, so its autoscaling settings are kept.
+func (c *AKSCluster) upgradeAgentPool(nodePool *model.AKSNodePoolModel, version string) error {
+	client, err := c.GetAKSClient()
+	if err != nil {
+		return err
+	}
+
+	client.With(log)
+
+	clusterSshSecret, err := c.getSshSecret(c)
+	if err != nil {
+		return err
+	}
+
+	sshKey := secret.NewSSHKeyPair(clusterSshSecret)
+
+	name := nodePool.Name
+	count := int32(nodePool.Count)
+
+	ccr := azureCluster.CreateClusterRequest{
+		Name:              c.modelCluster.Name,
+		Location:          c.modelCluster.Location,
+		ResourceGroup:     c.modelCluster.AKS.ResourceGroup,
+		KubernetesVersion: version,
+		SSHPubKey:         sshKey.PublicKeyData,
+		Profiles: []containerservice.AgentPoolProfile{
+			{
+				Name:   &name,
+				Count:  &count,
+				VMSize: containerservice.VMSizeTypes(nodePool.NodeInstanceType),
+			},
+		},
+	}
+
+	updatedCluster, err := c.updateWithPolling(client, &ccr)
+	if err != nil {
+		return err
+	}
+
+	nodePool.Version = version
+	c.azureCluster = &updatedCluster.Value
+
+	return nil
+}
+
 //GetID returns the specified cluster id
 func (c *AKSCluster) GetID() uint {
 	return c.modelCluster.ID
@@ -439,6 +569,25 @@ func CreateAKSClusterFromModel(clusterModel *model.ClusterModel) (*AKSCluster, e
 	return &aksCluster, nil
 }
 
+// loadAKSClusterFromModel creates the cluster from the model and loads its Azure properties from the database
+func loadAKSClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	aksCluster, err := CreateAKSClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	db := config.DB()
+
+	log.Debug("Load Azure props from database")
+	err = db.Where(model.AKSClusterModel{ID: aksCluster.modelCluster.ID}).First(&aksCluster.modelCluster.AKS).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Model(&aksCluster.modelCluster.AKS).Related(&aksCluster.modelCluster.AKS.NodePools, "NodePools").Error
+
+	return aksCluster, err
+}
+
 //AddDefaultsToUpdate adds defaults to update request
 func (c *AKSCluster) AddDefaultsToUpdate(r *pkgCluster.UpdateClusterRequest) {
 
@@ -209,7 +209,8 @@ func createAutoscalingForAzure(cluster CommonCluster, groups []nodeGroup) *autos
 	}
 }
 
-//DeployClusterAutoscaler post hook only for AWS & EKS & Azure for now
+//DeployClusterAutoscaler post hook deploys the cluster autoscaler for EC2, EKS & AKS clusters,
+// GKE node pools are autoscaled natively while ACSK & OKE node pools don't support autoscaling
 func DeployClusterAutoscaler(cluster CommonCluster) error {
 
 	var nodeGroups []nodeGroup
@@ -221,7 +222,11 @@ func DeployClusterAutoscaler(cluster CommonCluster) error {
 		nodeGroups, err = getAmazonNodeGroups(cluster)
 	case pkgCluster.Azure:
 		nodeGroups, err = getAzureNodeGroups(cluster)
+	case pkgCluster.Google:
+		// autoscaling is set on the GKE node pools themselves, see createNodePoolsFromClusterModel
+		return nil
 	default:
+		// autoscaling is rejected during validation for the rest of the providers
 		return nil
 	}
 
@@ -22,12 +22,10 @@ import (
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/config"
-	"github.com/banzaicloud/pipeline/internal/platform/database"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
-	modelOracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/model"
 	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
 	"github.com/banzaicloud/pipeline/secret"
 	"github.com/banzaicloud/pipeline/utils"
@@ -204,190 +202,37 @@ func getSecret(organizationId uint, secretId string) (*secret.SecretItemResponse
 
 // GetCommonClusterFromModel extracts CommonCluster from a ClusterModel
 func GetCommonClusterFromModel(modelCluster *model.ClusterModel) (CommonCluster, error) {
-
-	db := config.DB()
-
-	cloudType := modelCluster.Cloud
-	switch cloudType {
-	case pkgCluster.Alibaba:
-		//Create Alibaba struct
-		alibabaCluster, err := CreateACSKClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debug("Load Alibaba props from database")
-		err = db.Where(model.ACSKClusterModel{ID: alibabaCluster.modelCluster.ID}).First(&alibabaCluster.modelCluster.ACSK).Error
-		if err != nil {
-			return nil, err
-		}
-
-		err = db.Model(&alibabaCluster.modelCluster.ACSK).Related(&alibabaCluster.modelCluster.ACSK.NodePools, "NodePools").Error
-		if err != nil {
-			return nil, err
-		}
-
-		return alibabaCluster, nil
-
-	case pkgCluster.Amazon:
-		//Create Amazon EKS struct
-		eksCluster, err := CreateEKSClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debug("Load EKS props from database")
-		err = db.Where(model.EKSClusterModel{ID: eksCluster.modelCluster.ID}).First(&eksCluster.modelCluster.EKS).Error
-		if err != nil {
-			return nil, err
-		}
-		err = db.Model(&eksCluster.modelCluster.EKS).Related(&eksCluster.modelCluster.EKS.NodePools, "NodePools").Error
-
-		return eksCluster, err
-
-	case pkgCluster.Azure:
-		// Create Azure struct
-		aksCluster, err := CreateAKSClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debug("Load Azure props from database")
-		err = db.Where(model.AKSClusterModel{ID: aksCluster.modelCluster.ID}).First(&aksCluster.modelCluster.AKS).Error
-		if err != nil {
-			return nil, err
-		}
-		err = db.Model(&aksCluster.modelCluster.AKS).Related(&aksCluster.modelCluster.AKS.NodePools, "NodePools").Error
-
-		return aksCluster, err
-
-	case pkgCluster.Google:
-		// Create Google struct
-		gkeCluster, err := CreateGKEClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		return gkeCluster, err
-
-	case pkgCluster.Dummy:
-		dummyCluster, err := CreateDummyClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debug("Load Dummy props from database")
-		err = db.Where(model.DummyClusterModel{ID: dummyCluster.modelCluster.ID}).First(&dummyCluster.modelCluster.Dummy).Error
-
-		return dummyCluster, err
-
-	case pkgCluster.Kubernetes:
-		// Create Kubernetes struct
-		kubernetesCluster, err := CreateKubernetesClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debug("Load Kubernetes props from database")
-		err = db.Where(model.KubernetesClusterModel{ID: kubernetesCluster.modelCluster.ID}).First(&kubernetesCluster.modelCluster.Kubernetes).Error
-		if database.IsRecordNotFoundError(err) {
-			// metadata not set so there's no properties in DB
-			log.Warnf(err.Error())
-			err = nil
-		}
-
-		return kubernetesCluster, err
-
-	case pkgCluster.Oracle:
-		// Create Oracle struct
-		okeCluster, err := CreateOKEClusterFromModel(modelCluster)
-		if err != nil {
-			return nil, err
-		}
-
-		log.Debug("Load Oracle props from database")
-		err = db.Where(modelOracle.Cluster{ClusterModelID: okeCluster.modelCluster.ID}).Preload("NodePools.Subnets").Preload("NodePools.Labels").First(&okeCluster.modelCluster.OKE).Error
-
-		return okeCluster, err
+	distribution, err := GetDistribution(modelCluster.Cloud)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, pkgErrors.ErrorNotSupportedCloudType
+	return distribution.CreateFromModel(modelCluster)
 }
 
 //CreateCommonClusterFromRequest creates a CommonCluster from a request
 func CreateCommonClusterFromRequest(createClusterRequest *pkgCluster.CreateClusterRequest, orgId, userId uint) (CommonCluster, error) {
-
-	if err := createClusterRequest.AddDefaults(); err != nil {
-		return nil, err
-	}
-
-	// validate request
-	if err := createClusterRequest.Validate(); err != nil {
+	distribution, err := GetDistribution(createClusterRequest.Cloud)
+	if err != nil {
 		return nil, err
 	}
 
-	cloudType := createClusterRequest.Cloud
-	switch cloudType {
-	case pkgCluster.Alibaba:
-		//Create Alibaba struct
-		alibabaCluster, err := CreateACSKClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
-			return nil, err
-		}
-		return alibabaCluster, nil
-
-	case pkgCluster.Amazon:
-		//Create EKS struct
-		eksCluster, err := CreateEKSClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
+	if distribution.AddDefaults != nil {
+		if err := distribution.AddDefaults(createClusterRequest); err != nil {
 			return nil, err
 		}
-		return eksCluster, nil
-
-	case pkgCluster.Azure:
-		// Create AKS struct
-		aksCluster, err := CreateAKSClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
-			return nil, err
-		}
-		return aksCluster, nil
-
-	case pkgCluster.Google:
-		// Create GKE struct
-		gkeCluster, err := CreateGKEClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
-			return nil, err
-		}
-		return gkeCluster, nil
-
-	case pkgCluster.Dummy:
-		// Create Dummy struct
-		dummy, err := CreateDummyClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
-			return nil, err
-		}
-
-		return dummy, nil
-
-	case pkgCluster.Kubernetes:
-		// Create Kubernetes struct
-		kubeCluster, err := CreateKubernetesClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
-			return nil, err
-		}
-		return kubeCluster, nil
+	}
 
-	case pkgCluster.Oracle:
-		// Create OKE struct
-		okeCluster, err := CreateOKEClusterFromRequest(createClusterRequest, orgId, userId)
-		if err != nil {
-			return nil, err
-		}
-		return okeCluster, nil
+	// validate request
+	if err := createClusterRequest.ValidateMainFields(); err != nil {
+		return nil, err
+	}
 
+	if err := distribution.Validate(createClusterRequest); err != nil {
+		return nil, err
 	}
 
-	return nil, pkgErrors.ErrorNotSupportedCloudType
+	return distribution.CreateFromRequest(createClusterRequest, orgId, userId)
 }
 
 // CleanStateStore deletes state store folder by cluster name
@@ -0,0 +1,101 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/spf13/viper"
+)
+
+// CustomPostFunction describes a user-defined posthook of an organization which installs a Helm chart
+type CustomPostFunction struct {
+	postHook *pkgCluster.CustomPostHook
+	params   pkgCluster.PostHookParam
+	ErrorHandler
+}
+
+// NewCustomPostFunction returns a posthook function installing the chart of a user-defined posthook.
+// It checks that every required param of the posthook is given.
+func NewCustomPostFunction(postHook *pkgCluster.CustomPostHook, params pkgCluster.PostHookParam) (*CustomPostFunction, error) {
+	values, err := pkgCluster.CustomPostHookParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postHook.CheckParams(values); err != nil {
+		return nil, err
+	}
+
+	return &CustomPostFunction{
+		postHook: postHook,
+		params:   params,
+	}, nil
+}
+
+// Do renders the values of the posthook and installs its chart on the cluster
+func (p *CustomPostFunction) Do(cluster CommonCluster) error {
+	params, err := pkgCluster.CustomPostHookParams(p.params)
+	if err != nil {
+		return err
+	}
+
+	values, err := p.postHook.RenderValues(pkgCluster.CustomPostHookValues{
+		Cluster: pkgCluster.CustomPostHookCluster{
+			ID:           cluster.GetID(),
+			UID:          cluster.GetUID(),
+			Name:         cluster.GetName(),
+			Cloud:        cluster.GetCloud(),
+			Distribution: cluster.GetDistribution(),
+			Location:     cluster.GetLocation(),
+		},
+		Params: params,
+	})
+	if err != nil {
+		return emperror.With(err, "posthook", p.postHook.Name)
+	}
+
+	namespace := p.postHook.Namespace
+	if namespace == "" {
+		namespace = viper.GetString(pipConfig.PipelineSystemNamespace)
+	}
+
+	chartName := fmt.Sprintf("%s/%s", p.postHook.Repository, p.postHook.Chart)
+
+	return installDeployment(cluster, namespace, chartName, p.postHook.Name, values, p.postHook.Name, p.postHook.Version)
+}
+
+func (p *CustomPostFunction) String() string {
+	return p.postHook.Name
+}
+
+// GetName returns the name of the user-defined posthook
+func (p *CustomPostFunction) GetName() string {
+	return p.postHook.Name
+}
+
+// GetDependencies returns the names of the posthooks which have to succeed before the posthook can run.
+// User-defined posthooks always run after Helm is installed.
+func (p *CustomPostFunction) GetDependencies() []string {
+	return append([]string{pkgCluster.InstallHelmPostHook}, p.postHook.DependsOn...)
+}
+
+// GetParams returns posthook params
+func (p *CustomPostFunction) GetParams() pkgCluster.PostHookParam {
+	return p.params
+}
@@ -0,0 +1,110 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/banzaicloud/pipeline/model"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
+)
+
+// Distribution describes how the clusters of a Kubernetes distribution are created, loaded and validated.
+// Distributions register themselves by the cloud type their clusters are stored with,
+// so that supporting a new distribution does not require changing the code dispatching on cloud types.
+type Distribution struct {
+	// Cloud is the cloud type of the clusters of the distribution
+	Cloud string
+
+	// CreateFromRequest creates a cluster from a create request
+	CreateFromRequest func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error)
+
+	// CreateFromModel creates a cluster from a stored cluster and loads its distribution specific properties
+	CreateFromModel func(clusterModel *model.ClusterModel) (CommonCluster, error)
+
+	// AddDefaults puts default values to the optional distribution specific fields of a create request (optional)
+	AddDefaults func(request *pkgCluster.CreateClusterRequest) error
+
+	// Validate checks the distribution specific fields of a create request
+	Validate func(request *pkgCluster.CreateClusterRequest) error
+
+	// ValidateUpdate checks the distribution specific fields of an update request (clusters cannot be updated without it)
+	ValidateUpdate func(request *pkgCluster.UpdateClusterRequest) error
+
+	// KubernetesVersions returns the Kubernetes versions reported by cloud info for the location of a cluster
+	// (the Kubernetes version of clusters cannot be upgraded without it)
+	KubernetesVersions func(cluster CommonCluster) ([]string, error)
+
+	// GetSpec exports the distribution specific properties of a cluster in the shape of a create request
+	// (the spec of clusters cannot be exported, applied, cloned or have its node pools managed without it)
+	GetSpec func(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error)
+
+	// UpdateFromSpec sets the distribution specific fields of an update request from the properties of a spec
+	// (specs cannot be applied to clusters without it)
+	UpdateFromSpec func(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error
+
+	// PlanUpdate describes what the provider would do when updating a cluster with an update request
+	// (the node pools of update plans are not diffed without it)
+	PlanUpdate func(cluster CommonCluster, request *pkgCluster.UpdateClusterRequest) (*pkgCluster.UpdatePlanResponse, error)
+
+	// SetNodePool adds, changes or removes (when the node pool is nil) a node pool in the properties of a spec
+	// (node pools of clusters cannot be managed one by one without it)
+	SetNodePool func(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error
+
+	// OverrideNodePool overrides the fields set in a node pool override in the properties of a cloned spec
+	// (node pools of cloned clusters cannot be overridden without it)
+	OverrideNodePool func(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error
+}
+
+var (
+	distributionsMu sync.RWMutex
+	distributions   = make(map[string]Distribution)
+)
+
+// RegisterDistribution makes a distribution available by its cloud type.
+// It panics if a distribution is registered twice for the same cloud type or its constructors are missing.
+func RegisterDistribution(distribution Distribution) {
+	distributionsMu.Lock()
+	defer distributionsMu.Unlock()
+
+	if distribution.Cloud == "" {
+		panic("cluster: distribution cloud type is empty")
+	}
+
+	if distribution.CreateFromRequest == nil || distribution.CreateFromModel == nil || distribution.Validate == nil {
+		panic(fmt.Sprintf("cluster: constructors or validation of the %s distribution are missing", distribution.Cloud))
+	}
+
+	if _, ok := distributions[distribution.Cloud]; ok {
+		panic(fmt.Sprintf("cluster: distribution of %s is registered twice", distribution.Cloud))
+	}
+
+	distributions[distribution.Cloud] = distribution
+}
+
+// GetDistribution returns the distribution registered for a cloud type.
+func GetDistribution(cloud string) (Distribution, error) {
+	distributionsMu.RLock()
+	defer distributionsMu.RUnlock()
+
+	distribution, ok := distributions[cloud]
+	if !ok {
+		return Distribution{}, pkgErrors.ErrorNotSupportedCloudType
+	}
+
+	return distribution, nil
+}
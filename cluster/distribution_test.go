@@ -0,0 +1,67 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster_test
+
+import (
+	"testing"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
+)
+
+func TestGetDistribution(t *testing.T) {
+	clouds := []string{
+		pkgCluster.Alibaba,
+		pkgCluster.Amazon,
+		pkgCluster.Azure,
+		pkgCluster.Google,
+		pkgCluster.Dummy,
+		pkgCluster.Kubernetes,
+		pkgCluster.Oracle,
+	}
+
+	for _, cloud := range clouds {
+		t.Run(cloud, func(t *testing.T) {
+			distribution, err := cluster.GetDistribution(cloud)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if distribution.Cloud != cloud {
+				t.Fatalf("expected distribution of %s, got %s", cloud, distribution.Cloud)
+			}
+		})
+	}
+
+	if _, err := cluster.GetDistribution("baremetal"); err != pkgErrors.ErrorNotSupportedCloudType {
+		t.Fatalf("expected not supported cloud type error, got %v", err)
+	}
+}
+
+func TestRegisterDistributionTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering a distribution twice should panic")
+		}
+	}()
+
+	distribution, err := cluster.GetDistribution(pkgCluster.Dummy)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	cluster.RegisterDistribution(distribution)
+}
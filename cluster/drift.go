@@ -0,0 +1,192 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"k8s.io/api/core/v1"
+)
+
+const nodeInstanceTypeLabel = "beta.kubernetes.io/instance-type"
+
+// observedCluster describes the state of a cluster as reported by the provider and by its Kubernetes nodes.
+type observedCluster struct {
+	Ready         bool
+	MasterVersion string
+	Nodes         []v1.Node
+}
+
+// observedNodePool collects the nodes of a node pool.
+type observedNodePool struct {
+	count         int
+	instanceTypes map[string]bool
+	versions      map[string]bool
+}
+
+// detectDrift compares the recorded state of a cluster with the observed one.
+// Node pools are only compared when the nodes are labeled with their node pool names.
+func detectDrift(status *pkgCluster.GetClusterStatusResponse, observed observedCluster) []pkgCluster.DriftFinding {
+	var findings []pkgCluster.DriftFinding
+
+	if !observed.Ready {
+		return append(findings, pkgCluster.DriftFinding{
+			Field:    pkgCluster.DriftFieldStatus,
+			Expected: status.Status,
+			Actual:   "NOT_READY",
+		})
+	}
+
+	if observed.MasterVersion != "" && status.Version != "" && !sameVersion(observed.MasterVersion, status.Version) {
+		findings = append(findings, pkgCluster.DriftFinding{
+			Field:    pkgCluster.DriftFieldVersion,
+			Expected: status.Version,
+			Actual:   observed.MasterVersion,
+		})
+	}
+
+	nodePools := make(map[string]*observedNodePool)
+	for _, node := range observed.Nodes {
+		name, ok := node.Labels[pkgCommon.LabelKey]
+		if !ok {
+			continue
+		}
+
+		nodePool, ok := nodePools[name]
+		if !ok {
+			nodePool = &observedNodePool{
+				instanceTypes: make(map[string]bool),
+				versions:      make(map[string]bool),
+			}
+			nodePools[name] = nodePool
+		}
+
+		nodePool.count++
+		if instanceType := node.Labels[nodeInstanceTypeLabel]; instanceType != "" {
+			nodePool.instanceTypes[instanceType] = true
+		}
+		if version := node.Status.NodeInfo.KubeletVersion; version != "" {
+			nodePool.versions[version] = true
+		}
+	}
+
+	if len(nodePools) == 0 {
+		return findings
+	}
+
+	names := make([]string, 0, len(status.NodePools))
+	for name := range status.NodePools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		recorded := status.NodePools[name]
+		if recorded == nil {
+			continue
+		}
+
+		nodePool, ok := nodePools[name]
+		if !ok {
+			nodePool = &observedNodePool{}
+		}
+
+		if recorded.Autoscaling {
+			if nodePool.count < recorded.MinCount || nodePool.count > recorded.MaxCount {
+				findings = append(findings, pkgCluster.DriftFinding{
+					NodePool: name,
+					Field:    pkgCluster.DriftFieldCount,
+					Expected: fmt.Sprintf("%d-%d", recorded.MinCount, recorded.MaxCount),
+					Actual:   strconv.Itoa(nodePool.count),
+				})
+			}
+		} else if nodePool.count != recorded.Count {
+			findings = append(findings, pkgCluster.DriftFinding{
+				NodePool: name,
+				Field:    pkgCluster.DriftFieldCount,
+				Expected: strconv.Itoa(recorded.Count),
+				Actual:   strconv.Itoa(nodePool.count),
+			})
+		}
+
+		if recorded.InstanceType != "" && len(nodePool.instanceTypes) > 0 {
+			if len(nodePool.instanceTypes) > 1 || !nodePool.instanceTypes[recorded.InstanceType] {
+				findings = append(findings, pkgCluster.DriftFinding{
+					NodePool: name,
+					Field:    pkgCluster.DriftFieldInstanceType,
+					Expected: recorded.InstanceType,
+					Actual:   joinKeys(nodePool.instanceTypes),
+				})
+			}
+		}
+
+		if recorded.Version != "" && len(nodePool.versions) > 0 {
+			drifted := false
+			for version := range nodePool.versions {
+				if !sameVersion(version, recorded.Version) {
+					drifted = true
+				}
+			}
+
+			if drifted {
+				findings = append(findings, pkgCluster.DriftFinding{
+					NodePool: name,
+					Field:    pkgCluster.DriftFieldVersion,
+					Expected: recorded.Version,
+					Actual:   joinKeys(nodePool.versions),
+				})
+			}
+		}
+	}
+
+	unknown := make([]string, 0)
+	for name := range nodePools {
+		if _, ok := status.NodePools[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		findings = append(findings, pkgCluster.DriftFinding{
+			NodePool: name,
+			Field:    pkgCluster.DriftFieldNodePool,
+			Expected: "absent",
+			Actual:   fmt.Sprintf("%d nodes", nodePools[name].count),
+		})
+	}
+
+	return findings
+}
+
+// sameVersion compares Kubernetes versions regardless of the "v" prefix.
+func sameVersion(a string, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+func joinKeys(m map[string]bool) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return strings.Join(keys, ",")
+}
@@ -0,0 +1,123 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDriftTestNode(nodePool string, instanceType string, version string) v1.Node {
+	return v1.Node{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Labels: map[string]string{
+				pkgCommon.LabelKey:    nodePool,
+				nodeInstanceTypeLabel: instanceType,
+			},
+		},
+		Status: v1.NodeStatus{
+			NodeInfo: v1.NodeSystemInfo{KubeletVersion: version},
+		},
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+	status := &pkgCluster.GetClusterStatusResponse{
+		Status:  pkgCluster.Running,
+		Version: "1.11.2",
+		NodePools: map[string]*pkgCluster.NodePoolStatus{
+			"pool1": {Count: 2, InstanceType: "n1-standard-2", Version: "1.11.2"},
+			"pool2": {Autoscaling: true, MinCount: 1, MaxCount: 3, InstanceType: "n1-standard-4", Version: "1.11.2"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		observed observedCluster
+		fields   []string
+	}{
+		{
+			name:     "not ready",
+			observed: observedCluster{},
+			fields:   []string{pkgCluster.DriftFieldStatus},
+		},
+		{
+			name: "no drift",
+			observed: observedCluster{
+				Ready:         true,
+				MasterVersion: "v1.11.2",
+				Nodes: []v1.Node{
+					newDriftTestNode("pool1", "n1-standard-2", "v1.11.2"),
+					newDriftTestNode("pool1", "n1-standard-2", "v1.11.2"),
+					newDriftTestNode("pool2", "n1-standard-4", "v1.11.2"),
+				},
+			},
+		},
+		{
+			name: "unlabeled nodes",
+			observed: observedCluster{
+				Ready:         true,
+				MasterVersion: "1.11.2",
+				Nodes:         []v1.Node{{}},
+			},
+		},
+		{
+			name: "master version",
+			observed: observedCluster{
+				Ready:         true,
+				MasterVersion: "1.10.7",
+			},
+			fields: []string{pkgCluster.DriftFieldVersion},
+		},
+		{
+			name: "node pools",
+			observed: observedCluster{
+				Ready:         true,
+				MasterVersion: "1.11.2",
+				Nodes: []v1.Node{
+					newDriftTestNode("pool1", "n1-standard-8", "v1.10.7"),
+					newDriftTestNode("pool3", "n1-standard-2", "v1.11.2"),
+				},
+			},
+			fields: []string{
+				pkgCluster.DriftFieldCount,
+				pkgCluster.DriftFieldInstanceType,
+				pkgCluster.DriftFieldVersion,
+				pkgCluster.DriftFieldCount,
+				pkgCluster.DriftFieldNodePool,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings := detectDrift(status, test.observed)
+
+			if len(findings) != len(test.fields) {
+				t.Fatalf("expected %d findings, got: %+v", len(test.fields), findings)
+			}
+
+			for i, finding := range findings {
+				if finding.Field != test.fields[i] {
+					t.Errorf("expected finding %d in %s, got: %+v", i, test.fields[i], finding)
+				}
+			}
+		})
+	}
+}
@@ -15,24 +15,79 @@
 package cluster
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/dummy"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/secret"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
+// dummyKubernetesVersions are the Kubernetes versions dummy clusters can be upgraded to by default
+var dummyKubernetesVersions = []string{"1.9.11", "1.10.11", "1.11.5", "1.12.3"}
+
+// dummyPhase is a simulated phase of a dummy cluster operation
+type dummyPhase struct {
+	message string
+	apply   func()
+}
+
 // DummyCluster struct for DC
 type DummyCluster struct {
 	modelCluster *model.ClusterModel
 	APIEndpoint  string
 }
 
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Dummy,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateDummyClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadDummyClusterFromModel,
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterDummy.Validate()
+		},
+		ValidateUpdate: func(request *pkgCluster.UpdateClusterRequest) error {
+			return request.Dummy.Validate()
+		},
+		KubernetesVersions: func(cluster CommonCluster) ([]string, error) {
+			return cluster.(*DummyCluster).GetKubernetesVersions()
+		},
+		GetSpec:          getDummySpec,
+		UpdateFromSpec:   updateDummyFromSpec,
+		SetNodePool:      setDummyNodePool,
+		OverrideNodePool: overrideDummyNodePool,
+	})
+}
+
 // CreateDummyClusterFromRequest creates ClusterModel struct from the request
 func CreateDummyClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId, userId uint) (*DummyCluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
 	var cluster DummyCluster
 
+	nodePools, err := json.Marshal(request.Properties.CreateClusterDummy.NodePools)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not encode node pools")
+	}
+
+	simulation, err := json.Marshal(request.Properties.CreateClusterDummy.Simulation)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not encode simulation")
+	}
+
 	cluster.modelCluster = &model.ClusterModel{
 		Name:           request.Name,
 		Location:       request.Location,
@@ -44,14 +99,133 @@ func CreateDummyClusterFromRequest(request *pkgCluster.CreateClusterRequest, org
 		Dummy: model.DummyClusterModel{
 			KubernetesVersion: request.Properties.CreateClusterDummy.Node.KubernetesVersion,
 			NodeCount:         request.Properties.CreateClusterDummy.Node.Count,
+			NodePools:         string(nodePools),
+			Simulation:        string(simulation),
 		},
 	}
 	return &cluster, nil
 }
 
+// getNodePools returns the simulated node pools of the cluster.
+// Clusters created before node pools were simulated have a single node pool.
+func (c *DummyCluster) getNodePools() (map[string]*dummy.NodePool, error) {
+	nodePools := make(map[string]*dummy.NodePool)
+
+	if c.modelCluster.Dummy.NodePools == "" || c.modelCluster.Dummy.NodePools == "null" {
+		nodePools[dummy.DefaultNodePoolName] = &dummy.NodePool{
+			Count:   c.modelCluster.Dummy.NodeCount,
+			Version: c.modelCluster.Dummy.KubernetesVersion,
+		}
+
+		return nodePools, nil
+	}
+
+	if err := json.Unmarshal([]byte(c.modelCluster.Dummy.NodePools), &nodePools); err != nil {
+		return nil, emperror.Wrap(err, "could not decode node pools")
+	}
+
+	return nodePools, nil
+}
+
+// setNodePools stores the simulated node pools of the cluster in the model.
+func (c *DummyCluster) setNodePools(nodePools map[string]*dummy.NodePool) error {
+	data, err := json.Marshal(nodePools)
+	if err != nil {
+		return emperror.Wrap(err, "could not encode node pools")
+	}
+
+	c.modelCluster.Dummy.NodePools = string(data)
+	c.modelCluster.Dummy.NodeCount = dummy.NodeCount(nodePools)
+
+	return nil
+}
+
+// getSimulation returns the simulation settings of the cluster (or nil).
+func (c *DummyCluster) getSimulation() (*dummy.Simulation, error) {
+	if c.modelCluster.Dummy.Simulation == "" || c.modelCluster.Dummy.Simulation == "null" {
+		return nil, nil
+	}
+
+	var simulation dummy.Simulation
+	if err := json.Unmarshal([]byte(c.modelCluster.Dummy.Simulation), &simulation); err != nil {
+		return nil, emperror.Wrap(err, "could not decode simulation")
+	}
+
+	return &simulation, nil
+}
+
+// setSimulation stores the simulation settings of the cluster in the model.
+func (c *DummyCluster) setSimulation(simulation *dummy.Simulation) error {
+	data, err := json.Marshal(simulation)
+	if err != nil {
+		return emperror.Wrap(err, "could not encode simulation")
+	}
+
+	c.modelCluster.Dummy.Simulation = string(data)
+
+	return nil
+}
+
+// simulate runs the phases of an operation spreading its latency over them.
+// An injected failure happens halfway, so operations may be applied partially like on a real cloud provider.
+func (c *DummyCluster) simulate(operation string, simulation *dummy.Simulation, phases []dummyPhase) error {
+	if len(phases) == 0 {
+		return nil
+	}
+
+	status := c.modelCluster.Status
+	delay := simulation.Latency(operation) / time.Duration(len(phases))
+
+	for i, phase := range phases {
+		if i == len(phases)/2 {
+			if err := simulation.Fail(operation); err != nil {
+				if serr := c.setSimulation(simulation); serr != nil {
+					return serr
+				}
+
+				if serr := c.modelCluster.Save(); serr != nil {
+					return emperror.Wrap(serr, "could not save cluster")
+				}
+
+				return errors.WithMessage(err, fmt.Sprintf("%s failed", phase.message))
+			}
+		}
+
+		if err := c.modelCluster.UpdateStatus(status, phase.message); err != nil {
+			return emperror.Wrap(err, "could not update cluster status")
+		}
+
+		time.Sleep(delay)
+
+		if phase.apply != nil {
+			phase.apply()
+		}
+	}
+
+	return nil
+}
+
 //CreateCluster creates a new cluster
 func (c *DummyCluster) CreateCluster() error {
-	return nil
+	nodePools, err := c.getNodePools()
+	if err != nil {
+		return err
+	}
+
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return err
+	}
+
+	phases := []dummyPhase{{message: "Provisioning control plane"}}
+	for _, name := range dummy.NodePoolNames(nodePools) {
+		phases = append(phases, dummyPhase{
+			message: fmt.Sprintf("Creating node pool %s (%d nodes)", name, nodePools[name].Count),
+		})
+	}
+	phases = append(phases, dummyPhase{message: "Waiting for nodes to become ready"})
+
+	return c.simulate(dummy.OperationCreate, simulation, phases)
 }
 
 //Persist save the cluster model
@@ -62,6 +236,15 @@ func (c *DummyCluster) Persist(status, statusMessage string) error {
 
 // DownloadK8sConfig downloads the kubeconfig file from cloud
 func (c *DummyCluster) DownloadK8sConfig() ([]byte, error) {
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return nil, err
+	}
+
+	if simulation != nil && simulation.APIServer != "" {
+		return yaml.Marshal(createSimulatedConfig(c.modelCluster.Name, simulation))
+	}
+
 	return yaml.Marshal(createDummyConfig())
 }
 
@@ -83,6 +266,23 @@ func (c *DummyCluster) GetDistribution() string {
 //GetStatus gets cluster status
 func (c *DummyCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
 
+	nodePools, err := c.getNodePools()
+	if err != nil {
+		return nil, err
+	}
+
+	nodePoolStatus := make(map[string]*pkgCluster.NodePoolStatus, len(nodePools))
+	for name, nodePool := range nodePools {
+		nodePoolStatus[name] = &pkgCluster.NodePoolStatus{
+			Autoscaling:  nodePool.Autoscaling,
+			Count:        nodePool.Count,
+			InstanceType: nodePool.InstanceType,
+			MinCount:     nodePool.MinCount,
+			MaxCount:     nodePool.MaxCount,
+			Version:      nodePool.Version,
+		}
+	}
+
 	return &pkgCluster.GetClusterStatusResponse{
 		Status:            c.modelCluster.Status,
 		StatusMessage:     c.modelCluster.StatusMessage,
@@ -92,20 +292,155 @@ func (c *DummyCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error)
 		Distribution:      pkgCluster.Dummy,
 		ResourceID:        c.GetID(),
 		CreatorBaseFields: *NewCreatorBaseFields(c.modelCluster.CreatedAt, c.modelCluster.CreatedBy),
-		NodePools:         nil,
+		NodePools:         nodePoolStatus,
+		Version:           c.modelCluster.Dummy.KubernetesVersion,
 	}, nil
 }
 
 // DeleteCluster deletes cluster
 func (c *DummyCluster) DeleteCluster() error {
-	return nil
+	nodePools, err := c.getNodePools()
+	if err != nil {
+		return err
+	}
+
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return err
+	}
+
+	phases := []dummyPhase{{message: "Draining nodes"}}
+	for _, name := range dummy.NodePoolNames(nodePools) {
+		phases = append(phases, dummyPhase{message: fmt.Sprintf("Deleting node pool %s", name)})
+	}
+	phases = append(phases, dummyPhase{message: "Deleting control plane"})
+
+	return c.simulate(dummy.OperationDelete, simulation, phases)
 }
 
 // UpdateCluster updates the dummy cluster
 func (c *DummyCluster) UpdateCluster(r *pkgCluster.UpdateClusterRequest, _ uint) error {
-	c.modelCluster.Dummy.KubernetesVersion = r.Dummy.Node.KubernetesVersion
-	c.modelCluster.Dummy.NodeCount = r.Dummy.Node.Count
-	return nil
+	if r.Dummy.Simulation != nil {
+		if err := c.setSimulation(r.Dummy.Simulation); err != nil {
+			return err
+		}
+	}
+
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return err
+	}
+
+	current, err := c.getNodePools()
+	if err != nil {
+		return err
+	}
+
+	if r.Dummy.Node != nil && r.Dummy.NodePools == nil {
+		c.modelCluster.Dummy.KubernetesVersion = r.Dummy.Node.KubernetesVersion
+	}
+
+	updated := dummy.UpdatedNodePools(current, r.Dummy, c.modelCluster.Dummy.KubernetesVersion)
+
+	// node pools are changed one by one, so that a failure leaves the cluster partially updated
+	nodePools := make(map[string]*dummy.NodePool, len(current))
+	for name, nodePool := range current {
+		nodePools[name] = nodePool
+	}
+
+	var phases []dummyPhase
+	for _, name := range dummy.NodePoolNames(updated) {
+		name, nodePool := name, updated[name]
+
+		currentNodePool, ok := current[name]
+		switch {
+		case !ok:
+			phases = append(phases, dummyPhase{
+				message: fmt.Sprintf("Creating node pool %s (%d nodes)", name, nodePool.Count),
+				apply:   func() { nodePools[name] = nodePool },
+			})
+		case *currentNodePool != *nodePool:
+			phases = append(phases, dummyPhase{
+				message: fmt.Sprintf("Updating node pool %s (%d to %d nodes)", name, currentNodePool.Count, nodePool.Count),
+				apply:   func() { nodePools[name] = nodePool },
+			})
+		}
+	}
+
+	for _, name := range dummy.NodePoolNames(current) {
+		name := name
+
+		if _, ok := updated[name]; !ok {
+			phases = append(phases, dummyPhase{
+				message: fmt.Sprintf("Deleting node pool %s", name),
+				apply:   func() { delete(nodePools, name) },
+			})
+		}
+	}
+
+	if len(phases) == 0 {
+		phases = append(phases, dummyPhase{message: "Updating cluster"})
+	}
+
+	err = c.simulate(dummy.OperationUpdate, simulation, phases)
+
+	if serr := c.setNodePools(nodePools); serr != nil {
+		return serr
+	}
+
+	return err
+}
+
+// GetKubernetesVersions returns the Kubernetes versions the cluster can be upgraded to
+func (c *DummyCluster) GetKubernetesVersions() ([]string, error) {
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return nil, err
+	}
+
+	if simulation != nil && len(simulation.KubernetesVersions) > 0 {
+		return simulation.KubernetesVersions, nil
+	}
+
+	return dummyKubernetesVersions, nil
+}
+
+// UpgradeControlPlane upgrades the simulated control plane of the cluster
+func (c *DummyCluster) UpgradeControlPlane(version string) error {
+	return c.upgrade(fmt.Sprintf("Upgrading control plane to %s", version), func(map[string]*dummy.NodePool) {
+		c.modelCluster.Dummy.KubernetesVersion = version
+	})
+}
+
+// UpgradeNodePool upgrades a simulated node pool of the cluster
+func (c *DummyCluster) UpgradeNodePool(name string, version string) error {
+	return c.upgrade(fmt.Sprintf("Upgrading node pool %s to %s", name, version), func(nodePools map[string]*dummy.NodePool) {
+		if nodePool, ok := nodePools[name]; ok {
+			nodePool.Version = version
+		}
+	})
+}
+
+func (c *DummyCluster) upgrade(message string, apply func(map[string]*dummy.NodePool)) error {
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return err
+	}
+
+	nodePools, err := c.getNodePools()
+	if err != nil {
+		return err
+	}
+
+	err = c.simulate(dummy.OperationUpgrade, simulation, []dummyPhase{{
+		message: message,
+		apply:   func() { apply(nodePools) },
+	}})
+	if err != nil {
+		return err
+	}
+
+	return c.setNodePools(nodePools)
 }
 
 //GetID returns the specified cluster id
@@ -134,7 +469,16 @@ func (c *DummyCluster) AddDefaultsToUpdate(r *pkgCluster.UpdateClusterRequest) {
 
 //GetAPIEndpoint returns the Kubernetes Api endpoint
 func (c *DummyCluster) GetAPIEndpoint() (string, error) {
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return "", err
+	}
+
 	c.APIEndpoint = "http://cow.org:8080"
+	if simulation != nil && simulation.APIServer != "" {
+		c.APIEndpoint = simulation.APIServer
+	}
+
 	return c.APIEndpoint, nil
 }
 
@@ -230,6 +574,41 @@ func createDummyConfig() *kubeConfig {
 
 }
 
+// createSimulatedConfig creates a kubeconfig for the (fake) API server of a simulated cluster
+func createSimulatedConfig(name string, simulation *dummy.Simulation) *kubeConfig {
+	return &kubeConfig{
+		APIVersion: "v1",
+		Clusters: []configCluster{
+			{
+				Cluster: dataCluster{
+					Server:                simulation.APIServer,
+					InsecureSkipTLSVerify: true,
+				},
+				Name: name,
+			},
+		},
+		Contexts: []configContext{
+			{
+				Context: contextData{
+					Cluster: name,
+					User:    name,
+				},
+				Name: name,
+			},
+		},
+		Users: []configUser{
+			{
+				Name: name,
+				User: userData{
+					Token: simulation.APIToken,
+				},
+			},
+		},
+		CurrentContext: name,
+		Kind:           "Config",
+	}
+}
+
 //CreateDummyClusterFromModel creates the cluster from the model
 func CreateDummyClusterFromModel(clusterModel *model.ClusterModel) (*DummyCluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
@@ -239,6 +618,21 @@ func CreateDummyClusterFromModel(clusterModel *model.ClusterModel) (*DummyCluste
 	return &dummyCluster, nil
 }
 
+// loadDummyClusterFromModel creates the cluster from the model and loads its Dummy properties from the database
+func loadDummyClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	dummyCluster, err := CreateDummyClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	db := config.DB()
+
+	log.Debug("Load Dummy props from database")
+	err = db.Where(model.DummyClusterModel{ID: dummyCluster.modelCluster.ID}).First(&dummyCluster.modelCluster.Dummy).Error
+
+	return dummyCluster, err
+}
+
 // UpdateStatus updates cluster status in database
 func (c *DummyCluster) UpdateStatus(status, statusMessage string) error {
 	return c.modelCluster.UpdateStatus(status, statusMessage)
@@ -246,7 +640,14 @@ func (c *DummyCluster) UpdateStatus(status, statusMessage string) error {
 
 // NodePoolExists returns true if node pool with nodePoolName exists
 func (c *DummyCluster) NodePoolExists(nodePoolName string) bool {
-	return false
+	nodePools, err := c.getNodePools()
+	if err != nil {
+		return false
+	}
+
+	_, ok := nodePools[nodePoolName]
+
+	return ok
 }
 
 // GetClusterDetails gets cluster details from cloud
@@ -261,7 +662,7 @@ func (c *DummyCluster) GetClusterDetails() (*pkgCluster.DetailsResponse, error)
 		Name:              status.Name,
 		Id:                status.ResourceID,
 		Location:          status.Location,
-		MasterVersion:     "1.9.4",
+		MasterVersion:     status.Version,
 	}, nil
 }
 
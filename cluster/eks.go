@@ -29,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgEks "github.com/banzaicloud/pipeline/pkg/cluster/eks"
@@ -61,6 +62,35 @@ const mapUsersTemplate = `- userarn: %s
   - system:masters
 `
 
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Amazon,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateEKSClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadEKSClusterFromModel,
+		AddDefaults: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterEKS.AddDefaults(request.Location)
+		},
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterEKS.Validate()
+		},
+		ValidateUpdate: func(request *pkgCluster.UpdateClusterRequest) error {
+			return request.EKS.Validate()
+		},
+		GetSpec:          getEKSSpec,
+		UpdateFromSpec:   updateEKSFromSpec,
+		PlanUpdate:       planEKSUpdate,
+		SetNodePool:      setEKSNodePool,
+		OverrideNodePool: overrideEKSNodePool,
+	})
+}
+
 //CreateEKSClusterFromRequest creates ClusterModel struct from the request
 func CreateEKSClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId uint, userId uint) (*EKSCluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
@@ -158,6 +188,25 @@ func CreateEKSClusterFromModel(clusterModel *model.ClusterModel) (*EKSCluster, e
 	return &eksCluster, nil
 }
 
+// loadEKSClusterFromModel creates the cluster from the model and loads its EKS properties from the database
+func loadEKSClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	eksCluster, err := CreateEKSClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	db := config.DB()
+
+	log.Debug("Load EKS props from database")
+	err = db.Where(model.EKSClusterModel{ID: eksCluster.modelCluster.ID}).First(&eksCluster.modelCluster.EKS).Error
+	if err != nil {
+		return nil, err
+	}
+	err = db.Model(&eksCluster.modelCluster.EKS).Related(&eksCluster.modelCluster.EKS.NodePools, "NodePools").Error
+
+	return eksCluster, err
+}
+
 func (c *EKSCluster) createAWSCredentialsFromSecret() (*credentials.Credentials, error) {
 	clusterSecret, err := c.GetSecretWithValidation()
 	if err != nil {
@@ -0,0 +1,329 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/pricing"
+)
+
+// EstimateClusterCost estimates the hourly and monthly cost of the node pools of a cluster to be created,
+// or of an existing cluster after applying an update request.
+func (m *Manager) EstimateClusterCost(
+	ctx context.Context,
+	organizationID uint,
+	request *pkgCluster.EstimateClusterRequest,
+	prices pricing.Source,
+) (*pkgCluster.ClusterCostEstimate, error) {
+	switch {
+	case request.Create != nil && (request.ClusterID != 0 || request.Update != nil):
+		return nil, pkgCluster.NewValidationError(
+			"either a create request or a cluster and an update request must be given, not both",
+		)
+
+	case request.Create != nil:
+		if request.Create.Properties == nil {
+			return nil, pkgCluster.NewValidationError("properties of the create request are missing")
+		}
+
+		location, nodePools, err := getSpecNodePools(request.Create)
+		if err != nil {
+			return nil, err
+		}
+
+		return estimateClusterCost(request.Create.Cloud, location, nodePools, prices), nil
+
+	case request.ClusterID != 0 && request.Update != nil:
+		cluster, err := m.GetClusterByID(ctx, organizationID, request.ClusterID)
+		if err != nil {
+			return nil, err
+		}
+
+		if cluster.GetCloud() != request.Update.Cloud {
+			return nil, pkgCluster.NewValidationError(
+				fmt.Sprintf("cloud provider [%s] does not match the cluster's cloud provider [%s]", request.Update.Cloud, cluster.GetCloud()),
+			)
+		}
+
+		location, nodePools, err := getUpdatedNodePools(cluster, request.Update)
+		if err != nil {
+			return nil, err
+		}
+
+		return estimateClusterCost(cluster.GetCloud(), location, nodePools, prices), nil
+
+	default:
+		return nil, pkgCluster.NewValidationError(
+			"either a create request or a cluster and an update request must be given",
+		)
+	}
+}
+
+func newEstimatedNodePool(name string, instanceType string, spotPrice string, size pkgCluster.NodePoolSize) pkgCluster.EstimatedNodePool {
+	return pkgCluster.EstimatedNodePool{
+		NodePoolPlan: pkgCluster.NodePoolPlan{
+			Name:         name,
+			InstanceType: instanceType,
+			NodePoolSize: size,
+		},
+		SpotPrice: spotPrice,
+	}
+}
+
+// getSpecNodePools returns the location and the node pools of a cluster spec (or create request).
+func getSpecNodePools(spec *pkgCluster.CreateClusterRequest) (string, []pkgCluster.EstimatedNodePool, error) {
+	location := spec.Location
+
+	var nodePools []pkgCluster.EstimatedNodePool
+
+	switch p := spec.Properties; {
+	case p.CreateClusterEKS != nil:
+		for name, np := range p.CreateClusterEKS.NodePools {
+			nodePools = append(nodePools, newEstimatedNodePool(name, np.InstanceType, np.SpotPrice, pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+			}))
+		}
+
+	case p.CreateClusterAKS != nil:
+		for name, np := range p.CreateClusterAKS.NodePools {
+			nodePools = append(nodePools, newEstimatedNodePool(name, np.NodeInstanceType, "", pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+			}))
+		}
+
+	case p.CreateClusterGKE != nil:
+		for name, np := range p.CreateClusterGKE.NodePools {
+			nodePools = append(nodePools, newEstimatedNodePool(name, np.NodeInstanceType, "", pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+			}))
+		}
+
+	case p.CreateClusterACSK != nil:
+		if p.CreateClusterACSK.RegionID != "" {
+			location = p.CreateClusterACSK.RegionID
+		}
+
+		for name, np := range p.CreateClusterACSK.NodePools {
+			nodePools = append(nodePools, newEstimatedNodePool(name, np.InstanceType, "", pkgCluster.NodePoolSize{
+				Count: np.Count,
+			}))
+		}
+
+	case p.CreateClusterOKE != nil:
+		for name, np := range p.CreateClusterOKE.NodePools {
+			nodePools = append(nodePools, newEstimatedNodePool(name, np.Shape, "", pkgCluster.NodePoolSize{
+				Count: int(np.Count),
+			}))
+		}
+
+	default:
+		return "", nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("estimating the cost of %s clusters is not supported", spec.Cloud),
+		)
+	}
+
+	sort.Slice(nodePools, func(i, j int) bool { return nodePools[i].Name < nodePools[j].Name })
+
+	return location, nodePools, nil
+}
+
+// getUpdatedNodePools returns the location and the node pools of a cluster after applying an update request.
+// Node pools missing from the request are removed, except for AKS clusters where node pools cannot be removed.
+func getUpdatedNodePools(cluster CommonCluster, request *pkgCluster.UpdateClusterRequest) (string, []pkgCluster.EstimatedNodePool, error) {
+	spec, err := GetClusterSpec(cluster)
+	if err != nil {
+		return "", nil, err
+	}
+
+	location, current, err := getSpecNodePools(spec)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if request.UpdateProperties.IsEmpty() {
+		return location, current, nil
+	}
+
+	existing := make(map[string]pkgCluster.EstimatedNodePool, len(current))
+	for _, np := range current {
+		existing[np.Name] = np
+	}
+
+	updated := make(map[string]pkgCluster.EstimatedNodePool)
+
+	switch p := request.UpdateProperties; {
+	case p.EKS != nil:
+		for name, np := range p.EKS.NodePools {
+			updated[name] = newEstimatedNodePool(name, np.InstanceType, np.SpotPrice, pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+			})
+		}
+
+	case p.AKS != nil:
+		for name, np := range existing {
+			updated[name] = np
+		}
+
+		for name, np := range p.AKS.NodePools {
+			updated[name] = newEstimatedNodePool(name, "", "", pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+			})
+		}
+
+	case p.GKE != nil:
+		for name, np := range p.GKE.NodePools {
+			updated[name] = newEstimatedNodePool(name, np.NodeInstanceType, "", pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.MinCount,
+				MaxCount:    np.MaxCount,
+				Count:       np.Count,
+			})
+		}
+
+	case p.ACSK != nil:
+		for name, np := range p.ACSK.NodePools {
+			updated[name] = newEstimatedNodePool(name, np.InstanceType, "", pkgCluster.NodePoolSize{
+				Count: np.Count,
+			})
+		}
+
+	case p.OKE != nil:
+		for name, np := range p.OKE.NodePools {
+			updated[name] = newEstimatedNodePool(name, np.Shape, "", pkgCluster.NodePoolSize{
+				Count: int(np.Count),
+			})
+		}
+
+	default:
+		return location, current, nil
+	}
+
+	nodePools := make([]pkgCluster.EstimatedNodePool, 0, len(updated))
+	for name, np := range updated {
+		// the instance type of existing node pools cannot be changed
+		if currentNodePool, ok := existing[name]; ok {
+			np.InstanceType = currentNodePool.InstanceType
+		}
+
+		nodePools = append(nodePools, np)
+	}
+
+	sort.Slice(nodePools, func(i, j int) bool { return nodePools[i].Name < nodePools[j].Name })
+
+	return location, nodePools, nil
+}
+
+// estimateClusterCost estimates the cost of node pools from the prices of their instance types.
+// Spot node pools are estimated with the typical spot price of their instance type, but not above their maximum price.
+func estimateClusterCost(
+	cloud string,
+	location string,
+	nodePools []pkgCluster.EstimatedNodePool,
+	prices pricing.Source,
+) *pkgCluster.ClusterCostEstimate {
+	estimate := &pkgCluster.ClusterCostEstimate{
+		Cloud:     cloud,
+		Location:  location,
+		Currency:  prices.Currency(),
+		NodePools: make([]pkgCluster.NodePoolCostEstimate, 0, len(nodePools)),
+	}
+
+	for _, np := range nodePools {
+		nodePoolEstimate := pkgCluster.NodePoolCostEstimate{
+			EstimatedNodePool: np,
+		}
+
+		count, minCount, maxCount := np.Count, np.Count, np.Count
+		if np.Autoscaling {
+			minCount, maxCount = np.MinCount, np.MaxCount
+
+			if count < minCount {
+				count = minCount
+			}
+		}
+
+		nodePoolEstimate.Count = count
+
+		price, err := prices.GetPrice(cloud, location, np.InstanceType)
+		if err != nil {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("node pool [%s]: %s", np.Name, err.Error()))
+		} else {
+			nodePoolEstimate.PriceKnown = true
+			nodePoolEstimate.OnDemandPrice = price.OnDemand
+			nodePoolEstimate.NodePrice = price.OnDemand
+		}
+
+		if np.SpotPrice != "" && np.SpotPrice != "0" {
+			nodePoolEstimate.Spot = true
+
+			maxPrice, err := strconv.ParseFloat(np.SpotPrice, 64)
+			if err != nil {
+				estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("node pool [%s]: invalid spot price [%s]", np.Name, np.SpotPrice))
+			} else if nodePoolEstimate.PriceKnown && price.Spot > 0 {
+				nodePoolEstimate.NodePrice = math.Min(price.Spot, maxPrice)
+			} else {
+				// without a known spot price the maximum price is the worst case
+				nodePoolEstimate.PriceKnown = true
+				nodePoolEstimate.NodePrice = maxPrice
+
+				estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("node pool [%s]: spot price is not known, maximum price is used", np.Name))
+			}
+		}
+
+		nodePoolEstimate.HourlyCost = roundCost(nodePoolEstimate.NodePrice * float64(count))
+		nodePoolEstimate.MonthlyCost = roundCost(nodePoolEstimate.NodePrice * float64(count) * pkgCluster.HoursPerMonth)
+		nodePoolEstimate.MinHourlyCost = roundCost(nodePoolEstimate.NodePrice * float64(minCount))
+		nodePoolEstimate.MaxHourlyCost = roundCost(nodePoolEstimate.NodePrice * float64(maxCount))
+
+		estimate.HourlyCost += nodePoolEstimate.HourlyCost
+		estimate.MonthlyCost += nodePoolEstimate.MonthlyCost
+		estimate.MinHourlyCost += nodePoolEstimate.MinHourlyCost
+		estimate.MaxHourlyCost += nodePoolEstimate.MaxHourlyCost
+
+		estimate.NodePools = append(estimate.NodePools, nodePoolEstimate)
+	}
+
+	estimate.HourlyCost = roundCost(estimate.HourlyCost)
+	estimate.MonthlyCost = roundCost(estimate.MonthlyCost)
+	estimate.MinHourlyCost = roundCost(estimate.MinHourlyCost)
+	estimate.MaxHourlyCost = roundCost(estimate.MaxHourlyCost)
+
+	return estimate
+}
+
+func roundCost(cost float64) float64 {
+	return math.Round(cost*10000) / 10000
+}
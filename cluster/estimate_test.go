@@ -0,0 +1,103 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/pricing"
+)
+
+func TestEstimateClusterCost(t *testing.T) {
+	prices := pricing.NewCatalog("USD")
+	prices.Prices[pkgCluster.Amazon] = map[string]map[string]pricing.Price{
+		"us-east-1": {
+			"m4.xlarge": {OnDemand: 0.2, Spot: 0.06},
+			"c5.large":  {OnDemand: 0.085},
+		},
+	}
+
+	nodePool := func(name string, instanceType string, spotPrice string, size pkgCluster.NodePoolSize) pkgCluster.EstimatedNodePool {
+		return newEstimatedNodePool(name, instanceType, spotPrice, size)
+	}
+
+	tests := []struct {
+		name      string
+		nodePools []pkgCluster.EstimatedNodePool
+		hourly    float64
+		min       float64
+		max       float64
+		warnings  int
+	}{
+		{
+			name:      "on-demand",
+			nodePools: []pkgCluster.EstimatedNodePool{nodePool("pool1", "m4.xlarge", "", pkgCluster.NodePoolSize{Count: 3})},
+			hourly:    0.6, min: 0.6, max: 0.6,
+		},
+		{
+			name:      "spot below maximum price",
+			nodePools: []pkgCluster.EstimatedNodePool{nodePool("pool1", "m4.xlarge", "0.1", pkgCluster.NodePoolSize{Count: 2})},
+			hourly:    0.12, min: 0.12, max: 0.12,
+		},
+		{
+			name:      "spot above maximum price",
+			nodePools: []pkgCluster.EstimatedNodePool{nodePool("pool1", "m4.xlarge", "0.05", pkgCluster.NodePoolSize{Count: 2})},
+			hourly:    0.1, min: 0.1, max: 0.1,
+		},
+		{
+			name:      "unknown spot price",
+			nodePools: []pkgCluster.EstimatedNodePool{nodePool("pool1", "c5.large", "0.04", pkgCluster.NodePoolSize{Count: 1})},
+			hourly:    0.04, min: 0.04, max: 0.04, warnings: 1,
+		},
+		{
+			name: "autoscaling",
+			nodePools: []pkgCluster.EstimatedNodePool{
+				nodePool("pool1", "c5.large", "", pkgCluster.NodePoolSize{Autoscaling: true, MinCount: 2, MaxCount: 10, Count: 1}),
+			},
+			hourly: 0.17, min: 0.17, max: 0.85,
+		},
+		{
+			name: "unknown instance type",
+			nodePools: []pkgCluster.EstimatedNodePool{
+				nodePool("pool1", "m4.xlarge", "", pkgCluster.NodePoolSize{Count: 1}),
+				nodePool("pool2", "x1.32xlarge", "", pkgCluster.NodePoolSize{Count: 1}),
+			},
+			hourly: 0.2, min: 0.2, max: 0.2, warnings: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			estimate := estimateClusterCost(pkgCluster.Amazon, "us-east-1", test.nodePools, prices)
+
+			if estimate.HourlyCost != test.hourly {
+				t.Errorf("expected hourly cost %v, got %v", test.hourly, estimate.HourlyCost)
+			}
+
+			if estimate.MonthlyCost != roundCost(test.hourly*pkgCluster.HoursPerMonth) {
+				t.Errorf("expected monthly cost %v, got %v", roundCost(test.hourly*pkgCluster.HoursPerMonth), estimate.MonthlyCost)
+			}
+
+			if estimate.MinHourlyCost != test.min || estimate.MaxHourlyCost != test.max {
+				t.Errorf("expected hourly cost between %v and %v, got %v and %v", test.min, test.max, estimate.MinHourlyCost, estimate.MaxHourlyCost)
+			}
+
+			if len(estimate.Warnings) != test.warnings {
+				t.Errorf("expected %d warnings, got %v", test.warnings, estimate.Warnings)
+			}
+		})
+	}
+}
@@ -14,12 +14,17 @@
 
 package cluster
 
+import "time"
+
 type clusterEvents interface {
 	// ClusterCreated event is emitted when a cluster creation workflow finishes.
 	ClusterCreated(clusterID uint)
 
 	// ClusterDeleted event is emitted when a cluster is completely deleted.
 	ClusterDeleted(orgID uint, clusterName string)
+
+	// ClusterExpiring event is emitted when a cluster is about to be deleted because it expires.
+	ClusterExpiring(clusterID uint, expiresAt time.Time)
 }
 
 type nopClusterEvents struct {
@@ -35,6 +40,9 @@ func (*nopClusterEvents) ClusterCreated(clusterID uint) {
 func (*nopClusterEvents) ClusterDeleted(orgID uint, clusterName string) {
 }
 
+func (*nopClusterEvents) ClusterExpiring(clusterID uint, expiresAt time.Time) {
+}
+
 type eventBus interface {
 	Publish(topic string, args ...interface{})
 }
@@ -44,8 +52,9 @@ type clusterEventBus struct {
 }
 
 const (
-	clusterCreatedTopic = "cluster_created"
-	clusterDeletedTopic = "cluster_deleted"
+	clusterCreatedTopic  = "cluster_created"
+	clusterDeletedTopic  = "cluster_deleted"
+	clusterExpiringTopic = "cluster_expiring"
 )
 
 func NewClusterEvents(eb eventBus) *clusterEventBus {
@@ -61,3 +70,7 @@ func (c *clusterEventBus) ClusterCreated(clusterID uint) {
 func (c *clusterEventBus) ClusterDeleted(orgID uint, clusterName string) {
 	c.eb.Publish(clusterDeletedTopic, orgID, clusterName)
 }
+
+func (c *clusterEventBus) ClusterExpiring(clusterID uint, expiresAt time.Time) {
+	c.eb.Publish(clusterExpiringTopic, clusterID, expiresAt)
+}
@@ -71,6 +71,40 @@ const (
 	clusterNameKey = "cluster-name"
 )
 
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Google,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateGKEClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadGKEClusterFromModel,
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterGKE.Validate()
+		},
+		ValidateUpdate: func(request *pkgCluster.UpdateClusterRequest) error {
+			return request.GKE.Validate()
+		},
+		KubernetesVersions: func(cluster CommonCluster) ([]string, error) {
+			config, err := GetGkeServerConfig(cluster.GetOrganizationId(), cluster.GetSecretId(), cluster.GetLocation())
+			if err != nil {
+				return nil, err
+			}
+
+			return config.ValidMasterVersions, nil
+		},
+		GetSpec:          getGKESpec,
+		UpdateFromSpec:   updateGKEFromSpec,
+		PlanUpdate:       planGKEUpdate,
+		SetNodePool:      setGKENodePool,
+		OverrideNodePool: overrideGKENodePool,
+	})
+}
+
 // CreateGKEClusterFromRequest creates ClusterModel struct from the request
 func CreateGKEClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgID, userID uint) (*GKECluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
@@ -277,10 +311,8 @@ func (c *GKECluster) updateCurrentVersions(gkeCluster *gke.Cluster) {
 //Persist save the cluster model
 func (c *GKECluster) Persist(status, statusMessage string) error {
 	log.Infof("Model before save: %v", c.model)
-	c.model.Cluster.Status = status
-	c.model.Cluster.StatusMessage = statusMessage
 
-	err := c.db.Save(&c.model).Error
+	err := c.saveWithStatus(status, statusMessage)
 	if err != nil {
 		return errors.Wrap(err, "failed to persist cluster")
 	}
@@ -288,6 +320,19 @@ func (c *GKECluster) Persist(status, statusMessage string) error {
 	return nil
 }
 
+// saveWithStatus changes the status of the cluster through the cluster state machine and saves the model.
+func (c *GKECluster) saveWithStatus(status, statusMessage string) error {
+	cluster := &c.model.Cluster
+
+	return model.SaveClusterWithStatus(c.db, cluster.ID, cluster.Status, cluster.StatusMessage, status, statusMessage, func() (uint, error) {
+		cluster.Status = status
+		cluster.StatusMessage = statusMessage
+		err := c.db.Save(&c.model).Error
+
+		return cluster.ID, err
+	})
+}
+
 // DownloadK8sConfig downloads the kubeconfig file from cloud
 func (c *GKECluster) DownloadK8sConfig() ([]byte, error) {
 
@@ -1396,6 +1441,7 @@ type configCluster struct {
 type dataCluster struct {
 	CertificateAuthorityData string `yaml:"certificate-authority-data,omitempty"`
 	Server                   string `yaml:"server,omitempty"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify,omitempty"`
 }
 
 type configContext struct {
@@ -1454,6 +1500,16 @@ func CreateGKEClusterFromModel(clusterModel *model.ClusterModel) (*GKECluster, e
 	return &gkeCluster, nil
 }
 
+// loadGKEClusterFromModel creates the cluster from the model
+func loadGKEClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	gkeCluster, err := CreateGKEClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return gkeCluster, nil
+}
+
 //AddDefaultsToUpdate adds defaults to update request
 func (c *GKECluster) AddDefaultsToUpdate(r *pkgCluster.UpdateClusterRequest) {
 
@@ -1593,6 +1649,79 @@ func (c *GKECluster) GetGkeServerConfig(zone string) (*gke.ServerConfig, error)
 
 }
 
+// UpgradeControlPlane upgrades the master of the cluster to the given Kubernetes version.
+func (c *GKECluster) UpgradeControlPlane(version string) error {
+	svc, err := c.getGoogleServiceClient()
+	if err != nil {
+		return err
+	}
+
+	projectId, err := c.getProjectId()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Upgrading master to %s version", version)
+	updateCall, err := svc.Projects.Zones.Clusters.Update(projectId, c.model.Cluster.Location, c.model.Cluster.Name, &gke.UpdateClusterRequest{
+		Update: &gke.ClusterUpdate{
+			DesiredMasterVersion: version,
+		},
+	}).Context(context.Background()).Do()
+	if err != nil {
+		return errors.New(getBanzaiErrorFromError(err).Message)
+	}
+
+	if err := waitForOperation(newContainerOperation(svc, projectId, c.model.Cluster.Location), updateCall.Name); err != nil {
+		return err
+	}
+
+	return c.refreshVersions(svc, projectId)
+}
+
+// UpgradeNodePool upgrades the nodes of a node pool to the given Kubernetes version.
+func (c *GKECluster) UpgradeNodePool(name string, version string) error {
+	svc, err := c.getGoogleServiceClient()
+	if err != nil {
+		return err
+	}
+
+	projectId, err := c.getProjectId()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Upgrading node pool %s to %s version", name, version)
+	updateCall, err := svc.Projects.Zones.Clusters.NodePools.Update(projectId, c.model.Cluster.Location, c.model.Cluster.Name, name, &gke.UpdateNodePoolRequest{
+		NodeVersion: version,
+	}).Context(context.Background()).Do()
+	if err != nil {
+		return errors.New(getBanzaiErrorFromError(err).Message)
+	}
+
+	if err := waitForOperation(newContainerOperation(svc, projectId, c.model.Cluster.Location), updateCall.Name); err != nil {
+		return err
+	}
+
+	return c.refreshVersions(svc, projectId)
+}
+
+// refreshVersions updates the stored master and node versions from the cluster.
+func (c *GKECluster) refreshVersions(svc *gke.Service, projectId string) error {
+	res, err := getClusterGoogle(svc, googleCluster{
+		Name:      c.model.Cluster.Name,
+		ProjectID: projectId,
+		Zone:      c.model.Cluster.Location,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.googleCluster = res
+	c.updateCurrentVersions(res)
+
+	return nil
+}
+
 func updateVersions(validVersions []string) []string {
 
 	log.Info("append `major.minor` K8S version format to valid GKE versions")
@@ -1761,10 +1890,7 @@ func (c *GKECluster) getProjectId() (string, error) {
 
 // UpdateStatus updates cluster status in database
 func (c *GKECluster) UpdateStatus(status, statusMessage string) error {
-	c.model.Cluster.Status = status
-	c.model.Cluster.StatusMessage = statusMessage
-
-	err := c.db.Save(&c.model).Error
+	err := c.saveWithStatus(status, statusMessage)
 	if err != nil {
 		return errors.Wrap(err, "failed to update status")
 	}
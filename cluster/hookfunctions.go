@@ -31,58 +31,72 @@ var HookMap = map[string]PostFunctioner{
 	pkgCluster.SetupPrivileges: &BasePostFunction{
 		f:            SetupPrivileges,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.StoreKubeConfig},
 	},
 	pkgCluster.InstallHelmPostHook: &BasePostFunction{
 		f:            InstallHelmPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.SetupPrivileges, pkgCluster.TaintHeadNodes},
 	},
 	pkgCluster.InstallIngressControllerPostHook: &BasePostFunction{
 		f:            InstallIngressControllerPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook, pkgCluster.RegisterDomainPostHook},
 	},
 	pkgCluster.InstallKubernetesDashboardPostHook: &BasePostFunction{
 		f:            InstallKubernetesDashboardPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.InstallClusterAutoscalerPostHook: &BasePostFunction{
 		f:            InstallClusterAutoscalerPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.InstallHorizontalPodAutoscalerPostHook: &BasePostFunction{
 		f:            InstallHorizontalPodAutoscalerPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.InstallMonitoring: &BasePostFunction{
 		f:            InstallMonitoring,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook, pkgCluster.RegisterDomainPostHook, pkgCluster.InstallIngressControllerPostHook},
 	},
 	pkgCluster.InstallLogging: &PostFunctionWithParam{
 		f:            InstallLogging,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.RegisterDomainPostHook: &BasePostFunction{
 		f:            RegisterDomainPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.LabelNodes: &BasePostFunction{
 		f:            LabelNodes,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.StoreKubeConfig},
 	},
 	pkgCluster.TaintHeadNodes: &BasePostFunction{
 		f:            TaintHeadNodes,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.LabelNodes},
 	},
 	pkgCluster.InstallPVCOperator: &BasePostFunction{
 		f:            InstallPVCOperatorPostHook,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.InstallAnchoreImageValidator: &BasePostFunction{
 		f:            InstallAnchoreImageValidator,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 	pkgCluster.RestoreFromBackup: &PostFunctionWithParam{
 		f:            RestoreFromBackup,
 		ErrorHandler: ErrorHandler{},
+		dependencies: []string{pkgCluster.InstallHelmPostHook},
 	},
 }
 
@@ -102,10 +116,26 @@ var BasePostHookFunctions = []PostFunctioner{
 	HookMap[pkgCluster.InstallAnchoreImageValidator],
 }
 
+func init() {
+	// posthook functions know their own name, so that their state can be tracked
+	for name, function := range HookMap {
+		switch f := function.(type) {
+		case *BasePostFunction:
+			f.name = name
+		case *PostFunctionWithParam:
+			f.name = name
+		}
+	}
+}
+
 // PostFunctioner manages posthook functions
 type PostFunctioner interface {
 	Do(CommonCluster) error
 	Error(CommonCluster, error)
+	GetName() string
+
+	// GetDependencies returns the names of the posthooks which have to succeed before the posthook can run
+	GetDependencies() []string
 }
 
 // ErrorHandler is the common struct which implement Error function
@@ -118,14 +148,18 @@ func (*ErrorHandler) Error(c CommonCluster, err error) {
 
 // BasePostFunction describe a default posthook function
 type BasePostFunction struct {
-	f func(interface{}) error
+	f            func(interface{}) error
+	name         string
+	dependencies []string
 	ErrorHandler
 }
 
 // PostFunctionWithParam describes a posthook function with params
 type PostFunctionWithParam struct {
-	f      func(interface{}, pkgCluster.PostHookParam) error
-	params pkgCluster.PostHookParam
+	f            func(interface{}, pkgCluster.PostHookParam) error
+	name         string
+	dependencies []string
+	params       pkgCluster.PostHookParam
 	ErrorHandler
 }
 
@@ -143,6 +177,16 @@ func (b *BasePostFunction) String() string {
 	return getFunctionName(b.f)
 }
 
+// GetName returns the name the posthook function is registered with
+func (b *BasePostFunction) GetName() string {
+	return b.name
+}
+
+// GetDependencies returns the names of the posthooks which have to succeed before the posthook can run
+func (b *BasePostFunction) GetDependencies() []string {
+	return b.dependencies
+}
+
 func getFunctionName(f interface{}) string {
 	function := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
 	packageEnd := strings.LastIndex(function, ".")
@@ -155,7 +199,39 @@ func (p *PostFunctionWithParam) String() string {
 	return getFunctionName(p.f)
 }
 
+// GetName returns the name the posthook function is registered with
+func (p *PostFunctionWithParam) GetName() string {
+	return p.name
+}
+
+// GetDependencies returns the names of the posthooks which have to succeed before the posthook can run
+func (p *PostFunctionWithParam) GetDependencies() []string {
+	return p.dependencies
+}
+
 // SetParams sets posthook params
 func (p *PostFunctionWithParam) SetParams(params pkgCluster.PostHookParam) {
 	p.params = params
 }
+
+// GetParams returns posthook params
+func (p *PostFunctionWithParam) GetParams() pkgCluster.PostHookParam {
+	return p.params
+}
+
+// NewPostHookFunction returns the posthook function registered with the given name, initialized with the given params.
+// It returns nil if there's no posthook function with the given name.
+func NewPostHookFunction(name string, params pkgCluster.PostHookParam) PostFunctioner {
+	function := HookMap[name]
+	if function == nil {
+		return nil
+	}
+
+	if f, ok := function.(*PostFunctionWithParam); ok {
+		fa := *f
+		fa.SetParams(params)
+		function = &fa
+	}
+
+	return function
+}
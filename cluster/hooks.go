@@ -50,41 +50,6 @@ import (
 	pkgHelmRelease "k8s.io/helm/pkg/proto/hapi/release"
 )
 
-//RunPostHooks calls posthook functions with created cluster
-func RunPostHooks(postHooks []PostFunctioner, cluster CommonCluster) (err error) {
-
-	log := log.WithFields(logrus.Fields{"cluster": cluster.GetName(), "org": cluster.GetOrganizationId()})
-
-	for _, postHook := range postHooks {
-		if postHook != nil {
-			log.Infof("Start posthook function[%s]", postHook)
-			err = postHook.Do(cluster)
-			if err != nil {
-				log.Errorf("Error during posthook function[%s]: %s", postHook, err.Error())
-				postHook.Error(cluster, err)
-				return
-			}
-
-			statusMsg := fmt.Sprintf("Posthook function finished: %s", postHook)
-			err = cluster.UpdateStatus(pkgCluster.Creating, statusMsg)
-			if err != nil {
-				log.Errorf("Error during posthook status update in db [%s]: %s", postHook, err.Error())
-				return
-			}
-		}
-	}
-
-	log.Info("Run all posthooks for cluster successfully.")
-
-	err = cluster.UpdateStatus(pkgCluster.Running, pkgCluster.RunningMessage)
-
-	if err != nil {
-		log.Errorf("Error during posthook status update in db: %s", err.Error())
-	}
-
-	return
-}
-
 // PollingKubernetesConfig polls kubeconfig from the cloud
 func PollingKubernetesConfig(cluster CommonCluster) ([]byte, error) {
 
@@ -18,6 +18,7 @@ import (
 	"encoding/base64"
 
 	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/platform/database"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -29,6 +30,24 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Kubernetes,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateKubernetesClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadKubernetesClusterFromModel,
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterKubernetes.Validate()
+		},
+	})
+}
+
 // CreateKubernetesClusterFromRequest creates ClusterModel struct from the request
 func CreateKubernetesClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId, userId uint) (*KubeCluster, error) {
 
@@ -238,6 +257,26 @@ func CreateKubernetesClusterFromModel(clusterModel *model.ClusterModel) (*KubeCl
 	return &kubeCluster, nil
 }
 
+// loadKubernetesClusterFromModel creates the cluster from the model and loads its Kubernetes properties from the database
+func loadKubernetesClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	kubernetesCluster, err := CreateKubernetesClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	db := config.DB()
+
+	log.Debug("Load Kubernetes props from database")
+	err = db.Where(model.KubernetesClusterModel{ID: kubernetesCluster.modelCluster.ID}).First(&kubernetesCluster.modelCluster.Kubernetes).Error
+	if database.IsRecordNotFoundError(err) {
+		// metadata not set so there's no properties in DB
+		log.Warnf(err.Error())
+		err = nil
+	}
+
+	return kubernetesCluster, err
+}
+
 // UpdateStatus updates cluster status in database
 func (c *KubeCluster) UpdateStatus(status, statusMessage string) error {
 	return c.modelCluster.UpdateStatus(status, statusMessage)
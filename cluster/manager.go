@@ -16,10 +16,14 @@ package cluster
 
 import (
 	"context"
+	"time"
 
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
 	"github.com/banzaicloud/pipeline/model"
+	"github.com/banzaicloud/pipeline/pkg/pricing"
 	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
 )
 
@@ -30,26 +34,90 @@ type clusterRepository interface {
 	FindOneByID(organizationID uint, clusterID uint) (*model.ClusterModel, error)
 	FindOneByName(organizationID uint, clusterName string) (*model.ClusterModel, error)
 	FindBySecret(organizationID uint, secretID string) ([]*model.ClusterModel, error)
+	SetLabels(clusterID uint, labels map[string]string) error
+	FindExpiringBefore(t time.Time) ([]*model.ClusterModel, error)
+	SetExpiration(clusterID uint, expiresAt *time.Time) error
+	SetExpirationWarned(clusterID uint, warnedAt time.Time) error
+	SetDeletionProtection(clusterID uint, enabled bool, userID uint) error
+	SetFeatureFlag(clusterID uint, flag string, enabled bool) error
+	FindStatusHistory(clusterID uint) ([]*model.ClusterStatusHistoryModel, error)
 }
 
 type secretValidator interface {
 	ValidateSecretType(organizationID uint, secretID string, cloud string) error
 }
 
+// Repositories holds the persistence of the data managed by the cluster manager.
+type Repositories struct {
+	Clusters          clusterRepository
+	Operations        operationRepository
+	PostHooks         postHookRepository
+	Drifts            driftRepository
+	Locks             lockRepository
+	Maintenance       maintenanceRepository
+	NodePoolSchedules nodePoolScheduleRepository
+	Quotas            quotaRepository
+	CustomPostHooks   customPostHookRepository
+	SecretRotations   secretRotationRepository
+}
+
+// NewRepositories returns the database backed repositories of the cluster manager.
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Clusters:          intCluster.NewClusters(db),
+		Operations:        intCluster.NewOperations(db),
+		PostHooks:         intCluster.NewPostHooks(db),
+		Drifts:            intCluster.NewDrifts(db),
+		Locks:             intCluster.NewLocks(db),
+		Maintenance:       intCluster.NewMaintenance(db),
+		NodePoolSchedules: intCluster.NewNodePoolSchedules(db),
+		Quotas:            intCluster.NewQuotas(db),
+		CustomPostHooks:   intCluster.NewCustomPostHooks(db),
+		SecretRotations:   intCluster.NewSecretRotations(db),
+	}
+}
+
 type Manager struct {
-	clusters clusterRepository
-	secrets  secretValidator
-	events   clusterEvents
+	clusters    clusterRepository
+	operations  operationRepository
+	postHooks   postHookRepository
+	drifts      driftRepository
+	locks       lockRepository
+	maintenance maintenanceRepository
+	schedules   nodePoolScheduleRepository
+	quotas      quotaRepository
+	customHooks customPostHookRepository
+	rotations   secretRotationRepository
+	secrets     secretValidator
+	events      clusterEvents
+	prices      pricing.Source
 
 	logger       logrus.FieldLogger
 	errorHandler emperror.Handler
 }
 
-func NewManager(clusters clusterRepository, secrets secretValidator, events clusterEvents, logger logrus.FieldLogger, errorHandler emperror.Handler) *Manager {
+func NewManager(
+	repositories Repositories,
+	secrets secretValidator,
+	events clusterEvents,
+	prices pricing.Source,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *Manager {
 	return &Manager{
-		clusters: clusters,
-		secrets:  secrets,
-		events:   events,
+		clusters:    repositories.Clusters,
+		operations:  repositories.Operations,
+		postHooks:   repositories.PostHooks,
+		drifts:      repositories.Drifts,
+		locks:       repositories.Locks,
+		maintenance: repositories.Maintenance,
+		schedules:   repositories.NodePoolSchedules,
+		quotas:      repositories.Quotas,
+		customHooks: repositories.CustomPostHooks,
+		rotations:   repositories.SecretRotations,
+		secrets:     secrets,
+		events:      events,
+		prices:      prices,
 
 		logger:       logger,
 		errorHandler: errorHandler,
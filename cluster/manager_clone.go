@@ -0,0 +1,257 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// NewCloneRequest rebuilds the create request of a cluster from its stored model
+// (including its labels and the posthooks it was created with) and applies the overrides of a clone request.
+func (m *Manager) NewCloneRequest(
+	ctx context.Context,
+	source CommonCluster,
+	request *pkgCluster.CloneClusterRequest,
+) (*pkgCluster.CreateClusterRequest, error) {
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": source.GetOrganizationId(),
+		"cluster":      source.GetID(),
+		"clone":        request.Name,
+	}).Info("creating clone request")
+
+	spec, err := GetClusterSpec(source)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.Name = request.Name
+
+	if request.Location != "" && request.Location != spec.Location {
+		if spec.Properties.CreateClusterACSK != nil {
+			return nil, pkgCluster.NewValidationError("location of ACSK clusters cannot be overridden")
+		}
+
+		spec.Location = request.Location
+	}
+
+	if request.SecretId != "" {
+		spec.SecretId = request.SecretId
+	} else if request.SecretName != "" {
+		spec.SecretId = secret.GenerateSecretIDFromName(request.SecretName)
+	}
+
+	for name, nodePool := range request.NodePools {
+		if nodePool == nil {
+			continue
+		}
+
+		if err := overrideNodePool(spec, name, nodePool); err != nil {
+			return nil, err
+		}
+	}
+
+	clusterModel, err := m.clusters.FindOneByID(source.GetOrganizationId(), source.GetID())
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get source cluster")
+	}
+
+	spec.Labels = clusterModel.GetLabels()
+
+	postHooks, err := m.getCreationPostHooks(source)
+	if err != nil {
+		return nil, err
+	}
+
+	spec.PostHooks = postHooks
+
+	return spec, nil
+}
+
+// getCreationPostHooks returns the posthooks (and their params) a cluster was created with on top of the base posthooks.
+func (m *Manager) getCreationPostHooks(cluster CommonCluster) (pkgCluster.PostHooks, error) {
+	recorded, err := m.postHooks.FindByClusterID(cluster.GetID())
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster posthooks")
+	}
+
+	basePostHooks := make(map[string]bool, len(BasePostHookFunctions))
+	for _, function := range BasePostHookFunctions {
+		basePostHooks[function.GetName()] = true
+	}
+
+	postHooks := make(pkgCluster.PostHooks)
+	for _, postHook := range recorded {
+		if basePostHooks[postHook.Name] {
+			continue
+		}
+
+		var params pkgCluster.PostHookParam
+		if postHook.Params != "" {
+			if err := json.Unmarshal([]byte(postHook.Params), &params); err != nil {
+				return nil, emperror.With(errors.Wrap(err, "could not unmarshal posthook params"), "posthook", postHook.Name)
+			}
+		}
+
+		postHooks[postHook.Name] = params
+	}
+
+	return postHooks, nil
+}
+
+// overrideNodePool replaces the fields of a node pool in a spec which are set in an override.
+func overrideNodePool(spec *pkgCluster.CreateClusterRequest, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	distribution, err := GetDistribution(spec.Cloud)
+	if err != nil || distribution.OverrideNodePool == nil {
+		return pkgCluster.NewValidationError(fmt.Sprintf("overriding node pools of %s clusters is not supported", spec.Cloud))
+	}
+
+	return distribution.OverrideNodePool(spec.Properties, name, nodePool)
+}
+
+func overrideEKSNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	current, ok := properties.CreateClusterEKS.NodePools[name]
+	if !ok {
+		return newSourceNodePoolNotFoundError(name)
+	}
+
+	overrideNodePoolSize(nodePool, &current.Autoscaling, &current.MinCount, &current.MaxCount, &current.Count)
+	if nodePool.InstanceType != "" {
+		current.InstanceType = nodePool.InstanceType
+	}
+	if nodePool.SpotPrice != "" {
+		current.SpotPrice = nodePool.SpotPrice
+	}
+	if nodePool.Image != "" {
+		current.Image = nodePool.Image
+	}
+
+	return nil
+}
+
+func overrideAKSNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	current, ok := properties.CreateClusterAKS.NodePools[name]
+	if !ok {
+		return newSourceNodePoolNotFoundError(name)
+	}
+
+	overrideNodePoolSize(nodePool, &current.Autoscaling, &current.MinCount, &current.MaxCount, &current.Count)
+	if nodePool.InstanceType != "" {
+		current.NodeInstanceType = nodePool.InstanceType
+	}
+
+	return nil
+}
+
+func overrideGKENodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	current, ok := properties.CreateClusterGKE.NodePools[name]
+	if !ok {
+		return newSourceNodePoolNotFoundError(name)
+	}
+
+	overrideNodePoolSize(nodePool, &current.Autoscaling, &current.MinCount, &current.MaxCount, &current.Count)
+	if nodePool.InstanceType != "" {
+		current.NodeInstanceType = nodePool.InstanceType
+	}
+
+	return nil
+}
+
+// ACSK and OKE node pools cannot be autoscaled, setting autoscaling is rejected when the clone is validated.
+func overrideACSKNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	current, ok := properties.CreateClusterACSK.NodePools[name]
+	if !ok {
+		return newSourceNodePoolNotFoundError(name)
+	}
+
+	if nodePool.Autoscaling != nil {
+		current.Autoscaling = *nodePool.Autoscaling
+	}
+	if nodePool.Count != nil {
+		current.Count = *nodePool.Count
+	}
+	if nodePool.InstanceType != "" {
+		current.InstanceType = nodePool.InstanceType
+	}
+
+	return nil
+}
+
+func overrideOKENodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	current, ok := properties.CreateClusterOKE.NodePools[name]
+	if !ok {
+		return newSourceNodePoolNotFoundError(name)
+	}
+
+	if nodePool.Autoscaling != nil {
+		current.Autoscaling = *nodePool.Autoscaling
+	}
+	if nodePool.Count != nil {
+		if *nodePool.Count < 0 {
+			return pkgCluster.NewValidationError("node count must not be negative")
+		}
+
+		current.Count = uint(*nodePool.Count)
+	}
+	if nodePool.InstanceType != "" {
+		current.Shape = nodePool.InstanceType
+	}
+	if nodePool.Image != "" {
+		current.Image = nodePool.Image
+	}
+
+	return nil
+}
+
+func overrideDummyNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolOverride) error {
+	current, ok := properties.CreateClusterDummy.NodePools[name]
+	if !ok {
+		return newSourceNodePoolNotFoundError(name)
+	}
+
+	overrideNodePoolSize(nodePool, &current.Autoscaling, &current.MinCount, &current.MaxCount, &current.Count)
+	if nodePool.InstanceType != "" {
+		current.InstanceType = nodePool.InstanceType
+	}
+
+	return nil
+}
+
+// overrideNodePoolSize replaces the autoscaling settings and the node count of a node pool which are set in an override.
+func overrideNodePoolSize(nodePool *pkgCluster.NodePoolOverride, autoscaling *bool, minCount *int, maxCount *int, count *int) {
+	if nodePool.Autoscaling != nil {
+		*autoscaling = *nodePool.Autoscaling
+	}
+	if nodePool.MinCount != nil {
+		*minCount = *nodePool.MinCount
+	}
+	if nodePool.MaxCount != nil {
+		*maxCount = *nodePool.MaxCount
+	}
+	if nodePool.Count != nil {
+		*count = *nodePool.Count
+	}
+}
+
+func newSourceNodePoolNotFoundError(name string) error {
+	return pkgCluster.NewValidationError(fmt.Sprintf("node pool [%s] does not exist in the source cluster", name))
+}
@@ -0,0 +1,103 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/acsk"
+	pkgClusterGoogle "github.com/banzaicloud/pipeline/pkg/cluster/gke"
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
+)
+
+func TestOverrideNodePool(t *testing.T) {
+	spec := &pkgCluster.CreateClusterRequest{
+		Cloud: pkgCluster.Google,
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterGKE: &pkgClusterGoogle.CreateClusterGKE{
+				NodePools: map[string]*pkgClusterGoogle.NodePool{
+					"pool1": {Count: 3, NodeInstanceType: "n1-standard-2"},
+				},
+			},
+		},
+	}
+
+	count := 1
+	err := overrideNodePool(spec, "pool1", &pkgCluster.NodePoolOverride{Count: &count})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	nodePool := spec.Properties.CreateClusterGKE.NodePools["pool1"]
+	if nodePool.Count != 1 {
+		t.Errorf("expected node count 1, got %d", nodePool.Count)
+	}
+
+	if nodePool.NodeInstanceType != "n1-standard-2" {
+		t.Errorf("expected the instance type to be kept, got %s", nodePool.NodeInstanceType)
+	}
+
+	err = overrideNodePool(spec, "pool2", &pkgCluster.NodePoolOverride{Count: &count})
+	if _, ok := err.(*pkgCluster.ValidationError); !ok {
+		t.Fatalf("expected a validation error, got: %v", err)
+	}
+}
+
+func TestOverrideNodePool_InstanceTypeOnly(t *testing.T) {
+	spec := &pkgCluster.CreateClusterRequest{
+		Cloud: pkgCluster.Google,
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterGKE: &pkgClusterGoogle.CreateClusterGKE{
+				NodePools: map[string]*pkgClusterGoogle.NodePool{
+					"pool1": {Autoscaling: true, MinCount: 1, MaxCount: 5, Count: 3, NodeInstanceType: "n1-standard-2"},
+				},
+			},
+		},
+	}
+
+	err := overrideNodePool(spec, "pool1", &pkgCluster.NodePoolOverride{InstanceType: "n1-standard-4"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := pkgClusterGoogle.NodePool{Autoscaling: true, MinCount: 1, MaxCount: 5, Count: 3, NodeInstanceType: "n1-standard-4"}
+	if nodePool := *spec.Properties.CreateClusterGKE.NodePools["pool1"]; nodePool != expected {
+		t.Errorf("expected node pool %+v, got %+v", expected, nodePool)
+	}
+}
+
+func TestOverrideNodePool_AutoscalingNotSupported(t *testing.T) {
+	spec := &pkgCluster.CreateClusterRequest{
+		Cloud: pkgCluster.Alibaba,
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterACSK: &acsk.CreateClusterACSK{
+				NodePools: acsk.NodePools{
+					"pool1": {Count: 3, InstanceType: "ecs.sn1.large"},
+				},
+			},
+		},
+	}
+
+	autoscaling := true
+	err := overrideNodePool(spec, "pool1", &pkgCluster.NodePoolOverride{Autoscaling: &autoscaling})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if err := acsk.ValidateNodePools(spec.Properties.CreateClusterACSK.NodePools); err != pkgErrors.ErrorAlibabaNoAutoscaling {
+		t.Errorf("expected the autoscaling to be rejected by validation, got: %v", err)
+	}
+}
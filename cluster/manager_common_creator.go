@@ -47,3 +47,8 @@ func (c *commonCreator) Prepare(ctx context.Context) (CommonCluster, error) {
 func (c *commonCreator) Create(ctx context.Context) error {
 	return c.cluster.CreateCluster()
 }
+
+// Resources implements the creationResourcer interface.
+func (c *commonCreator) Resources() (*cluster.ClusterResources, error) {
+	return getSpecResources(c.request)
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 	"github.com/goph/emperror"
 )
 
@@ -33,6 +34,7 @@ type commonUpdateValidationError struct {
 
 	invalidRequest     bool
 	preconditionFailed bool
+	unchanged          bool
 }
 
 func (e *commonUpdateValidationError) Error() string {
@@ -47,6 +49,11 @@ func (e *commonUpdateValidationError) IsPreconditionFailed() bool {
 	return e.preconditionFailed
 }
 
+// Unchanged tells whether the update request matches the stored cluster.
+func (e *commonUpdateValidationError) Unchanged() bool {
+	return e.unchanged
+}
+
 // NewCommonClusterUpdater returns a new cluster creator instance.
 func NewCommonClusterUpdater(request *cluster.UpdateClusterRequest, cluster CommonCluster, userID uint) *commonUpdater {
 	return &commonUpdater{
@@ -85,23 +92,85 @@ func (c *commonUpdater) Validate(ctx context.Context) error {
 
 // Prepare implements the clusterUpdater interface.
 func (c *commonUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
+	if err := c.prepareRequest(); err != nil {
+		return nil, err
+	}
+
+	return c.cluster, c.cluster.Persist(cluster.Updating, cluster.UpdatingMessage)
+}
+
+// Plan implements the clusterUpdatePlanner interface.
+func (c *commonUpdater) Plan(ctx context.Context) (*cluster.UpdatePlanResponse, error) {
+	if err := c.prepareRequest(); err != nil {
+		return nil, err
+	}
+
+	return planClusterUpdate(c.cluster, c.request)
+}
+
+// prepareRequest adds the defaults to the update request and validates it against the stored cluster.
+func (c *commonUpdater) prepareRequest() error {
 	c.cluster.AddDefaultsToUpdate(c.request)
 
 	if err := c.cluster.CheckEqualityToUpdate(c.request); err != nil {
-		return nil, &commonUpdateValidationError{
+		return &commonUpdateValidationError{
 			msg:            err.Error(),
 			invalidRequest: true,
+			unchanged:      err == pkgErrors.ErrorNotDifferentInterfaces,
 		}
 	}
 
-	if err := c.request.Validate(); err != nil {
-		return nil, &commonUpdateValidationError{
+	c.request.ResetOtherClouds()
+
+	distribution, err := GetDistribution(c.cluster.GetCloud())
+	if err != nil {
+		return &commonUpdateValidationError{
 			msg:            err.Error(),
 			invalidRequest: true,
 		}
 	}
 
-	return c.cluster, c.cluster.Persist(cluster.Updating, cluster.UpdatingMessage)
+	if distribution.ValidateUpdate == nil {
+		return &commonUpdateValidationError{
+			msg:            pkgErrors.ErrorNotSupportedCloudType.Error(),
+			invalidRequest: true,
+		}
+	}
+
+	if err := distribution.ValidateUpdate(c.request); err != nil {
+		return &commonUpdateValidationError{
+			msg:            err.Error(),
+			invalidRequest: true,
+		}
+	}
+
+	return nil
+}
+
+// Resources implements the updateResourcer interface.
+func (c *commonUpdater) Resources() (*cluster.ClusterResources, *cluster.ClusterResources, error) {
+	current, err := getClusterResources(c.cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the node pools of the cluster are not managed by Pipeline
+	if len(current.NodePools) == 0 {
+		return current, current, nil
+	}
+
+	location, nodePools, err := getUpdatedNodePools(c.cluster, c.request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requested := &cluster.ClusterResources{
+		Cloud:     current.Cloud,
+		Location:  location,
+		NodePools: getNodePoolPlans(nodePools),
+	}
+
+	return current, requested, nil
 }
 
 // Update implements the clusterUpdater interface.
@@ -17,6 +17,7 @@ package cluster
 import (
 	"context"
 	stderrors "errors"
+	"time"
 
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	"github.com/banzaicloud/pipeline/secret"
@@ -33,6 +34,11 @@ type CreationContext struct {
 	Provider       string
 	SecretID       string
 	PostHooks      []PostFunctioner
+	Labels         map[string]string
+	TTL            string
+	ExpiresAt      *time.Time
+
+	DeletionProtection bool
 }
 
 var ErrAlreadyExists = stderrors.New("cluster already exists with this name")
@@ -61,8 +67,17 @@ func (m *Manager) CreateCluster(ctx context.Context, creationCtx CreationContext
 		return nil, err
 	}
 
+	if err := ValidateLabels(creationCtx.Labels); err != nil {
+		return nil, err
+	}
+
+	expiresAt, err := NewExpiration(creationCtx.TTL, creationCtx.ExpiresAt, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Info("validating secret")
-	err := m.secrets.ValidateSecretType(creationCtx.OrganizationID, creationCtx.SecretID, creationCtx.Provider)
+	err = m.secrets.ValidateSecretType(creationCtx.OrganizationID, creationCtx.SecretID, creationCtx.Provider)
 	if err != nil {
 		return nil, err
 	}
@@ -74,23 +89,49 @@ func (m *Manager) CreateCluster(ctx context.Context, creationCtx CreationContext
 	}
 
 	logger.Info("creation context is valid")
+
+	logger.Info("checking organization quota")
+	releaseQuota, err := m.checkCreationQuota(ctx, creationCtx.OrganizationID, creator)
+	if err != nil {
+		return nil, err
+	}
+
 	logger.Info("preparing cluster creation")
 
 	cluster, err := creator.Prepare(ctx)
+
+	// the stored cluster is counted by the quota checks waiting for the lock
+	releaseQuota()
+
 	if err != nil {
 		return nil, err
 	}
 
-	if err := cluster.UpdateStatus(pkgCluster.Creating, pkgCluster.CreatingMessage); err != nil {
+	// the cluster is stored by now, it must not be left behind in CREATING status if its creation cannot be started
+	lock, err := m.lockCluster(ctx, cluster.GetID(), pkgCluster.OperationCreate)
+	if err != nil {
+		m.failClusterCreation(ctx, cluster, err)
+
 		return nil, err
 	}
 
+	if err := m.initCluster(ctx, cluster, creationCtx, expiresAt); err != nil {
+		m.failClusterCreation(ctx, cluster, err)
+		lock.Release()
+
+		return nil, err
+	}
+
+	operation := m.startOperation(ctx, cluster, pkgCluster.OperationCreate, creationCtx.UserID, lock)
+
+	logger = logger.WithField("operation", operation.ID())
 	logger.Info("creating cluster")
 
 	go func() {
 		defer emperror.HandleRecover(m.errorHandler)
 
-		err := m.createCluster(ctx, cluster, creator, creationCtx.PostHooks, logger)
+		err := m.createCluster(ctx, cluster, creator, creationCtx.PostHooks, operation, logger)
+		operation.Finish(err)
 		if err != nil {
 			logger.Errorf("failed to create cluster: %s", err.Error())
 		}
@@ -99,6 +140,40 @@ func (m *Manager) CreateCluster(ctx context.Context, creationCtx CreationContext
 	return cluster, nil
 }
 
+// initCluster sets the status and the metadata of a stored cluster before creating it.
+func (m *Manager) initCluster(ctx context.Context, cluster CommonCluster, creationCtx CreationContext, expiresAt *time.Time) error {
+	if err := cluster.UpdateStatus(pkgCluster.Creating, pkgCluster.CreatingMessage); err != nil {
+		return err
+	}
+
+	if len(creationCtx.Labels) != 0 {
+		if err := m.SetClusterLabels(ctx, cluster, creationCtx.Labels); err != nil {
+			return err
+		}
+	}
+
+	if expiresAt != nil {
+		if err := m.SetClusterExpiration(ctx, cluster, expiresAt); err != nil {
+			return err
+		}
+	}
+
+	if creationCtx.DeletionProtection {
+		if _, err := m.SetClusterDeletionProtection(ctx, cluster, true, creationCtx.UserID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failClusterCreation marks a stored cluster as failed when its creation cannot be started.
+func (m *Manager) failClusterCreation(ctx context.Context, cluster CommonCluster, err error) {
+	if err := cluster.UpdateStatus(pkgCluster.Error, err.Error()); err != nil {
+		m.getErrorHandler(ctx).Handle(err)
+	}
+}
+
 func (m *Manager) assertNotExists(ctx CreationContext) error {
 	exists, err := m.clusters.Exists(ctx.OrganizationID, ctx.Name)
 	if err != nil {
@@ -117,11 +192,13 @@ func (m *Manager) createCluster(
 	cluster CommonCluster,
 	creator clusterCreator,
 	postHooks []PostFunctioner,
+	operation *clusterOperation,
 	logger logrus.FieldLogger,
 ) error {
 	// Check if public ssh key is needed for the cluster. If so and there is generate one and store it Vault
 	if len(cluster.GetSshSecretId()) == 0 && cluster.RequiresSshPublicKey() {
 		logger.Info("generating SSH Key for the cluster")
+		operation.Step("GenerateSSHKey", "generating SSH key for the cluster")
 
 		sshKey, err := secret.GenerateSSHKeyPair()
 		if err != nil {
@@ -138,6 +215,8 @@ func (m *Manager) createCluster(
 		}
 	}
 
+	operation.Step("CreateCluster", "creating cluster at the provider")
+
 	err := creator.Create(ctx)
 	if err != nil {
 		cluster.UpdateStatus(pkgCluster.Error, err.Error())
@@ -152,8 +231,14 @@ func (m *Manager) createCluster(
 		postHookFunctions = append(postHookFunctions, postHooks...)
 	}
 
-	err = RunPostHooks(postHookFunctions, cluster)
+	operation.Step("RunPostHooks", "running cluster posthooks")
+
+	postHookStates, err := m.resetPostHooks(cluster, postHookFunctions)
+	if err != nil {
+		return errors.Wrap(err, "error during recording cluster posthooks")
+	}
 
+	err = m.runPostHooks(ctx, cluster, pkgCluster.Creating, postHookStates, postHookFunctions)
 	if err != nil {
 		return errors.Wrap(err, "error during running cluster posthooks")
 	}
@@ -0,0 +1,219 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type customPostHookRepository interface {
+	FindByOrganization(organizationID uint) ([]*intCluster.CustomPostHookModel, error)
+	FindOneByName(organizationID uint, name string) (*intCluster.CustomPostHookModel, error)
+	Save(postHook *intCluster.CustomPostHookModel) error
+	Delete(organizationID uint, name string) error
+}
+
+type customPostHookExistsError struct {
+	name string
+}
+
+func (e *customPostHookExistsError) Error() string {
+	return fmt.Sprintf("posthook %q already exists", e.name)
+}
+
+func (e *customPostHookExistsError) Conflict() bool {
+	return true
+}
+
+// GetCustomPostHooks returns the user-defined posthooks of an organization.
+func (m *Manager) GetCustomPostHooks(ctx context.Context, organizationID uint) ([]*pkgCluster.CustomPostHookResponse, error) {
+	postHooks, err := m.customHooks.FindByOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*pkgCluster.CustomPostHookResponse, 0, len(postHooks))
+	for _, postHook := range postHooks {
+		entity, err := postHook.ConvertModelToEntity()
+		if err != nil {
+			return nil, emperror.With(errors.Wrap(err, "could not convert custom posthook"), "posthook", postHook.Name)
+		}
+
+		response = append(response, entity)
+	}
+
+	return response, nil
+}
+
+// GetCustomPostHook returns a user-defined posthook of an organization.
+func (m *Manager) GetCustomPostHook(ctx context.Context, organizationID uint, name string) (*pkgCluster.CustomPostHookResponse, error) {
+	postHook, err := m.customHooks.FindOneByName(organizationID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := postHook.ConvertModelToEntity()
+	if err != nil {
+		return nil, emperror.With(errors.Wrap(err, "could not convert custom posthook"), "posthook", name)
+	}
+
+	return response, nil
+}
+
+// CreateCustomPostHook adds a user-defined posthook to an organization.
+// The name of the posthook cannot be the same as the name of a built-in posthook.
+func (m *Manager) CreateCustomPostHook(
+	ctx context.Context,
+	organizationID uint,
+	request *pkgCluster.CustomPostHook,
+	userID uint,
+) (*pkgCluster.CustomPostHookResponse, error) {
+	if err := validateCustomPostHook(request); err != nil {
+		return nil, err
+	}
+
+	_, err := m.customHooks.FindOneByName(organizationID, request.Name)
+	if err == nil {
+		return nil, errors.WithStack(&customPostHookExistsError{name: request.Name})
+	} else if !isNotFoundError(err) {
+		return nil, err
+	}
+
+	postHook := &intCluster.CustomPostHookModel{
+		OrganizationID: organizationID,
+		CreatedBy:      userID,
+	}
+
+	return m.saveCustomPostHook(ctx, postHook, request)
+}
+
+// UpdateCustomPostHook replaces the definition of a user-defined posthook of an organization.
+// Clusters already created with the posthook are not affected until the posthook is run again.
+func (m *Manager) UpdateCustomPostHook(
+	ctx context.Context,
+	organizationID uint,
+	request *pkgCluster.CustomPostHook,
+) (*pkgCluster.CustomPostHookResponse, error) {
+	if err := validateCustomPostHook(request); err != nil {
+		return nil, err
+	}
+
+	postHook, err := m.customHooks.FindOneByName(organizationID, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.saveCustomPostHook(ctx, postHook, request)
+}
+
+func (m *Manager) saveCustomPostHook(
+	ctx context.Context,
+	postHook *intCluster.CustomPostHookModel,
+	request *pkgCluster.CustomPostHook,
+) (*pkgCluster.CustomPostHookResponse, error) {
+	if err := postHook.SetPostHook(request); err != nil {
+		return nil, emperror.With(errors.Wrap(err, "could not marshal custom posthook"), "posthook", request.Name)
+	}
+
+	if err := m.customHooks.Save(postHook); err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": postHook.OrganizationID,
+		"posthook":     postHook.Name,
+		"chart":        postHook.Chart,
+	}).Info("custom posthook saved")
+
+	return postHook.ConvertModelToEntity()
+}
+
+// DeleteCustomPostHook removes a user-defined posthook of an organization.
+func (m *Manager) DeleteCustomPostHook(ctx context.Context, organizationID uint, name string) error {
+	return m.customHooks.Delete(organizationID, name)
+}
+
+func validateCustomPostHook(postHook *pkgCluster.CustomPostHook) error {
+	if _, ok := HookMap[postHook.Name]; ok {
+		return pkgCluster.NewValidationError(fmt.Sprintf("%q is the name of a built-in posthook", postHook.Name))
+	}
+
+	return postHook.Validate()
+}
+
+// GetPostHookFunctions returns the posthook functions requested for a cluster of an organization.
+// Built-in posthooks take precedence over the user-defined posthooks of the organization,
+// posthooks which exist neither way are skipped.
+func (m *Manager) GetPostHookFunctions(ctx context.Context, organizationID uint, postHooks pkgCluster.PostHooks) ([]PostFunctioner, error) {
+	logger := m.getLogger(ctx).WithField("organization", organizationID)
+
+	var functions []PostFunctioner
+
+	for name, params := range postHooks {
+		function, err := m.getPostHookFunction(organizationID, name, params)
+		if isNotFoundError(err) {
+			logger.Warnf("there's no function with this name [%s]", name)
+
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		logger.Infof("posthook function: %s", function)
+		logger.Debugf("posthook params: %#v", params)
+
+		functions = append(functions, function)
+	}
+
+	return functions, nil
+}
+
+// getPostHookFunction returns a built-in or a user-defined posthook function initialized with the given params.
+func (m *Manager) getPostHookFunction(organizationID uint, name string, params pkgCluster.PostHookParam) (PostFunctioner, error) {
+	if function := NewPostHookFunction(name, params); function != nil {
+		return function, nil
+	}
+
+	model, err := m.customHooks.FindOneByName(organizationID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	postHook, err := model.PostHook()
+	if err != nil {
+		return nil, emperror.With(errors.Wrap(err, "could not unmarshal custom posthook"), "posthook", name)
+	}
+
+	function, err := NewCustomPostFunction(postHook, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return function, nil
+}
+
+// isNotFoundError checks whether an error is about a resource not being found.
+func isNotFoundError(err error) bool {
+	e, ok := errors.Cause(err).(interface{ NotFound() bool })
+
+	return ok && e.NotFound()
+}
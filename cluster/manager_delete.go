@@ -32,7 +32,7 @@ import (
 const retry = 3
 
 // DeleteCluster deletes a cluster.
-func (m *Manager) DeleteCluster(ctx context.Context, cluster CommonCluster, force bool, kubeProxyCache *sync.Map) error {
+func (m *Manager) DeleteCluster(ctx context.Context, cluster CommonCluster, userID uint, force bool, kubeProxyCache *sync.Map) error {
 	errorHandler := emperror.HandlerWith(
 		m.getErrorHandler(ctx),
 		"organization", cluster.GetOrganizationId(),
@@ -40,10 +40,26 @@ func (m *Manager) DeleteCluster(ctx context.Context, cluster CommonCluster, forc
 		"force", force,
 	)
 
+	lock, err := m.lockCluster(ctx, cluster.GetID(), pkgCluster.OperationDelete)
+	if err != nil {
+		return err
+	}
+
+	// deletion protection can not be overridden by force,
+	// it is checked while holding the lock so that an update enabling it cannot slip in before the deletion
+	if err := m.assertNotDeletionProtected(ctx, cluster); err != nil {
+		lock.Release()
+
+		return err
+	}
+
+	operation := m.startOperation(ctx, cluster, pkgCluster.OperationDelete, userID, lock)
+
 	go func() {
 		defer emperror.HandleRecover(m.errorHandler)
 
-		err := m.deleteCluster(ctx, cluster, force, kubeProxyCache)
+		err := m.deleteCluster(ctx, cluster, force, kubeProxyCache, operation)
+		operation.Finish(err)
 		if err != nil {
 			errorHandler.Handle(err)
 		}
@@ -104,11 +120,12 @@ func deleteDnsRecordsOwnedByCluster(cluster CommonCluster) error {
 	return nil
 }
 
-func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, force bool, kubeProxyCache *sync.Map) error {
+func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, force bool, kubeProxyCache *sync.Map, operation *clusterOperation) error {
 	logger := m.getLogger(ctx).WithFields(logrus.Fields{
 		"organization": cluster.GetOrganizationId(),
 		"cluster":      cluster.GetName(),
 		"force":        force,
+		"operation":    operation.ID(),
 	})
 
 	logger.Info("deleting cluster")
@@ -135,6 +152,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 
 	if c != nil {
 		// delete deployments
+		operation.Step("DeleteDeployments", "deleting deployments and resources")
 		err = helm.DeleteAllDeployment(c)
 		if err != nil {
 			if force {
@@ -158,9 +176,11 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 	}
 
 	// clean up dns registrations
+	operation.Step("DeleteDNSRecords", "deleting DNS records owned by the cluster")
 	err = deleteDnsRecordsOwnedByCluster(cluster)
 
 	// delete cluster
+	operation.Step("DeleteCluster", "deleting cluster at the provider")
 	err = cluster.DeleteCluster()
 	if err != nil {
 		if !force {
@@ -174,11 +194,14 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 
 	// delete from proxy from kubeProxyCache if any
 	// TODO: this should be handled somewhere else
-	kubeProxyCache.Delete(fmt.Sprint(cluster.GetOrganizationId(), "-", cluster.GetID()))
+	if kubeProxyCache != nil {
+		kubeProxyCache.Delete(fmt.Sprint(cluster.GetOrganizationId(), "-", cluster.GetID()))
+	}
 
 	// delete cluster from database
 	orgID := cluster.GetOrganizationId()
 	deleteName := cluster.GetName()
+	operation.Step("DeleteFromDatabase", "deleting cluster from the database")
 	err = cluster.DeleteFromDatabase()
 	if err != nil {
 		if !force {
@@ -192,6 +215,7 @@ func (m *Manager) deleteCluster(ctx context.Context, cluster CommonCluster, forc
 
 	// clean statestore
 	logger.Info("cleaning cluster's statestore folder")
+	operation.Step("CleanStateStore", "cleaning cluster's statestore folder")
 	if err := CleanStateStore(deleteName); err != nil {
 		return emperror.Wrap(err, "cleaning cluster statestore failed")
 	}
@@ -0,0 +1,85 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+type deletionProtectedError struct {
+	clusterName string
+}
+
+func (e *deletionProtectedError) Error() string {
+	return fmt.Sprintf("cluster %s is protected from deletion, disable its deletion protection first", e.clusterName)
+}
+
+func (e *deletionProtectedError) PreconditionFailed() bool {
+	return true
+}
+
+// GetClusterDeletionProtection tells whether a cluster is protected from being deleted.
+func (m *Manager) GetClusterDeletionProtection(ctx context.Context, organizationID uint, clusterID uint) (bool, error) {
+	clusterModel, err := m.clusters.FindOneByID(organizationID, clusterID)
+	if err != nil {
+		return false, err
+	}
+
+	return clusterModel.IsDeletionProtected(), nil
+}
+
+// SetClusterDeletionProtection enables or disables the deletion protection of a cluster.
+// It returns whether the setting has changed.
+func (m *Manager) SetClusterDeletionProtection(ctx context.Context, cluster CommonCluster, enabled bool, userID uint) (bool, error) {
+	protected, err := m.GetClusterDeletionProtection(ctx, cluster.GetOrganizationId(), cluster.GetID())
+	if err != nil {
+		return false, err
+	}
+
+	if protected == enabled {
+		return false, nil
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+		"user":         userID,
+	}).Infof("setting cluster deletion protection to %t", enabled)
+
+	err = m.clusters.SetDeletionProtection(cluster.GetID(), enabled, userID)
+	if err != nil {
+		return false, emperror.Wrap(err, "could not set cluster deletion protection")
+	}
+
+	return true, nil
+}
+
+// assertNotDeletionProtected returns an error if a cluster is protected from being deleted.
+func (m *Manager) assertNotDeletionProtected(ctx context.Context, cluster CommonCluster) error {
+	protected, err := m.GetClusterDeletionProtection(ctx, cluster.GetOrganizationId(), cluster.GetID())
+	if err != nil {
+		return emperror.Wrap(err, "could not check cluster deletion protection")
+	}
+
+	if protected {
+		return &deletionProtectedError{clusterName: cluster.GetName()}
+	}
+
+	return nil
+}
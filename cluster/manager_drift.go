@@ -0,0 +1,289 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type driftRepository interface {
+	FindByClusterID(clusterID uint) (*intCluster.DriftReportModel, error)
+	Save(report *intCluster.DriftReportModel) error
+}
+
+// GetClusterDrift returns the latest drift report of a cluster.
+func (m *Manager) GetClusterDrift(ctx context.Context, cluster CommonCluster) (*pkgCluster.ClusterDriftResponse, error) {
+	report, err := m.drifts.FindByClusterID(cluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	if report == nil {
+		return &pkgCluster.ClusterDriftResponse{
+			Findings: []pkgCluster.DriftFinding{},
+		}, nil
+	}
+
+	return report.ConvertModelToEntity(), nil
+}
+
+// CheckClusterDrift compares the recorded state of a cluster with the state reported by the provider
+// and by the Kubernetes nodes of the cluster, and records the differences.
+func (m *Manager) CheckClusterDrift(ctx context.Context, cluster CommonCluster, now time.Time) (*pkgCluster.ClusterDriftResponse, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+	})
+
+	previous, err := m.drifts.FindByClusterID(cluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &intCluster.DriftReportModel{
+		ClusterID: cluster.GetID(),
+		CheckedAt: now,
+	}
+
+	findings, err := m.detectClusterDrift(cluster)
+	if err != nil {
+		logger.Warnf("could not check cluster drift: %s", err.Error())
+
+		report.Error = err.Error()
+	}
+
+	// findings which were already present keep the time of their first detection
+	detectedAt := make(map[string]time.Time)
+	if previous != nil {
+		for _, finding := range previous.Findings {
+			detectedAt[finding.NodePool+"/"+finding.Field] = finding.DetectedAt
+		}
+	}
+
+	for _, finding := range findings {
+		findingDetectedAt, ok := detectedAt[finding.NodePool+"/"+finding.Field]
+		if !ok {
+			findingDetectedAt = now
+
+			logger.WithField("nodePool", finding.NodePool).Warnf(
+				"cluster drift detected in %s: expected %s, actual %s",
+				finding.Field,
+				finding.Expected,
+				finding.Actual,
+			)
+		}
+
+		report.Findings = append(report.Findings, intCluster.DriftFindingModel{
+			NodePool:   finding.NodePool,
+			Field:      finding.Field,
+			Expected:   finding.Expected,
+			Actual:     finding.Actual,
+			DetectedAt: findingDetectedAt,
+		})
+	}
+
+	if err := m.drifts.Save(report); err != nil {
+		return nil, err
+	}
+
+	return report.ConvertModelToEntity(), nil
+}
+
+func (m *Manager) detectClusterDrift(cluster CommonCluster) ([]pkgCluster.DriftFinding, error) {
+	status, err := cluster.GetStatus()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster status")
+	}
+
+	observed := observedCluster{Ready: true}
+
+	details, err := cluster.GetClusterDetails()
+	if err == pkgErrors.ErrorClusterNotReady {
+		observed.Ready = false
+
+		return detectDrift(status, observed), nil
+	} else if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster details")
+	}
+
+	observed.MasterVersion = details.MasterVersion
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get k8s config")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not create k8s client")
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(meta_v1.ListOptions{})
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not list nodes")
+	}
+
+	observed.Nodes = nodes.Items
+
+	return detectDrift(status, observed), nil
+}
+
+// StartDriftReconciler periodically checks the running clusters for drift
+// and optionally corrects it by applying the recorded spec again.
+func (m *Manager) StartDriftReconciler(interval time.Duration, correct bool) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		for now := range ticker.C {
+			err := m.ReconcileClusterDrift(context.Background(), now, correct)
+			if err != nil {
+				m.errorHandler.Handle(err)
+			}
+		}
+	}()
+}
+
+// ReconcileClusterDrift checks every running cluster for drift.
+func (m *Manager) ReconcileClusterDrift(ctx context.Context, now time.Time, correct bool) error {
+	logger := m.getLogger(ctx)
+
+	clusterModels, err := m.clusters.All()
+	if err != nil {
+		return err
+	}
+
+	for _, clusterModel := range clusterModels {
+		if clusterModel.Status != pkgCluster.Running && clusterModel.Status != pkgCluster.Warning {
+			continue
+		}
+
+		logger := logger.WithFields(logrus.Fields{
+			"organization": clusterModel.OrganizationId,
+			"cluster":      clusterModel.Name,
+		})
+
+		cluster, err := m.getClusterFromModel(clusterModel)
+		if err != nil {
+			logger.Errorf("converting cluster model to common cluster failed: %s", err.Error())
+
+			continue
+		}
+
+		drift, err := m.CheckClusterDrift(ctx, cluster, now)
+		if err != nil {
+			m.getErrorHandler(ctx).Handle(emperror.With(
+				emperror.Wrap(err, "could not check cluster drift"),
+				"organization", clusterModel.OrganizationId,
+				"cluster", clusterModel.ID,
+			))
+
+			continue
+		}
+
+		if correct && drift.Drifted && isCorrectable(drift) {
+			logger.Info("correcting cluster drift")
+
+			err := m.CorrectClusterDrift(ctx, cluster, clusterModel.CreatedBy)
+			if err != nil {
+				m.getErrorHandler(ctx).Handle(emperror.With(
+					emperror.Wrap(err, "could not correct cluster drift"),
+					"organization", clusterModel.OrganizationId,
+					"cluster", clusterModel.ID,
+				))
+			}
+		}
+	}
+
+	return nil
+}
+
+// isCorrectable tells whether the drift of a cluster can be corrected by applying the recorded spec again.
+func isCorrectable(drift *pkgCluster.ClusterDriftResponse) bool {
+	for _, finding := range drift.Findings {
+		if finding.Field == pkgCluster.DriftFieldCount || finding.Field == pkgCluster.DriftFieldNodePool {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CorrectClusterDrift applies the recorded spec of a cluster to the provider again.
+func (m *Manager) CorrectClusterDrift(ctx context.Context, cluster CommonCluster, userID uint) error {
+	spec, err := GetClusterSpec(cluster)
+	if err != nil {
+		return err
+	}
+
+	request, err := NewUpdateRequestFromSpec(cluster, spec)
+	if err != nil {
+		return err
+	}
+
+	updateCtx := UpdateContext{
+		OrganizationID: cluster.GetOrganizationId(),
+		UserID:         userID,
+		ClusterID:      cluster.GetID(),
+	}
+
+	return m.UpdateCluster(ctx, updateCtx, &driftCorrector{
+		cluster: cluster,
+		request: request,
+		userID:  userID,
+	})
+}
+
+// driftCorrector updates a cluster with its recorded spec.
+// Unlike the common updater it does not reject requests matching the stored cluster,
+// since the provider is compared against the request during the update.
+type driftCorrector struct {
+	cluster CommonCluster
+	request *pkgCluster.UpdateClusterRequest
+	userID  uint
+}
+
+func (c *driftCorrector) Validate(ctx context.Context) error {
+	status, err := c.cluster.GetStatus()
+	if err != nil {
+		return emperror.Wrap(err, "could not get cluster status")
+	}
+
+	if status.Status != pkgCluster.Running && status.Status != pkgCluster.Warning {
+		return pkgCluster.NewPreconditionFailedError(
+			fmt.Sprintf("cluster is not in %s or %s state yet", pkgCluster.Running, pkgCluster.Warning),
+		)
+	}
+
+	return nil
+}
+
+func (c *driftCorrector) Prepare(ctx context.Context) (CommonCluster, error) {
+	return c.cluster, nil
+}
+
+func (c *driftCorrector) Update(ctx context.Context) error {
+	return c.cluster.UpdateCluster(c.request, c.userID)
+}
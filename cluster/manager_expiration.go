@@ -0,0 +1,180 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/banzaicloud/pipeline/notify"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+// NewExpiration calculates the expiration time of a cluster either from a TTL (counted from now)
+// or from an absolute time. It returns nil if neither of them is set.
+func NewExpiration(ttl string, expiresAt *time.Time, now time.Time) (*time.Time, error) {
+	if ttl != "" && expiresAt != nil {
+		return nil, pkgCluster.NewValidationError("either ttl or expiresAt can be set, not both")
+	}
+
+	if ttl != "" {
+		duration, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, pkgCluster.NewValidationError(fmt.Sprintf("invalid ttl [%s]: %s", ttl, err.Error()))
+		}
+
+		if duration <= 0 {
+			return nil, pkgCluster.NewValidationError("ttl must be positive")
+		}
+
+		t := now.Add(duration)
+
+		return &t, nil
+	}
+
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, pkgCluster.NewValidationError("expiresAt must be in the future")
+	}
+
+	return expiresAt, nil
+}
+
+// SetClusterExpiration sets (or with a nil value removes) the expiration time of a cluster.
+func (m *Manager) SetClusterExpiration(ctx context.Context, cluster CommonCluster, expiresAt *time.Time) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+	})
+
+	if expiresAt != nil {
+		logger.Infof("cluster expires at %s", expiresAt.Format(time.RFC3339))
+	} else {
+		logger.Info("removing cluster expiration")
+	}
+
+	err := m.clusters.SetExpiration(cluster.GetID(), expiresAt)
+	if err != nil {
+		return emperror.Wrap(err, "could not set cluster expiration")
+	}
+
+	return nil
+}
+
+// StartClusterReaper periodically deletes expired clusters and warns about clusters which are about to expire.
+func (m *Manager) StartClusterReaper(interval time.Duration, warningPeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		for now := range ticker.C {
+			err := m.ReapExpiredClusters(context.Background(), now, warningPeriod)
+			if err != nil {
+				m.errorHandler.Handle(err)
+			}
+		}
+	}()
+}
+
+// ReapExpiredClusters deletes the clusters which are expired at the given time
+// and warns (once) about the ones expiring within the warning period.
+func (m *Manager) ReapExpiredClusters(ctx context.Context, now time.Time, warningPeriod time.Duration) error {
+	logger := m.getLogger(ctx)
+
+	clusterModels, err := m.clusters.FindExpiringBefore(now.Add(warningPeriod))
+	if err != nil {
+		return err
+	}
+
+	for _, clusterModel := range clusterModels {
+		logger := logger.WithFields(logrus.Fields{
+			"organization": clusterModel.OrganizationId,
+			"cluster":      clusterModel.Name,
+		})
+
+		expiration := clusterModel.Expiration
+		if expiration == nil {
+			continue
+		}
+
+		cluster, err := m.getClusterFromModel(clusterModel)
+		if err != nil {
+			logger.Errorf("converting cluster model to common cluster failed: %s", err.Error())
+
+			continue
+		}
+
+		if expiration.ExpiresAt.After(now) {
+			if expiration.WarnedAt == nil {
+				m.warnClusterExpiring(ctx, cluster, expiration.ExpiresAt, now)
+			}
+
+			continue
+		}
+
+		switch clusterModel.Status {
+		case pkgCluster.Creating, pkgCluster.Updating, pkgCluster.Deleting:
+			logger.Infof("cluster is expired, but it is %s, deleting it later", clusterModel.Status)
+
+			continue
+		}
+
+		if clusterModel.IsDeletionProtected() {
+			logger.Debug("cluster is expired, but it is protected from deletion")
+
+			continue
+		}
+
+		logger.Info("deleting expired cluster")
+
+		err = m.DeleteCluster(ctx, cluster, clusterModel.CreatedBy, false, nil)
+		if err != nil {
+			m.getErrorHandler(ctx).Handle(emperror.With(
+				emperror.Wrap(err, "could not delete expired cluster"),
+				"organization", clusterModel.OrganizationId,
+				"cluster", clusterModel.ID,
+			))
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) warnClusterExpiring(ctx context.Context, cluster CommonCluster, expiresAt time.Time, now time.Time) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetName(),
+	})
+
+	logger.Warnf("cluster expires at %s", expiresAt.Format(time.RFC3339))
+
+	m.events.ClusterExpiring(cluster.GetID(), expiresAt)
+
+	message := fmt.Sprintf(
+		"Cluster %s (organization %d) expires at %s and will be deleted, extend its TTL to keep it",
+		cluster.GetName(),
+		cluster.GetOrganizationId(),
+		expiresAt.Format(time.RFC3339),
+	)
+	if err := notify.SlackNotify(message); err != nil {
+		logger.Errorf("could not send cluster expiration warning: %s", err.Error())
+	}
+
+	if err := m.clusters.SetExpirationWarned(cluster.GetID(), now); err != nil {
+		m.getErrorHandler(ctx).Handle(err)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestNewExpiration(t *testing.T) {
+	now := time.Date(2018, 11, 15, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		ttl       string
+		expiresAt *time.Time
+		expected  *time.Time
+		invalid   bool
+	}{
+		{name: "none"},
+		{name: "ttl", ttl: "1h", expected: &future},
+		{name: "expiresAt", expiresAt: &future, expected: &future},
+		{name: "both", ttl: "1h", expiresAt: &future, invalid: true},
+		{name: "malformed ttl", ttl: "one hour", invalid: true},
+		{name: "negative ttl", ttl: "-1h", invalid: true},
+		{name: "expiresAt in the past", expiresAt: &past, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expiresAt, err := NewExpiration(test.ttl, test.expiresAt, now)
+			if test.invalid {
+				if _, ok := err.(*pkgCluster.ValidationError); !ok {
+					t.Fatalf("expected a validation error, got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if (expiresAt == nil) != (test.expected == nil) || (expiresAt != nil && !expiresAt.Equal(*test.expected)) {
+				t.Errorf("expected expiration %v, got %v", test.expected, expiresAt)
+			}
+		})
+	}
+}
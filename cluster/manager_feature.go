@@ -0,0 +1,340 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/security"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// clusterFeature describes a feature of a cluster installed by a posthook.
+type clusterFeature struct {
+	// postHook installs the feature.
+	postHook string
+
+	// releases are the Helm releases installed by the feature, the first one is the main release
+	// which decides whether the feature is enabled.
+	// Secrets tagged with the main release are owned by the feature.
+	releases []string
+
+	// flag is the column of the cluster model which records whether the feature is enabled (optional).
+	flag string
+
+	// cleanup removes external resources of the feature after its releases are deleted (optional).
+	cleanup func(cluster CommonCluster)
+}
+
+var clusterFeatures = map[string]clusterFeature{
+	pkgCluster.FeatureMonitoring: {
+		postHook: pkgCluster.InstallMonitoring,
+		releases: []string{pipConfig.MonitorReleaseName},
+		flag:     "monitoring",
+	},
+	pkgCluster.FeatureLogging: {
+		postHook: pkgCluster.InstallLogging,
+		releases: []string{
+			pipConfig.LoggingReleaseName,
+			"pipeline-s3-output",
+			"pipeline-gcs-output",
+			"pipeline-azure-output",
+		},
+		flag: "logging",
+	},
+	pkgCluster.FeatureDashboard: {
+		postHook: pkgCluster.InstallKubernetesDashboardPostHook,
+		releases: []string{"dashboard"},
+	},
+	pkgCluster.FeatureAnchore: {
+		postHook: pkgCluster.InstallAnchoreImageValidator,
+		releases: []string{"anchore"},
+		cleanup: func(cluster CommonCluster) {
+			anchore.RemoveAnchoreUser(cluster.GetOrganizationId(), cluster.GetUID())
+		},
+	},
+}
+
+type featureNotFoundError struct {
+	name string
+}
+
+func (e *featureNotFoundError) Error() string {
+	return fmt.Sprintf("feature %q does not exist", e.name)
+}
+
+func (e *featureNotFoundError) NotFound() bool {
+	return true
+}
+
+// getClusterFeature returns the definition of a cluster feature.
+func getClusterFeature(name string) (clusterFeature, error) {
+	feature, ok := clusterFeatures[name]
+	if !ok {
+		return clusterFeature{}, errors.WithStack(&featureNotFoundError{name: name})
+	}
+
+	return feature, nil
+}
+
+// getPostHookFeature returns the definition of the cluster feature installed by a posthook.
+func getPostHookFeature(postHook string) (clusterFeature, bool) {
+	for _, feature := range clusterFeatures {
+		if feature.postHook == postHook {
+			return feature, true
+		}
+	}
+
+	return clusterFeature{}, false
+}
+
+// setClusterFeatureFlag records in the cluster model whether a feature is enabled.
+// Only the flag column is updated, so it must not be called while the cluster model may be saved concurrently.
+func (m *Manager) setClusterFeatureFlag(cluster CommonCluster, feature clusterFeature, enabled bool) error {
+	if feature.flag == "" {
+		return nil
+	}
+
+	return m.clusters.SetFeatureFlag(cluster.GetID(), feature.flag, enabled)
+}
+
+// GetClusterFeatures returns the features of a cluster and whether they are enabled.
+// The state of the features is read from the Helm releases of the cluster.
+func (m *Manager) GetClusterFeatures(ctx context.Context, cluster CommonCluster) ([]*pkgCluster.FeatureResponse, error) {
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get kubernetes config")
+	}
+
+	releases, err := getFeatureReleases(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(clusterFeatures))
+	for name := range clusterFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	response := make([]*pkgCluster.FeatureResponse, 0, len(names))
+	for _, name := range names {
+		feature := clusterFeatures[name]
+
+		item := &pkgCluster.FeatureResponse{
+			Name:     name,
+			PostHook: feature.postHook,
+		}
+
+		for i, releaseName := range feature.releases {
+			r, ok := releases[releaseName]
+			if !ok {
+				continue
+			}
+
+			status := r.GetInfo().GetStatus().GetCode()
+			if i == 0 {
+				item.Enabled = status == release.Status_DEPLOYED
+			}
+
+			item.Releases = append(item.Releases, pkgCluster.FeatureReleaseResponse{
+				Name:   releaseName,
+				Status: status.String(),
+			})
+		}
+
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+// SetClusterFeature enables or disables a feature of a cluster in the background.
+// Enabling a feature runs its posthook with the given params, disabling it deletes its Helm releases
+// together with the secrets created for them.
+func (m *Manager) SetClusterFeature(
+	ctx context.Context,
+	cluster CommonCluster,
+	name string,
+	request *pkgCluster.FeatureRequest,
+	userID uint,
+) error {
+	feature, err := getClusterFeature(name)
+	if err != nil {
+		return err
+	}
+
+	if request.Enabled {
+		function, err := m.getPostHookFunction(cluster.GetOrganizationId(), feature.postHook, request.Params)
+		if err != nil {
+			return err
+		}
+
+		return m.ReRunPostHooks(ctx, cluster, []PostFunctioner{function}, userID)
+	}
+
+	errorHandler := emperror.HandlerWith(
+		m.getErrorHandler(ctx),
+		"organization", cluster.GetOrganizationId(),
+		"user", userID,
+		"cluster", cluster.GetID(),
+		"feature", name,
+	)
+
+	lock, err := m.lockCluster(ctx, cluster.GetID(), pkgCluster.OperationFeature)
+	if err != nil {
+		return err
+	}
+
+	operation := m.startOperation(ctx, cluster, pkgCluster.OperationFeature, userID, lock)
+
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		err := m.disableClusterFeature(ctx, cluster, name, feature, operation)
+		operation.Finish(err)
+		if err != nil {
+			errorHandler.Handle(err)
+		}
+	}()
+
+	return nil
+}
+
+// disableClusterFeature deletes the Helm releases and the secrets of a cluster feature.
+func (m *Manager) disableClusterFeature(
+	ctx context.Context,
+	cluster CommonCluster,
+	name string,
+	feature clusterFeature,
+	operation *clusterOperation,
+) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+		"feature":      name,
+	})
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "could not get kubernetes config")
+	}
+
+	releases, err := getFeatureReleases(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	namespace := viper.GetString(pipConfig.PipelineSystemNamespace)
+	if r, ok := releases[feature.releases[0]]; ok && r.GetNamespace() != "" {
+		namespace = r.GetNamespace()
+	}
+
+	for _, releaseName := range feature.releases {
+		if _, ok := releases[releaseName]; !ok {
+			continue
+		}
+
+		logger.WithField("release", releaseName).Info("deleting feature release")
+		operation.Step("DeleteRelease", fmt.Sprintf("Deleting Helm release %s", releaseName))
+
+		if err := helm.DeleteDeployment(releaseName, kubeConfig); err != nil {
+			return emperror.With(errors.Wrap(err, "could not delete feature release"), "release", releaseName)
+		}
+	}
+
+	operation.Step("DeleteSecrets", "Deleting secrets")
+
+	if err := deleteFeatureSecrets(cluster, kubeConfig, feature.releases[0], namespace); err != nil {
+		return err
+	}
+
+	if feature.cleanup != nil {
+		feature.cleanup(cluster)
+	}
+
+	if err := m.setClusterFeatureFlag(cluster, feature, false); err != nil {
+		return emperror.Wrap(err, "could not update cluster")
+	}
+
+	logger.Info("feature disabled")
+
+	return nil
+}
+
+// getFeatureReleases returns the Helm releases of a cluster by name.
+func getFeatureReleases(kubeConfig []byte) (map[string]*release.Release, error) {
+	resp, err := helm.ListDeployments(nil, "", kubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not list helm releases")
+	}
+
+	releases := make(map[string]*release.Release)
+	for _, r := range resp.GetReleases() {
+		releases[r.GetName()] = r
+	}
+
+	return releases, nil
+}
+
+// deleteFeatureSecrets deletes the secrets created for a Helm release of a cluster from the secret store
+// and from the namespace of the release.
+func deleteFeatureSecrets(cluster CommonCluster, kubeConfig []byte, releaseName string, namespace string) error {
+	secrets, err := secret.Store.List(cluster.GetOrganizationId(), &pkgSecret.ListSecretsQuery{
+		Tags: []string{
+			fmt.Sprintf("clusterUID:%s", cluster.GetUID()),
+			fmt.Sprintf("release:%s", releaseName),
+		},
+	})
+	if err != nil {
+		return emperror.Wrap(err, "could not list feature secrets")
+	}
+
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return emperror.Wrap(err, "could not create kubernetes client")
+	}
+
+	for _, s := range secrets {
+		err := client.CoreV1().Secrets(namespace).Delete(s.Name, &metav1.DeleteOptions{})
+		if err != nil && !k8sapierrors.IsNotFound(err) {
+			return emperror.With(errors.Wrap(err, "could not delete kubernetes secret"), "secret", s.Name)
+		}
+
+		if err := secret.Store.Delete(cluster.GetOrganizationId(), s.ID); err != nil {
+			return emperror.With(err, "secret", s.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type featureFlagClusterRepository struct {
+	clusterRepository
+
+	flags map[string]bool
+}
+
+func (r *featureFlagClusterRepository) SetFeatureFlag(clusterID uint, flag string, enabled bool) error {
+	r.flags[flag] = enabled
+
+	return nil
+}
+
+type featureFlagCluster struct {
+	CommonCluster
+}
+
+func (c *featureFlagCluster) GetID() uint {
+	return 1
+}
+
+func TestClusterFeatures(t *testing.T) {
+	for name, feature := range clusterFeatures {
+		if _, ok := HookMap[feature.postHook]; !ok {
+			t.Errorf("feature %s: posthook %s does not exist", name, feature.postHook)
+		}
+
+		if len(feature.releases) == 0 {
+			t.Errorf("feature %s: expected at least one release", name)
+		}
+	}
+}
+
+func TestGetClusterFeature(t *testing.T) {
+	feature, err := getClusterFeature(pkgCluster.FeatureMonitoring)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if feature.postHook != pkgCluster.InstallMonitoring {
+		t.Errorf("expected posthook %s, got %s", pkgCluster.InstallMonitoring, feature.postHook)
+	}
+
+	_, err = getClusterFeature("unknown")
+	if !isNotFoundError(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+}
+
+func TestGetPostHookFeature(t *testing.T) {
+	feature, ok := getPostHookFeature(pkgCluster.InstallLogging)
+	if !ok {
+		t.Fatalf("expected a feature for posthook %s", pkgCluster.InstallLogging)
+	}
+
+	if feature.flag != "logging" {
+		t.Errorf("expected the logging flag, got %q", feature.flag)
+	}
+
+	if _, ok := getPostHookFeature(pkgCluster.StoreKubeConfig); ok {
+		t.Errorf("expected no feature for posthook %s", pkgCluster.StoreKubeConfig)
+	}
+}
+
+func TestManager_SetClusterFeatureFlag(t *testing.T) {
+	clusters := &featureFlagClusterRepository{flags: map[string]bool{}}
+	manager := &Manager{clusters: clusters}
+	cluster := &featureFlagCluster{}
+
+	err := manager.setClusterFeatureFlag(cluster, clusterFeatures[pkgCluster.FeatureMonitoring], true)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if enabled, ok := clusters.flags["monitoring"]; !ok || !enabled {
+		t.Errorf("expected the monitoring flag to be enabled, got %v", clusters.flags)
+	}
+
+	err = manager.setClusterFeatureFlag(cluster, clusterFeatures[pkgCluster.FeatureDashboard], false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if len(clusters.flags) != 1 {
+		t.Errorf("expected no flag for the dashboard feature, got %v", clusters.flags)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/banzaicloud/pipeline/model"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateLabels checks that cluster labels follow the Kubernetes label syntax.
+func ValidateLabels(clusterLabels map[string]string) error {
+	for key, value := range clusterLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+			return pkgCluster.NewValidationError(
+				fmt.Sprintf("invalid label key [%s]: %s", key, strings.Join(errs, "; ")),
+			)
+		}
+
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			return pkgCluster.NewValidationError(
+				fmt.Sprintf("invalid value for label [%s]: %s", key, strings.Join(errs, "; ")),
+			)
+		}
+	}
+
+	return nil
+}
+
+// ClusterMetadata is the data stored by Pipeline along with a cluster.
+type ClusterMetadata struct {
+	Labels             map[string]string
+	ExpiresAt          *time.Time
+	DeletionProtection bool
+}
+
+func newClusterMetadata(clusterModel *model.ClusterModel) ClusterMetadata {
+	return ClusterMetadata{
+		Labels:             clusterModel.GetLabels(),
+		ExpiresAt:          clusterModel.GetExpiresAt(),
+		DeletionProtection: clusterModel.IsDeletionProtected(),
+	}
+}
+
+// AddToStatus adds the metadata to the status of the cluster.
+func (m ClusterMetadata) AddToStatus(status *pkgCluster.GetClusterStatusResponse) {
+	status.Labels = m.Labels
+	status.ExpiresAt = m.ExpiresAt
+	status.DeletionProtection = m.DeletionProtection
+}
+
+// ListedCluster is a cluster instance along with its metadata.
+type ListedCluster struct {
+	CommonCluster
+
+	Metadata ClusterMetadata
+}
+
+// ListClusters returns the cluster instances for an organization ID along with their metadata.
+// If the selector is not empty only the clusters matching it are returned.
+func (m *Manager) ListClusters(ctx context.Context, organizationID uint, selector string) ([]ListedCluster, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"selector":     selector,
+	})
+
+	clusterModels, err := m.findClusterModelsBySelector(logger, organizationID, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]ListedCluster, 0, len(clusterModels))
+
+	for _, clusterModel := range clusterModels {
+		logger := logger.WithField("cluster", clusterModel.Name)
+		logger.Debug("converting cluster model to common cluster")
+
+		cluster, err := m.getClusterFromModel(clusterModel)
+		if err != nil {
+			logger.Errorf("converting cluster model to common cluster failed: %s", err.Error())
+
+			continue
+		}
+
+		clusters = append(clusters, ListedCluster{
+			CommonCluster: cluster,
+			Metadata:      newClusterMetadata(clusterModel),
+		})
+	}
+
+	return clusters, nil
+}
+
+// GetClusterMetadata returns the metadata of a cluster.
+func (m *Manager) GetClusterMetadata(ctx context.Context, organizationID uint, clusterID uint) (ClusterMetadata, error) {
+	clusterModel, err := m.clusters.FindOneByID(organizationID, clusterID)
+	if err != nil {
+		return ClusterMetadata{}, err
+	}
+
+	return newClusterMetadata(clusterModel), nil
+}
+
+// findClusterModelsBySelector returns the cluster models of an organization matching a label selector.
+// The labels, expiration and deletion protection of the clusters are loaded along with them.
+func (m *Manager) findClusterModelsBySelector(logger logrus.FieldLogger, organizationID uint, selector string) ([]*model.ClusterModel, error) {
+	labelSelector := labels.Everything()
+
+	if selector != "" {
+		var err error
+
+		labelSelector, err = labels.Parse(selector)
+		if err != nil {
+			return nil, pkgCluster.NewValidationError(fmt.Sprintf("invalid label selector: %s", err.Error()))
+		}
+	}
+
+	logger.Debug("fetching clusters from database")
+
+	clusterModels, err := m.clusters.FindByOrganization(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	matchingModels := clusterModels[:0]
+	for _, clusterModel := range clusterModels {
+		if labelSelector.Matches(labels.Set(clusterModel.GetLabels())) {
+			matchingModels = append(matchingModels, clusterModel)
+		}
+	}
+
+	return matchingModels, nil
+}
+
+// GetClusterLabels returns the labels of a cluster.
+func (m *Manager) GetClusterLabels(ctx context.Context, organizationID uint, clusterID uint) (map[string]string, error) {
+	clusterModel, err := m.clusters.FindOneByID(organizationID, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return clusterModel.GetLabels(), nil
+}
+
+// SetClusterLabels replaces the labels of a cluster.
+func (m *Manager) SetClusterLabels(ctx context.Context, cluster CommonCluster, clusterLabels map[string]string) error {
+	if err := ValidateLabels(clusterLabels); err != nil {
+		return err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+	}).Info("setting cluster labels")
+
+	err := m.clusters.SetLabels(cluster.GetID(), clusterLabels)
+	if err != nil {
+		return emperror.Wrap(err, "could not set cluster labels")
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/goph/emperror"
+)
+
+// clusterLockTTL is how long a cluster lock is valid without being renewed.
+// Locks are renewed every third of this period while the operation holding them is running.
+const clusterLockTTL = 2 * time.Minute
+
+type lockRepository interface {
+	Acquire(lock *intCluster.LockModel) error
+	SetOperation(lockID uint, operationID uint) error
+	Renew(lockID uint, expiresAt time.Time) error
+	Release(lockID uint) error
+}
+
+// clusterLock is held by the operation running on a cluster, so that no other operation can run on it at the same time.
+type clusterLock struct {
+	model        *intCluster.LockModel
+	locks        lockRepository
+	errorHandler emperror.Handler
+	done         chan struct{}
+
+	// lost is closed when the lock could not be renewed before it expired, err tells why.
+	lost chan struct{}
+	err  error
+}
+
+// lockCluster acquires the lock of a cluster and keeps renewing it until it is released.
+func (m *Manager) lockCluster(ctx context.Context, clusterID uint, operationType string) (*clusterLock, error) {
+	now := time.Now()
+
+	lock := &clusterLock{
+		model: &intCluster.LockModel{
+			ClusterID:     clusterID,
+			OperationType: operationType,
+			Holder:        lockHolder(),
+			AcquiredAt:    now,
+			ExpiresAt:     now.Add(clusterLockTTL),
+		},
+		locks: m.locks,
+		errorHandler: emperror.HandlerWith(
+			m.getErrorHandler(ctx),
+			"cluster", clusterID,
+			"operation", operationType,
+		),
+		done: make(chan struct{}),
+		lost: make(chan struct{}),
+	}
+
+	if err := m.locks.Acquire(lock.model); err != nil {
+		return nil, err
+	}
+
+	go lock.keepAlive()
+
+	return lock, nil
+}
+
+// lockHolder identifies the Pipeline instance holding a lock.
+func lockHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s/%d", hostname, os.Getpid())
+}
+
+func (l *clusterLock) keepAlive() {
+	defer emperror.HandleRecover(l.errorHandler)
+
+	interval := clusterLockTTL / 3
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+
+		case now := <-ticker.C:
+			expiresAt := now.Add(clusterLockTTL)
+
+			err := l.locks.Renew(l.model.ID, expiresAt)
+			if err == nil {
+				l.model.ExpiresAt = expiresAt
+
+				continue
+			}
+
+			l.errorHandler.Handle(err)
+
+			// failing renewals are retried as long as the lock is valid,
+			// once it expires another operation can acquire it
+			if now.Add(interval).Before(l.model.ExpiresAt) {
+				continue
+			}
+
+			l.err = emperror.Wrap(err, "cluster lock expired while the operation was running")
+			close(l.lost)
+
+			return
+		}
+	}
+}
+
+// Err returns an error if the lock has been lost, in which case the operation holding it must be failed.
+func (l *clusterLock) Err() error {
+	select {
+	case <-l.lost:
+		return l.err
+
+	default:
+		return nil
+	}
+}
+
+// setOperation records the operation holding the lock, so that conflicting requests can refer to it.
+func (l *clusterLock) setOperation(operationID uint) {
+	if operationID == 0 {
+		return
+	}
+
+	l.model.OperationID = operationID
+
+	if err := l.locks.SetOperation(l.model.ID, operationID); err != nil {
+		l.errorHandler.Handle(err)
+	}
+}
+
+// Release releases the lock. Failing to release the lock is not fatal, since it expires anyway.
+func (l *clusterLock) Release() {
+	close(l.done)
+
+	if err := l.locks.Release(l.model.ID); err != nil {
+		l.errorHandler.Handle(err)
+	}
+}
+
+// RunClusterOperation runs a synchronous operation on a cluster while holding the lock of the cluster.
+func (m *Manager) RunClusterOperation(
+	ctx context.Context,
+	cluster CommonCluster,
+	operationType string,
+	userID uint,
+	run func() error,
+) error {
+	lock, err := m.lockCluster(ctx, cluster.GetID(), operationType)
+	if err != nil {
+		return err
+	}
+
+	operation := m.startOperation(ctx, cluster, operationType, userID, lock)
+
+	err = run()
+	if err == nil {
+		err = lock.Err()
+	}
+
+	operation.Finish(err)
+
+	return err
+}
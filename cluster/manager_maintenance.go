@@ -0,0 +1,443 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type maintenanceRepository interface {
+	FindWindows(organizationID uint, clusterID uint) ([]*intCluster.MaintenanceWindowModel, error)
+	CreateWindow(window *intCluster.MaintenanceWindowModel) error
+	DeleteWindow(organizationID uint, clusterID uint, windowID uint) error
+	CreateQueuedOperation(operation *intCluster.QueuedOperationModel) error
+	SaveQueuedOperation(operation *intCluster.QueuedOperationModel) error
+	FindQueuedOperations(organizationID uint, clusterID uint) ([]*intCluster.QueuedOperationModel, error)
+	FindPendingQueuedOperations() ([]*intCluster.QueuedOperationModel, error)
+	StartQueuedOperation(operation *intCluster.QueuedOperationModel, startedAt time.Time) (bool, error)
+	CancelQueuedOperation(organizationID uint, clusterID uint, operationID uint) error
+}
+
+// GetMaintenanceWindows returns the maintenance windows of an organization (when clusterID is 0) or of a cluster.
+func (m *Manager) GetMaintenanceWindows(ctx context.Context, organizationID uint, clusterID uint) ([]*pkgCluster.MaintenanceWindowResponse, error) {
+	windows, err := m.maintenance.FindWindows(organizationID, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	response := make([]*pkgCluster.MaintenanceWindowResponse, 0, len(windows))
+	for _, window := range windows {
+		response = append(response, window.ConvertModelToEntity(now))
+	}
+
+	return response, nil
+}
+
+// CreateMaintenanceWindow adds a maintenance window to an organization (when clusterID is 0) or to a cluster.
+func (m *Manager) CreateMaintenanceWindow(
+	ctx context.Context,
+	organizationID uint,
+	clusterID uint,
+	request *pkgCluster.MaintenanceWindowRequest,
+	userID uint,
+) (*pkgCluster.MaintenanceWindowResponse, error) {
+	window := &intCluster.MaintenanceWindowModel{
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+		Schedule:       request.Schedule,
+		Duration:       request.Duration,
+		TimeZone:       request.TimeZone,
+		CreatedBy:      userID,
+	}
+
+	if window.TimeZone == "" {
+		window.TimeZone = "UTC"
+	}
+
+	if err := window.Window().Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := m.maintenance.CreateWindow(window); err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"cluster":      clusterID,
+		"window":       window.ID,
+	}).Info("maintenance window created")
+
+	return window.ConvertModelToEntity(time.Now()), nil
+}
+
+// DeleteMaintenanceWindow removes a maintenance window of an organization (when clusterID is 0) or of a cluster.
+func (m *Manager) DeleteMaintenanceWindow(ctx context.Context, organizationID uint, clusterID uint, windowID uint) error {
+	return m.maintenance.DeleteWindow(organizationID, clusterID, windowID)
+}
+
+// checkMaintenanceWindows tells whether disruptive operations can run on a cluster at the given time.
+// The windows of a cluster take precedence over the windows of its organization.
+func (m *Manager) checkMaintenanceWindows(cluster CommonCluster, now time.Time) (bool, *time.Time, error) {
+	windowModels, err := m.maintenance.FindWindows(cluster.GetOrganizationId(), cluster.GetID())
+	if err != nil {
+		return false, nil, err
+	}
+
+	if len(windowModels) == 0 {
+		windowModels, err = m.maintenance.FindWindows(cluster.GetOrganizationId(), 0)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	windows := make([]pkgCluster.MaintenanceWindow, 0, len(windowModels))
+	for _, window := range windowModels {
+		windows = append(windows, window.Window())
+	}
+
+	return pkgCluster.CheckMaintenanceWindows(windows, now)
+}
+
+// queueOutsideMaintenanceWindow queues an operation until the next maintenance window of a cluster opens.
+// It returns nil if the cluster is inside one of its maintenance windows (or has none) and the operation can run now.
+// Requests are validated before being queued, so that invalid requests are rejected right away.
+func (m *Manager) queueOutsideMaintenanceWindow(
+	ctx context.Context,
+	cluster CommonCluster,
+	operationType string,
+	request interface{},
+	userID uint,
+	validate func() error,
+) (*pkgCluster.QueuedOperationResponse, error) {
+	open, nextStart, err := m.checkMaintenanceWindows(cluster, time.Now())
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not check maintenance windows")
+	}
+
+	if open {
+		return nil, nil
+	}
+
+	if err := validate(); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not marshal queued operation request")
+	}
+
+	operation := &intCluster.QueuedOperationModel{
+		OrganizationID: cluster.GetOrganizationId(),
+		ClusterID:      cluster.GetID(),
+		Type:           operationType,
+		Request:        string(payload),
+		State:          pkgCluster.QueuedOperationQueued,
+		UserID:         userID,
+		ScheduledAt:    nextStart,
+	}
+
+	if err := m.maintenance.CreateQueuedOperation(operation); err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+		"operation":    operation.ID,
+		"type":         operationType,
+	}).Info("cluster operation queued until the next maintenance window")
+
+	return operation.ConvertModelToEntity(), nil
+}
+
+// QueueClusterUpdate queues a cluster update submitted outside the maintenance windows of the cluster.
+// It returns nil if the update can run now.
+func (m *Manager) QueueClusterUpdate(
+	ctx context.Context,
+	cluster CommonCluster,
+	request *pkgCluster.UpdateClusterRequest,
+	userID uint,
+) (*pkgCluster.QueuedOperationResponse, error) {
+	return m.queueOutsideMaintenanceWindow(ctx, cluster, pkgCluster.QueuedOperationUpdate, request, userID, func() error {
+		return m.validateQueuedUpdate(ctx, NewCommonClusterUpdater(request, cluster, userID))
+	})
+}
+
+// QueueNodePoolUpdate queues a node pool shrink or deletion (nil node pool) submitted outside the maintenance windows of the cluster.
+// It returns nil if the update can run now: adding and growing node pools is never queued.
+func (m *Manager) QueueNodePoolUpdate(
+	ctx context.Context,
+	cluster CommonCluster,
+	name string,
+	nodePool *pkgCluster.NodePoolRequest,
+	userID uint,
+) (*pkgCluster.QueuedOperationResponse, error) {
+	status, err := cluster.GetStatus()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster status")
+	}
+
+	if !IsNodePoolShrink(status.NodePools[name], nodePool) {
+		return nil, nil
+	}
+
+	request := pkgCluster.NodePoolQueuedOperation{
+		Name:     name,
+		NodePool: nodePool,
+	}
+
+	return m.queueOutsideMaintenanceWindow(ctx, cluster, pkgCluster.QueuedOperationNodePoolUpdate, request, userID, func() error {
+		updateRequest, err := NewNodePoolUpdateRequest(cluster, name, nodePool)
+		if err != nil {
+			return err
+		}
+
+		return m.validateQueuedUpdate(ctx, NewCommonClusterUpdater(updateRequest, cluster, userID))
+	})
+}
+
+// validateQueuedUpdate runs the checks of an update which would otherwise only run when the update starts.
+func (m *Manager) validateQueuedUpdate(ctx context.Context, updater *commonUpdater) error {
+	if err := updater.Validate(ctx); err != nil {
+		return err
+	}
+
+	if err := m.checkUpdateQuota(ctx, updater.cluster.GetOrganizationId(), updater); err != nil {
+		return err
+	}
+
+	return updater.prepareRequest()
+}
+
+// QueueClusterUpgrade queues a Kubernetes version upgrade submitted outside the maintenance windows of the cluster.
+// It returns nil if the upgrade can run now.
+func (m *Manager) QueueClusterUpgrade(
+	ctx context.Context,
+	cluster CommonCluster,
+	version string,
+	userID uint,
+) (*pkgCluster.QueuedOperationResponse, error) {
+	request := pkgCluster.UpgradeQueuedOperation{
+		Version: version,
+	}
+
+	return m.queueOutsideMaintenanceWindow(ctx, cluster, pkgCluster.QueuedOperationUpgrade, request, userID, func() error {
+		_, err := validateClusterUpgrade(cluster, version)
+
+		return err
+	})
+}
+
+// GetQueuedOperations returns the queued operations of a cluster.
+func (m *Manager) GetQueuedOperations(ctx context.Context, cluster CommonCluster) ([]*pkgCluster.QueuedOperationResponse, error) {
+	operations, err := m.maintenance.FindQueuedOperations(cluster.GetOrganizationId(), cluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*pkgCluster.QueuedOperationResponse, 0, len(operations))
+	for _, operation := range operations {
+		response = append(response, operation.ConvertModelToEntity())
+	}
+
+	return response, nil
+}
+
+// CancelQueuedOperation cancels an operation of a cluster still waiting for a maintenance window.
+func (m *Manager) CancelQueuedOperation(ctx context.Context, cluster CommonCluster, operationID uint) error {
+	return m.maintenance.CancelQueuedOperation(cluster.GetOrganizationId(), cluster.GetID(), operationID)
+}
+
+// StartMaintenanceScheduler periodically starts the queued operations of the clusters inside their maintenance windows.
+func (m *Manager) StartMaintenanceScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		for now := range ticker.C {
+			err := m.RunQueuedOperations(context.Background(), now)
+			if err != nil {
+				m.errorHandler.Handle(err)
+			}
+		}
+	}()
+}
+
+// RunQueuedOperations starts the queued operations of the clusters inside their maintenance windows, the oldest first.
+func (m *Manager) RunQueuedOperations(ctx context.Context, now time.Time) error {
+	logger := m.getLogger(ctx)
+
+	operations, err := m.maintenance.FindPendingQueuedOperations()
+	if err != nil {
+		return err
+	}
+
+	for _, operation := range operations {
+		logger := logger.WithFields(logrus.Fields{
+			"organization": operation.OrganizationID,
+			"cluster":      operation.ClusterID,
+			"operation":    operation.ID,
+			"type":         operation.Type,
+		})
+
+		errorHandler := emperror.HandlerWith(
+			m.getErrorHandler(ctx),
+			"organization", operation.OrganizationID,
+			"cluster", operation.ClusterID,
+			"operation", operation.ID,
+		)
+
+		clusterModel, err := m.clusters.FindOneByID(operation.OrganizationID, operation.ClusterID)
+		if e, ok := errors.Cause(err).(interface{ NotFound() bool }); ok && e.NotFound() {
+			m.finishQueuedOperation(operation, now, err, errorHandler)
+
+			continue
+		} else if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		cluster, err := m.getClusterFromModel(clusterModel)
+		if err != nil {
+			errorHandler.Handle(emperror.Wrap(err, "converting cluster model to common cluster failed"))
+
+			continue
+		}
+
+		open, _, err := m.checkMaintenanceWindows(cluster, now)
+		if err != nil {
+			errorHandler.Handle(emperror.Wrap(err, "could not check maintenance windows"))
+
+			continue
+		}
+
+		if !open {
+			continue
+		}
+
+		started, err := m.maintenance.StartQueuedOperation(operation, now)
+		if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		// the operation has been cancelled or started by another Pipeline instance in the meantime
+		if !started {
+			continue
+		}
+
+		logger.Info("starting queued cluster operation")
+
+		err = m.runQueuedOperation(ctx, cluster, operation)
+		if _, ok := errors.Cause(err).(*pkgCluster.OperationInProgressError); ok {
+			logger.Info("another operation is in progress on the cluster, queued operation postponed")
+
+			operation.State = pkgCluster.QueuedOperationQueued
+			operation.StartedAt = nil
+
+			if err := m.maintenance.SaveQueuedOperation(operation); err != nil {
+				errorHandler.Handle(err)
+			}
+
+			continue
+		}
+
+		m.finishQueuedOperation(operation, now, err, errorHandler)
+	}
+
+	return nil
+}
+
+// finishQueuedOperation records the result of starting a queued operation.
+// The started operation itself can be followed among the operations of the cluster.
+func (m *Manager) finishQueuedOperation(
+	operation *intCluster.QueuedOperationModel,
+	now time.Time,
+	err error,
+	errorHandler emperror.Handler,
+) {
+	if err == nil {
+		return
+	}
+
+	errorHandler.Handle(emperror.Wrap(err, "could not start queued cluster operation"))
+
+	operation.State = pkgCluster.QueuedOperationFailed
+	operation.Error = err.Error()
+	if operation.StartedAt == nil {
+		operation.StartedAt = &now
+	}
+
+	if err := m.maintenance.SaveQueuedOperation(operation); err != nil {
+		errorHandler.Handle(err)
+	}
+}
+
+func (m *Manager) runQueuedOperation(ctx context.Context, cluster CommonCluster, operation *intCluster.QueuedOperationModel) error {
+	updateCtx := UpdateContext{
+		OrganizationID: operation.OrganizationID,
+		UserID:         operation.UserID,
+		ClusterID:      operation.ClusterID,
+	}
+
+	switch operation.Type {
+	case pkgCluster.QueuedOperationUpdate:
+		var request pkgCluster.UpdateClusterRequest
+		if err := json.Unmarshal([]byte(operation.Request), &request); err != nil {
+			return emperror.Wrap(err, "could not unmarshal queued update request")
+		}
+
+		return m.UpdateCluster(ctx, updateCtx, NewCommonClusterUpdater(&request, cluster, operation.UserID))
+
+	case pkgCluster.QueuedOperationNodePoolUpdate:
+		var request pkgCluster.NodePoolQueuedOperation
+		if err := json.Unmarshal([]byte(operation.Request), &request); err != nil {
+			return emperror.Wrap(err, "could not unmarshal queued node pool request")
+		}
+
+		// the update request is built from the current state of the cluster, not from the state at the time of queueing
+		updateRequest, err := NewNodePoolUpdateRequest(cluster, request.Name, request.NodePool)
+		if err != nil {
+			return err
+		}
+
+		return m.UpdateCluster(ctx, updateCtx, NewCommonClusterUpdater(updateRequest, cluster, operation.UserID))
+
+	case pkgCluster.QueuedOperationUpgrade:
+		var request pkgCluster.UpgradeQueuedOperation
+		if err := json.Unmarshal([]byte(operation.Request), &request); err != nil {
+			return emperror.Wrap(err, "could not unmarshal queued upgrade request")
+		}
+
+		return m.UpgradeCluster(ctx, cluster, request.Version, operation.UserID)
+
+	default:
+		return errors.Errorf("unknown queued operation type: %s", operation.Type)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// nodePoolScheduleRetryPeriod is how long a scheduled scaling is retried while other operations are running on the cluster.
+const nodePoolScheduleRetryPeriod = 30 * time.Minute
+
+type nodePoolScheduleRepository interface {
+	All() ([]*intCluster.NodePoolScheduleModel, error)
+	FindByNodePool(clusterID uint, nodePool string) ([]*intCluster.NodePoolScheduleModel, error)
+	Create(schedule *intCluster.NodePoolScheduleModel) error
+	Delete(clusterID uint, nodePool string, scheduleID uint) error
+	DeleteByClusterID(clusterID uint) error
+	Claim(schedule *intCluster.NodePoolScheduleModel, runAt time.Time) (bool, error)
+	SetResult(scheduleID uint, lastRunAt *time.Time, lastError string) error
+}
+
+// GetNodePoolSchedules returns the scaling schedules of a node pool.
+func (m *Manager) GetNodePoolSchedules(ctx context.Context, cluster CommonCluster, nodePool string) ([]*pkgCluster.NodePoolScheduleResponse, error) {
+	schedules, err := m.schedules.FindByNodePool(cluster.GetID(), nodePool)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	response := make([]*pkgCluster.NodePoolScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		response = append(response, schedule.ConvertModelToEntity(now))
+	}
+
+	return response, nil
+}
+
+// CreateNodePoolSchedule adds a scaling schedule to a node pool.
+func (m *Manager) CreateNodePoolSchedule(
+	ctx context.Context,
+	cluster CommonCluster,
+	nodePool string,
+	request *pkgCluster.NodePoolScheduleRequest,
+	userID uint,
+) (*pkgCluster.NodePoolScheduleResponse, error) {
+	schedule := &intCluster.NodePoolScheduleModel{
+		OrganizationID: cluster.GetOrganizationId(),
+		ClusterID:      cluster.GetID(),
+		NodePool:       nodePool,
+		Schedule:       request.Schedule,
+		TimeZone:       request.TimeZone,
+		Count:          request.Count,
+		CreatedBy:      userID,
+	}
+
+	if schedule.TimeZone == "" {
+		schedule.TimeZone = "UTC"
+	}
+
+	if err := schedule.NodePoolSchedule().Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := m.schedules.Create(schedule); err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+		"nodePool":     nodePool,
+		"schedule":     schedule.ID,
+	}).Info("node pool schedule created")
+
+	return schedule.ConvertModelToEntity(time.Now()), nil
+}
+
+// DeleteNodePoolSchedule removes a scaling schedule of a node pool.
+func (m *Manager) DeleteNodePoolSchedule(ctx context.Context, cluster CommonCluster, nodePool string, scheduleID uint) error {
+	return m.schedules.Delete(cluster.GetID(), nodePool, scheduleID)
+}
+
+// StartNodePoolScaler periodically scales the node pools with due scaling schedules.
+func (m *Manager) StartNodePoolScaler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		for now := range ticker.C {
+			err := m.RunNodePoolSchedules(context.Background(), now)
+			if err != nil {
+				m.errorHandler.Handle(err)
+			}
+		}
+	}()
+}
+
+// RunNodePoolSchedules scales the node pools with scaling schedules due since their last run.
+func (m *Manager) RunNodePoolSchedules(ctx context.Context, now time.Time) error {
+	logger := m.getLogger(ctx)
+
+	schedules, err := m.schedules.All()
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		logger := logger.WithFields(logrus.Fields{
+			"organization": schedule.OrganizationID,
+			"cluster":      schedule.ClusterID,
+			"nodePool":     schedule.NodePool,
+			"schedule":     schedule.ID,
+		})
+
+		errorHandler := emperror.HandlerWith(
+			m.getErrorHandler(ctx),
+			"organization", schedule.OrganizationID,
+			"cluster", schedule.ClusterID,
+			"nodePool", schedule.NodePool,
+			"schedule", schedule.ID,
+		)
+
+		since := schedule.CreatedAt
+		if schedule.LastRunAt != nil {
+			since = *schedule.LastRunAt
+		}
+
+		dueAt, err := schedule.NodePoolSchedule().DueRun(since, now)
+		if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		if dueAt.IsZero() {
+			continue
+		}
+
+		clusterModel, err := m.clusters.FindOneByID(schedule.OrganizationID, schedule.ClusterID)
+		if e, ok := errors.Cause(err).(interface{ NotFound() bool }); ok && e.NotFound() {
+			logger.Info("cluster not found, deleting its node pool schedules")
+
+			if err := m.schedules.DeleteByClusterID(schedule.ClusterID); err != nil {
+				errorHandler.Handle(err)
+			}
+
+			continue
+		} else if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		cluster, err := m.getClusterFromModel(clusterModel)
+		if err != nil {
+			errorHandler.Handle(emperror.Wrap(err, "converting cluster model to common cluster failed"))
+
+			continue
+		}
+
+		lastRunAt := schedule.LastRunAt
+
+		claimed, err := m.schedules.Claim(schedule, dueAt)
+		if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		// the run has been recorded by another Pipeline instance in the meantime
+		if !claimed {
+			continue
+		}
+
+		logger.Info("scaling node pool according to schedule")
+
+		var lastError string
+
+		err = m.scaleNodePool(ctx, cluster, schedule.NodePool, schedule.Count, schedule.CreatedBy)
+		if _, ok := errors.Cause(err).(*pkgCluster.OperationInProgressError); ok && now.Sub(dueAt) < nodePoolScheduleRetryPeriod {
+			logger.Info("another operation is in progress on the cluster, scheduled scaling postponed")
+
+			// the run is retried on the next tick
+			if err := m.schedules.SetResult(schedule.ID, lastRunAt, err.Error()); err != nil {
+				errorHandler.Handle(err)
+			}
+
+			continue
+		} else if err != nil {
+			errorHandler.Handle(emperror.Wrap(err, "scheduled node pool scaling failed"))
+
+			lastError = err.Error()
+		}
+
+		if err := m.schedules.SetResult(schedule.ID, &dueAt, lastError); err != nil {
+			errorHandler.Handle(err)
+		}
+	}
+
+	return nil
+}
+
+// scaleNodePool resizes a node pool through the common update path of the cluster.
+// The minimum and maximum size of node pools with autoscaling enabled are kept.
+func (m *Manager) scaleNodePool(ctx context.Context, cluster CommonCluster, nodePool string, count int, userID uint) error {
+	status, err := cluster.GetStatus()
+	if err != nil {
+		return emperror.Wrap(err, "could not get cluster status")
+	}
+
+	current, ok := status.NodePools[nodePool]
+	if !ok || current == nil {
+		return fmt.Errorf("node pool [%s] not found", nodePool)
+	}
+
+	count = pkgCluster.ScheduledNodeCount(current, count)
+	if count == current.Count {
+		return nil
+	}
+
+	request := &pkgCluster.NodePoolRequest{
+		Autoscaling: current.Autoscaling,
+		MinCount:    current.MinCount,
+		MaxCount:    current.MaxCount,
+		Count:       count,
+	}
+
+	updateRequest, err := NewNodePoolUpdateRequest(cluster, nodePool, request)
+	if err != nil {
+		return err
+	}
+
+	updateCtx := UpdateContext{
+		OrganizationID: cluster.GetOrganizationId(),
+		UserID:         userID,
+		ClusterID:      cluster.GetID(),
+	}
+
+	err = m.UpdateCluster(ctx, updateCtx, NewCommonClusterUpdater(updateRequest, cluster, userID))
+	if e, ok := errors.Cause(err).(interface{ Unchanged() bool }); ok && e.Unchanged() {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,163 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+type operationRepository interface {
+	Create(operation *intCluster.OperationModel) error
+	Save(operation *intCluster.OperationModel) error
+	AddStep(operationID uint, name string, message string) error
+	FindByClusterID(organizationID uint, clusterID uint) ([]*intCluster.OperationModel, error)
+	FindOneByID(organizationID uint, clusterID uint, operationID uint) (*intCluster.OperationModel, error)
+}
+
+// clusterOperation records the progress of a single cluster operation.
+// Failing to record the progress never fails the operation itself, errors are passed to the error handler instead.
+type clusterOperation struct {
+	model        *intCluster.OperationModel
+	lock         *clusterLock
+	operations   operationRepository
+	errorHandler emperror.Handler
+}
+
+// startOperation persists a new running operation for a cluster.
+// The operation holds the lock of the cluster until it is finished.
+func (m *Manager) startOperation(
+	ctx context.Context,
+	cluster CommonCluster,
+	operationType string,
+	userID uint,
+	lock *clusterLock,
+) *clusterOperation {
+	operation := &clusterOperation{
+		model: &intCluster.OperationModel{
+			ClusterID:      cluster.GetID(),
+			OrganizationID: cluster.GetOrganizationId(),
+			Type:           operationType,
+			State:          pkgCluster.OperationRunning,
+			Actor:          userID,
+			StartedAt:      time.Now(),
+		},
+		lock:       lock,
+		operations: m.operations,
+		errorHandler: emperror.HandlerWith(
+			m.getErrorHandler(ctx),
+			"organization", cluster.GetOrganizationId(),
+			"cluster", cluster.GetID(),
+			"operation", operationType,
+		),
+	}
+
+	if err := m.operations.Create(operation.model); err != nil {
+		operation.errorHandler.Handle(err)
+	}
+
+	lock.setOperation(operation.model.ID)
+
+	return operation
+}
+
+// ID returns the ID of the operation.
+func (o *clusterOperation) ID() uint {
+	return o.model.ID
+}
+
+// Step appends a step to the operation log.
+func (o *clusterOperation) Step(name string, message string) {
+	if o.model.ID == 0 {
+		return
+	}
+
+	if err := o.operations.AddStep(o.model.ID, name, message); err != nil {
+		o.errorHandler.Handle(err)
+	}
+}
+
+// Finish marks the operation as succeeded or failed depending on the error and releases the lock of the cluster.
+// Operations which lost the lock of the cluster while running are failed.
+func (o *clusterOperation) Finish(err error) {
+	defer o.lock.Release()
+
+	if err == nil {
+		err = o.lock.Err()
+	}
+
+	if o.model.ID == 0 {
+		return
+	}
+
+	now := time.Now()
+	o.model.FinishedAt = &now
+	o.model.State = pkgCluster.OperationSucceeded
+
+	if err != nil {
+		o.model.State = pkgCluster.OperationFailed
+		o.model.Error = err.Error()
+	}
+
+	if err := o.operations.Save(o.model); err != nil {
+		o.errorHandler.Handle(err)
+	}
+}
+
+// GetOperations returns the operations of a cluster, the latest first.
+// Operations of deleted clusters are returned as well.
+func (m *Manager) GetOperations(ctx context.Context, organizationID uint, clusterID uint) ([]*pkgCluster.OperationResponse, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"cluster":      clusterID,
+	})
+
+	logger.Debug("fetching cluster operations from database")
+
+	operations, err := m.operations.FindByClusterID(organizationID, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*pkgCluster.OperationResponse, 0, len(operations))
+	for _, operation := range operations {
+		response = append(response, operation.ConvertModelToEntity())
+	}
+
+	return response, nil
+}
+
+// GetOperation returns a single operation of a cluster.
+func (m *Manager) GetOperation(ctx context.Context, organizationID uint, clusterID uint, operationID uint) (*pkgCluster.OperationResponse, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"cluster":      clusterID,
+		"operation":    operationID,
+	})
+
+	logger.Debug("fetching cluster operation from database")
+
+	operation, err := m.operations.FindOneByID(organizationID, clusterID, operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return operation.ConvertModelToEntity(), nil
+}
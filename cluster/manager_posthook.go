@@ -0,0 +1,468 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type postHookRepository interface {
+	FindByClusterID(clusterID uint) ([]*intCluster.PostHookModel, error)
+	Save(postHook *intCluster.PostHookModel) error
+	DeleteByClusterID(clusterID uint) error
+}
+
+// GetPostHooks returns the recorded posthook states of a cluster in the order of execution.
+func (m *Manager) GetPostHooks(ctx context.Context, cluster CommonCluster) ([]*pkgCluster.PostHookStatus, error) {
+	postHooks, err := m.postHooks.FindByClusterID(cluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*pkgCluster.PostHookStatus, 0, len(postHooks))
+	for _, postHook := range postHooks {
+		response = append(response, postHook.ConvertModelToEntity())
+	}
+
+	return response, nil
+}
+
+// ReRunPostHooks runs posthooks on an existing cluster in the background.
+// If no posthooks are given, the base posthooks are run from the start and the previously recorded states are dropped,
+// otherwise only the given posthooks are run (respecting their dependencies on each other).
+func (m *Manager) ReRunPostHooks(ctx context.Context, cluster CommonCluster, functions []PostFunctioner, userID uint) error {
+	lock, err := m.lockCluster(ctx, cluster.GetID(), pkgCluster.OperationPostHook)
+	if err != nil {
+		return err
+	}
+
+	var postHooks []*intCluster.PostHookModel
+
+	if len(functions) == 0 {
+		functions = BasePostHookFunctions
+		postHooks, err = m.resetPostHooks(cluster, functions)
+	} else {
+		postHooks, functions, err = m.selectPostHooks(cluster, functions)
+	}
+	if err != nil {
+		lock.Release()
+
+		return err
+	}
+
+	m.runPostHooksInBackground(ctx, cluster, postHooks, functions, userID, lock)
+
+	return nil
+}
+
+// ResumePostHooks runs the posthooks of a cluster which have not succeeded yet in the background,
+// dependencies on posthooks which already succeeded are considered to be satisfied.
+func (m *Manager) ResumePostHooks(ctx context.Context, cluster CommonCluster, userID uint) error {
+	lock, err := m.lockCluster(ctx, cluster.GetID(), pkgCluster.OperationPostHook)
+	if err != nil {
+		return err
+	}
+
+	postHooks, functions, err := m.getUnfinishedPostHooks(cluster)
+	if err != nil {
+		lock.Release()
+
+		return err
+	}
+
+	m.runPostHooksInBackground(ctx, cluster, postHooks, functions, userID, lock)
+
+	return nil
+}
+
+// getUnfinishedPostHooks returns the posthooks of a cluster which have not succeeded yet.
+func (m *Manager) getUnfinishedPostHooks(cluster CommonCluster) ([]*intCluster.PostHookModel, []PostFunctioner, error) {
+	recorded, err := m.postHooks.FindByClusterID(cluster.GetID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var postHooks []*intCluster.PostHookModel
+	var functions []PostFunctioner
+
+	for _, postHook := range recorded {
+		if postHook.State == pkgCluster.PostHookSucceeded {
+			continue
+		}
+
+		function, err := m.postHookFunctionFromModel(cluster, postHook)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		postHooks = append(postHooks, postHook)
+		functions = append(functions, function)
+	}
+
+	if len(postHooks) == 0 {
+		return nil, nil, pkgCluster.NewValidationError("there are no posthooks to resume")
+	}
+
+	return postHooks, functions, nil
+}
+
+func (m *Manager) runPostHooksInBackground(
+	ctx context.Context,
+	cluster CommonCluster,
+	postHooks []*intCluster.PostHookModel,
+	functions []PostFunctioner,
+	userID uint,
+	lock *clusterLock,
+) {
+	errorHandler := emperror.HandlerWith(
+		m.getErrorHandler(ctx),
+		"organization", cluster.GetOrganizationId(),
+		"cluster", cluster.GetID(),
+	)
+
+	operation := m.startOperation(ctx, cluster, pkgCluster.OperationPostHook, userID, lock)
+
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		operation.Step("RunPostHooks", "running cluster posthooks")
+		err := m.runPostHooks(ctx, cluster, pkgCluster.Updating, postHooks, functions)
+		operation.Finish(err)
+		if err != nil {
+			errorHandler.Handle(err)
+		}
+	}()
+}
+
+// resetPostHooks drops the recorded posthook states of a cluster and records the given posthooks as pending.
+func (m *Manager) resetPostHooks(cluster CommonCluster, functions []PostFunctioner) ([]*intCluster.PostHookModel, error) {
+	if err := m.postHooks.DeleteByClusterID(cluster.GetID()); err != nil {
+		return nil, err
+	}
+
+	postHooks := make([]*intCluster.PostHookModel, 0, len(functions))
+
+	for i, function := range functions {
+		postHook := &intCluster.PostHookModel{
+			ClusterID: cluster.GetID(),
+			Name:      function.GetName(),
+			Position:  i,
+		}
+
+		if err := setPostHookPending(postHook, function); err != nil {
+			return nil, err
+		}
+
+		if err := m.postHooks.Save(postHook); err != nil {
+			return nil, err
+		}
+
+		postHooks = append(postHooks, postHook)
+	}
+
+	return postHooks, nil
+}
+
+// selectPostHooks marks the given posthooks as pending and orders them by their original position.
+// Posthooks which have never run on the cluster are appended to the end.
+func (m *Manager) selectPostHooks(cluster CommonCluster, functions []PostFunctioner) ([]*intCluster.PostHookModel, []PostFunctioner, error) {
+	recorded, err := m.postHooks.FindByClusterID(cluster.GetID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordedByName := make(map[string]*intCluster.PostHookModel, len(recorded))
+	nextPosition := 0
+	for _, postHook := range recorded {
+		recordedByName[postHook.Name] = postHook
+
+		if postHook.Position >= nextPosition {
+			nextPosition = postHook.Position + 1
+		}
+	}
+
+	postHooks := make([]*intCluster.PostHookModel, 0, len(functions))
+
+	for _, function := range functions {
+		postHook, ok := recordedByName[function.GetName()]
+		if !ok {
+			postHook = &intCluster.PostHookModel{
+				ClusterID: cluster.GetID(),
+				Name:      function.GetName(),
+				Position:  nextPosition,
+			}
+			nextPosition++
+		}
+
+		if err := setPostHookPending(postHook, function); err != nil {
+			return nil, nil, err
+		}
+
+		if err := m.postHooks.Save(postHook); err != nil {
+			return nil, nil, err
+		}
+
+		postHooks = append(postHooks, postHook)
+	}
+
+	sort.Stable(postHooksByPosition{postHooks: postHooks, functions: functions})
+
+	return postHooks, functions, nil
+}
+
+// runPostHooks runs posthook functions on a cluster and records their states.
+// The progress is reported in the given cluster status: CREATING for new clusters, UPDATING for existing ones.
+// Posthooks are started as soon as their dependencies succeeded, independent ones run concurrently on a bounded number of workers.
+// No more posthooks are started after the first failing one.
+func (m *Manager) runPostHooks(ctx context.Context, cluster CommonCluster, status string, postHooks []*intCluster.PostHookModel, functions []PostFunctioner) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetName(),
+	})
+
+	errorHandler := emperror.HandlerWith(
+		m.getErrorHandler(ctx),
+		"organization", cluster.GetOrganizationId(),
+		"cluster", cluster.GetID(),
+	)
+
+	names := make([]string, len(functions))
+	dependencies := make([][]string, len(functions))
+	for i, function := range functions {
+		names[i] = function.GetName()
+		dependencies[i] = function.GetDependencies()
+	}
+
+	graph, err := newPostHookGraph(names, dependencies)
+	if err != nil {
+		return err
+	}
+
+	// cluster implementations load these lazily and cache them without synchronization,
+	// so load them once before the posthooks access the cluster concurrently
+	if _, err := cluster.GetK8sConfig(); err != nil {
+		return emperror.Wrap(err, "failed to get kubernetes config")
+	}
+
+	if _, err := cluster.GetAPIEndpoint(); err != nil {
+		return emperror.Wrap(err, "failed to get api endpoint")
+	}
+
+	workers := viper.GetInt(pipConfig.ClusterPostHookWorkers)
+	timeout := viper.GetDuration(pipConfig.ClusterPostHookTimeout)
+
+	// the status updates save the cluster model as a whole,
+	// so the flags of the installed features are set only after every posthook function returned
+	var installed []clusterFeature
+	defer func() {
+		for _, feature := range installed {
+			if err := m.setClusterFeatureFlag(cluster, feature, true); err != nil {
+				errorHandler.Handle(err)
+			}
+		}
+	}()
+
+	// timed out posthook functions keep running, the cluster must stay locked until they return
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	do := func(i int) error {
+		postHook := postHooks[i]
+		function := functions[i]
+
+		logger.WithField("posthook", postHook.Name).Infof("start posthook function[%s]", function)
+
+		startedAt := time.Now()
+		postHook.State = pkgCluster.PostHookRunning
+		postHook.StartedAt = &startedAt
+		postHook.FinishedAt = nil
+		postHook.Error = ""
+		if err := m.postHooks.Save(postHook); err != nil {
+			errorHandler.Handle(err)
+		}
+
+		return runPostHookFunction(cluster, function, timeout, &running)
+	}
+
+	var statusErr error
+	failed := -1
+
+	// called from this goroutine only, so that the cluster status is never updated concurrently
+	finished := func(i int, err error) {
+		postHook := postHooks[i]
+		function := functions[i]
+
+		finishedAt := time.Now()
+		postHook.FinishedAt = &finishedAt
+		postHook.State = pkgCluster.PostHookSucceeded
+
+		if err != nil {
+			postHook.State = pkgCluster.PostHookFailed
+			postHook.Error = err.Error()
+		}
+
+		if err := m.postHooks.Save(postHook); err != nil {
+			errorHandler.Handle(err)
+		}
+
+		if err != nil {
+			function.Error(cluster, err)
+
+			if failed < 0 {
+				failed = i
+			}
+
+			return
+		}
+
+		if feature, ok := getPostHookFeature(function.GetName()); ok {
+			installed = append(installed, feature)
+		}
+
+		statusMsg := fmt.Sprintf("Posthook function finished: %s", function)
+		if err := cluster.UpdateStatus(status, statusMsg); err != nil && statusErr == nil {
+			statusErr = emperror.Wrap(err, "posthook status update failed")
+		}
+	}
+
+	if err := graph.run(workers, do, finished); err != nil {
+		return emperror.With(
+			emperror.Wrapf(err, "posthook function[%s] failed", functions[failed]),
+			"posthook", postHooks[failed].Name,
+		)
+	}
+
+	if statusErr != nil {
+		return statusErr
+	}
+
+	logger.Info("all posthooks finished successfully")
+
+	if err := cluster.UpdateStatus(pkgCluster.Running, pkgCluster.RunningMessage); err != nil {
+		return emperror.Wrap(err, "cluster status update failed")
+	}
+
+	return nil
+}
+
+// runPostHookFunction runs a posthook function and gives up waiting for it after the given timeout (unless it is zero).
+// Posthook functions cannot be cancelled, a timed out function keeps running in the background
+// and marks the given wait group done when it returns.
+func runPostHookFunction(cluster CommonCluster, function PostFunctioner, timeout time.Duration, running *sync.WaitGroup) error {
+	if timeout <= 0 {
+		return function.Do(cluster)
+	}
+
+	done := make(chan error, 1)
+
+	running.Add(1)
+	go func() {
+		var err error
+
+		defer running.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				err = emperror.Recover(r)
+			}
+
+			done <- err
+		}()
+
+		err = function.Do(cluster)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+
+	case <-time.After(timeout):
+		return errors.Errorf("posthook function[%s] timed out after %s", function, timeout)
+	}
+}
+
+// setPostHookPending resets the recorded state of a posthook and stores the params of the function.
+func setPostHookPending(postHook *intCluster.PostHookModel, function PostFunctioner) error {
+	postHook.State = pkgCluster.PostHookPending
+	postHook.Error = ""
+	postHook.StartedAt = nil
+	postHook.FinishedAt = nil
+	postHook.Params = ""
+
+	if f, ok := function.(interface {
+		GetParams() pkgCluster.PostHookParam
+	}); ok && f.GetParams() != nil {
+		params, err := json.Marshal(f.GetParams())
+		if err != nil {
+			return emperror.With(errors.Wrap(err, "could not marshal posthook params"), "posthook", postHook.Name)
+		}
+
+		postHook.Params = string(params)
+	}
+
+	return nil
+}
+
+// postHookFunctionFromModel restores a posthook function with its params from its recorded state.
+// User-defined posthooks are restored with their current definition.
+func (m *Manager) postHookFunctionFromModel(cluster CommonCluster, postHook *intCluster.PostHookModel) (PostFunctioner, error) {
+	var params pkgCluster.PostHookParam
+
+	if postHook.Params != "" {
+		if err := json.Unmarshal([]byte(postHook.Params), &params); err != nil {
+			return nil, emperror.With(errors.Wrap(err, "could not unmarshal posthook params"), "posthook", postHook.Name)
+		}
+	}
+
+	function, err := m.getPostHookFunction(cluster.GetOrganizationId(), postHook.Name, params)
+	if isNotFoundError(err) {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("there's no posthook function with name [%s]", postHook.Name),
+		)
+	}
+
+	return function, err
+}
+
+// postHooksByPosition sorts posthook states and their functions together.
+type postHooksByPosition struct {
+	postHooks []*intCluster.PostHookModel
+	functions []PostFunctioner
+}
+
+func (p postHooksByPosition) Len() int {
+	return len(p.postHooks)
+}
+
+func (p postHooksByPosition) Less(i, j int) bool {
+	return p.postHooks[i].Position < p.postHooks[j].Position
+}
+
+func (p postHooksByPosition) Swap(i, j int) {
+	p.postHooks[i], p.postHooks[j] = p.postHooks[j], p.postHooks[i]
+	p.functions[i], p.functions[j] = p.functions[j], p.functions[i]
+}
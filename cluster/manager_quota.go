@@ -0,0 +1,302 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+type quotaRepository interface {
+	FindByOrganization(organizationID uint) (*intCluster.OrganizationQuotaModel, error)
+	Lock(organizationID uint) (func(), error)
+	Save(quota *intCluster.OrganizationQuotaModel) error
+	Delete(organizationID uint) error
+}
+
+// creationResourcer is implemented by cluster creators which can tell the resources of the cluster to be created.
+type creationResourcer interface {
+	// Resources returns the resources of the cluster to be created.
+	Resources() (*pkgCluster.ClusterResources, error)
+}
+
+// updateResourcer is implemented by cluster updaters which can tell the resources of a cluster before and after the update.
+type updateResourcer interface {
+	// Resources returns the current resources of the cluster and the resources after the update.
+	Resources() (*pkgCluster.ClusterResources, *pkgCluster.ClusterResources, error)
+}
+
+// GetQuota returns the quota of an organization together with the resources used by its clusters.
+func (m *Manager) GetQuota(ctx context.Context, organizationID uint) (*pkgCluster.QuotaResponse, error) {
+	quota, model, err := m.getQuota(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := m.getQuotaUsage(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pkgCluster.QuotaResponse{
+		Usage: usage,
+	}
+
+	if quota != nil {
+		response.Quota = *quota
+		response.UpdatedAt = &model.UpdatedAt
+		response.UpdatedBy = model.UpdatedBy
+	}
+
+	return response, nil
+}
+
+// SetQuota replaces the quota of an organization.
+// Existing clusters are not affected, the quota is enforced when clusters are created or updated.
+func (m *Manager) SetQuota(ctx context.Context, organizationID uint, quota *pkgCluster.Quota, userID uint) (*pkgCluster.QuotaResponse, error) {
+	if err := quota.Validate(); err != nil {
+		return nil, err
+	}
+
+	limits, err := json.Marshal(quota)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not marshal organization quota")
+	}
+
+	model := &intCluster.OrganizationQuotaModel{
+		OrganizationID: organizationID,
+		Limits:         string(limits),
+		UpdatedBy:      userID,
+	}
+
+	if err := m.quotas.Save(model); err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"user":         userID,
+	}).Info("organization quota updated")
+
+	return m.GetQuota(ctx, organizationID)
+}
+
+// DeleteQuota removes the quota of an organization.
+func (m *Manager) DeleteQuota(ctx context.Context, organizationID uint) error {
+	return m.quotas.Delete(organizationID)
+}
+
+// getQuota returns the quota of an organization or nil if the organization has no quota.
+func (m *Manager) getQuota(organizationID uint) (*pkgCluster.Quota, *intCluster.OrganizationQuotaModel, error) {
+	model, err := m.quotas.FindByOrganization(organizationID)
+	if err != nil || model == nil {
+		return nil, nil, err
+	}
+
+	var quota pkgCluster.Quota
+	if err := json.Unmarshal([]byte(model.Limits), &quota); err != nil {
+		return nil, nil, emperror.With(
+			errors.Wrap(err, "could not unmarshal organization quota"),
+			"organization", organizationID,
+		)
+	}
+
+	return &quota, model, nil
+}
+
+// getQuotaUsage sums the resources of the clusters of an organization.
+func (m *Manager) getQuotaUsage(ctx context.Context, organizationID uint) (pkgCluster.QuotaUsage, error) {
+	logger := m.getLogger(ctx).WithField("organization", organizationID)
+
+	clusters, err := m.GetClusters(ctx, organizationID)
+	if err != nil {
+		return pkgCluster.QuotaUsage{}, err
+	}
+
+	resources := make([]pkgCluster.ClusterResources, 0, len(clusters))
+
+	for _, cluster := range clusters {
+		clusterResources, err := getClusterResources(cluster)
+		if err != nil {
+			// the cluster is still counted, even if its node pools are unknown
+			logger.WithField("cluster", cluster.GetID()).Warnf("could not get cluster resources: %s", err.Error())
+
+			clusterResources = &pkgCluster.ClusterResources{Cloud: cluster.GetCloud(), Location: cluster.GetLocation()}
+		}
+
+		m.setInstanceTypeCPUs(clusterResources)
+
+		resources = append(resources, *clusterResources)
+	}
+
+	return pkgCluster.NewQuotaUsage(resources), nil
+}
+
+// checkCreationQuota checks that a cluster to be created does not exceed the quota of its organization.
+// The quota stays locked until the returned function is called, it should be called once the cluster is stored,
+// so that concurrent creations are checked against the usage including the new cluster.
+func (m *Manager) checkCreationQuota(ctx context.Context, organizationID uint, creator clusterCreator) (func(), error) {
+	resourcer, ok := creator.(creationResourcer)
+	if !ok {
+		return func() {}, nil
+	}
+
+	return m.checkQuota(ctx, organizationID, func() (*pkgCluster.ClusterResources, *pkgCluster.ClusterResources, error) {
+		requested, err := resourcer.Resources()
+
+		return nil, requested, err
+	})
+}
+
+// checkUpdateQuota checks that a cluster update does not exceed the quota of the organization of the cluster.
+// The usage is calculated from the stored node pools, which are only updated once the cluster update succeeds,
+// so the quota is soft for concurrent updates: they are checked against the usage before any of them is applied.
+func (m *Manager) checkUpdateQuota(ctx context.Context, organizationID uint, updater interface{}) error {
+	resourcer, ok := updater.(updateResourcer)
+	if !ok {
+		return nil
+	}
+
+	release, err := m.checkQuota(ctx, organizationID, resourcer.Resources)
+	if err != nil {
+		return err
+	}
+
+	release()
+
+	return nil
+}
+
+// checkQuota locks the quota of an organization and checks the requested resources against it.
+// The lock is released on error, otherwise by calling the returned function.
+func (m *Manager) checkQuota(
+	ctx context.Context,
+	organizationID uint,
+	resources func() (*pkgCluster.ClusterResources, *pkgCluster.ClusterResources, error),
+) (func(), error) {
+	release, err := m.quotas.Lock(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkLockedQuota(ctx, organizationID, resources); err != nil {
+		release()
+
+		return nil, err
+	}
+
+	return release, nil
+}
+
+func (m *Manager) checkLockedQuota(
+	ctx context.Context,
+	organizationID uint,
+	resources func() (*pkgCluster.ClusterResources, *pkgCluster.ClusterResources, error),
+) error {
+	quota, _, err := m.getQuota(organizationID)
+	if err != nil || quota == nil {
+		return err
+	}
+
+	current, requested, err := resources()
+	if err != nil {
+		return err
+	}
+
+	if current != nil {
+		m.setInstanceTypeCPUs(current)
+	}
+	m.setInstanceTypeCPUs(requested)
+
+	usage, err := m.getQuotaUsage(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	if violations := quota.Check(usage, current, requested); len(violations) != 0 {
+		return &pkgCluster.QuotaExceededError{Violations: violations}
+	}
+
+	return nil
+}
+
+// setInstanceTypeCPUs looks up the vCPU counts of the instance types of the node pools in the price source.
+// Instance types with an unknown vCPU count are not counted against the quota unless they are configured to be denied.
+func (m *Manager) setInstanceTypeCPUs(resources *pkgCluster.ClusterResources) {
+	denyUnknown := viper.GetBool(pipConfig.ClusterQuotaDenyUnknownInstanceTypes)
+
+	resources.InstanceTypeCPUs = make(map[string]int, len(resources.NodePools))
+
+	for _, nodePool := range resources.NodePools {
+		cpus, ok := m.prices.GetCPUs(resources.Cloud, resources.Location, nodePool.InstanceType)
+		if !ok && denyUnknown {
+			continue
+		}
+
+		resources.InstanceTypeCPUs[nodePool.InstanceType] = cpus
+	}
+}
+
+// getClusterResources returns the resources of a cluster from its stored spec.
+func getClusterResources(cluster CommonCluster) (*pkgCluster.ClusterResources, error) {
+	spec, err := GetClusterSpec(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSpecResources(spec)
+}
+
+// getSpecResources returns the resources of a cluster spec (or create request).
+// Node pools of clusters which are not managed by Pipeline (eg. dummy or imported clusters) are not counted.
+func getSpecResources(spec *pkgCluster.CreateClusterRequest) (*pkgCluster.ClusterResources, error) {
+	resources := &pkgCluster.ClusterResources{
+		Cloud:    spec.Cloud,
+		Location: spec.Location,
+	}
+
+	if spec.Properties == nil {
+		return resources, nil
+	}
+
+	location, nodePools, err := getSpecNodePools(spec)
+	if _, ok := errors.Cause(err).(*pkgCluster.ValidationError); ok {
+		return resources, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	resources.Location = location
+	resources.NodePools = getNodePoolPlans(nodePools)
+
+	return resources, nil
+}
+
+func getNodePoolPlans(nodePools []pkgCluster.EstimatedNodePool) []pkgCluster.NodePoolPlan {
+	plans := make([]pkgCluster.NodePoolPlan, 0, len(nodePools))
+	for _, nodePool := range nodePools {
+		plans = append(plans, nodePool.NodePoolPlan)
+	}
+
+	return plans
+}
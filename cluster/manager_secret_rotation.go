@@ -0,0 +1,495 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// scheduledSecretRotationUpdatedBy is recorded as the author of the secret versions created by rotation policies
+const scheduledSecretRotationUpdatedBy = "pipeline"
+
+// SecretRotationSkipped marks Kubernetes secrets which no longer exist when a rotated secret is propagated
+const SecretRotationSkipped = "SKIPPED"
+
+type secretRotationRepository interface {
+	SaveInstallation(installation *intCluster.SecretInstallationModel) error
+	FindInstallationsBySecret(organizationID uint, secretID string) ([]*intCluster.SecretInstallationModel, error)
+	DeleteInstallationsByClusterID(clusterID uint) error
+	FindPolicy(organizationID uint, secretID string) (*intCluster.SecretRotationPolicyModel, error)
+	FindDuePolicies(now time.Time) ([]*intCluster.SecretRotationPolicyModel, error)
+	SavePolicy(policy *intCluster.SecretRotationPolicyModel) error
+	DeletePolicy(organizationID uint, secretID string) error
+	ClaimPolicy(policy *intCluster.SecretRotationPolicyModel, now time.Time) (bool, error)
+	CreateRotation(rotation *intCluster.SecretRotationModel) error
+	SaveRotation(rotation *intCluster.SecretRotationModel) error
+	FindRotationsBySecret(organizationID uint, secretID string) ([]*intCluster.SecretRotationModel, error)
+}
+
+// RecordSecretInstallation records that a secret has been installed to (or merged with) a Kubernetes secret of a cluster,
+// so that the rotated values of the secret are propagated to it.
+func (m *Manager) RecordSecretInstallation(
+	ctx context.Context,
+	cluster CommonCluster,
+	secretName string,
+	req InstallSecretRequest,
+	merge bool,
+) error {
+	var spec []byte
+	if len(req.Spec) > 0 {
+		var err error
+
+		spec, err = json.Marshal(req.Spec)
+		if err != nil {
+			return emperror.With(errors.Wrap(err, "could not marshal secret spec"), "secret", secretName)
+		}
+	}
+
+	installation := &intCluster.SecretInstallationModel{
+		OrganizationID: cluster.GetOrganizationId(),
+		SecretID:       secret.GenerateSecretIDFromName(req.SourceSecretName),
+		ClusterID:      cluster.GetID(),
+		Namespace:      req.Namespace,
+		Name:           secretName,
+		Spec:           string(spec),
+		Merge:          merge,
+	}
+
+	return m.rotations.SaveInstallation(installation)
+}
+
+// GetSecretRotationPolicy returns the rotation policy of a secret.
+func (m *Manager) GetSecretRotationPolicy(ctx context.Context, organizationID uint, secretID string) (*pkgCluster.SecretRotationPolicyResponse, error) {
+	policy, err := m.rotations.FindPolicy(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy.ConvertModelToEntity(), nil
+}
+
+// SetSecretRotationPolicy creates or updates the rotation policy of a secret.
+// The next rotation is scheduled one interval after the last rotation (or the creation of the policy).
+func (m *Manager) SetSecretRotationPolicy(
+	ctx context.Context,
+	organizationID uint,
+	secretID string,
+	request *pkgCluster.SecretRotationPolicy,
+	userID uint,
+) (*pkgCluster.SecretRotationPolicyResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	secretItem, err := secret.Store.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSecretRotatable(secretItem); err != nil {
+		return nil, err
+	}
+
+	policy, err := m.rotations.FindPolicy(organizationID, secretID)
+	if isNotFoundError(err) {
+		policy = &intCluster.SecretRotationPolicyModel{
+			OrganizationID: organizationID,
+			SecretID:       secretID,
+			CreatedBy:      userID,
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	since := time.Now()
+	if policy.LastRotatedAt != nil {
+		since = *policy.LastRotatedAt
+	}
+
+	policy.IntervalDays = request.IntervalDays
+	policy.NextRotationAt = since.Add(request.Interval())
+
+	if err := m.rotations.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"secret":       secretID,
+		"interval":     request.IntervalDays,
+	}).Info("secret rotation policy saved")
+
+	return policy.ConvertModelToEntity(), nil
+}
+
+// DeleteSecretRotationPolicy deletes the rotation policy of a secret.
+func (m *Manager) DeleteSecretRotationPolicy(ctx context.Context, organizationID uint, secretID string) error {
+	return m.rotations.DeletePolicy(organizationID, secretID)
+}
+
+// GetSecretRotations returns the rotations of a secret with their results per cluster, the latest first.
+func (m *Manager) GetSecretRotations(ctx context.Context, organizationID uint, secretID string) ([]*pkgCluster.SecretRotationResponse, error) {
+	rotations, err := m.rotations.FindRotationsBySecret(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*pkgCluster.SecretRotationResponse, 0, len(rotations))
+	for _, rotation := range rotations {
+		response = append(response, rotation.ConvertModelToEntity())
+	}
+
+	return response, nil
+}
+
+// RotateSecret regenerates the values of a secret right away and propagates them to the clusters
+// the secret is installed to in the background. The next rotation of the policy of the secret is rescheduled.
+func (m *Manager) RotateSecret(
+	ctx context.Context,
+	organizationID uint,
+	secretID string,
+	userID uint,
+	updatedBy string,
+) (*pkgCluster.SecretRotationResponse, error) {
+	secretItem, err := secret.Store.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkSecretRotatable(secretItem); err != nil {
+		return nil, err
+	}
+
+	errorHandler := emperror.HandlerWith(
+		m.getErrorHandler(ctx),
+		"organization", organizationID,
+		"user", userID,
+		"secret", secretID,
+	)
+
+	policy, err := m.rotations.FindPolicy(organizationID, secretID)
+	if err == nil {
+		now := time.Now()
+		policy.LastRotatedAt = &now
+		policy.NextRotationAt = now.Add(policy.SecretRotationPolicy().Interval())
+
+		if err := m.rotations.SavePolicy(policy); err != nil {
+			errorHandler.Handle(err)
+		}
+	} else if !isNotFoundError(err) {
+		return nil, err
+	}
+
+	rotation, err := m.startSecretRotation(organizationID, secretID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := rotation.ConvertModelToEntity()
+
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		err := m.rotateSecret(ctx, rotation, secretItem.Name, updatedBy)
+		if err != nil {
+			errorHandler.Handle(err)
+		}
+	}()
+
+	return response, nil
+}
+
+// StartSecretRotator periodically rotates the secrets with due rotation policies.
+func (m *Manager) StartSecretRotator(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		for now := range ticker.C {
+			err := m.RotateDueSecrets(context.Background(), now)
+			if err != nil {
+				m.errorHandler.Handle(err)
+			}
+		}
+	}()
+}
+
+// RotateDueSecrets rotates the secrets whose rotation policy is due and propagates the new values to the clusters.
+func (m *Manager) RotateDueSecrets(ctx context.Context, now time.Time) error {
+	logger := m.getLogger(ctx)
+
+	policies, err := m.rotations.FindDuePolicies(now)
+	if err != nil {
+		return err
+	}
+
+	for _, policy := range policies {
+		logger := logger.WithFields(logrus.Fields{
+			"organization": policy.OrganizationID,
+			"secret":       policy.SecretID,
+		})
+
+		errorHandler := emperror.HandlerWith(
+			m.getErrorHandler(ctx),
+			"organization", policy.OrganizationID,
+			"secret", policy.SecretID,
+		)
+
+		claimed, err := m.rotations.ClaimPolicy(policy, now)
+		if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		// the rotation has been recorded by another Pipeline instance in the meantime
+		if !claimed {
+			continue
+		}
+
+		secretItem, err := secret.Store.Get(policy.OrganizationID, policy.SecretID)
+		if err == secret.ErrSecretNotExists {
+			logger.Info("secret not found, deleting its rotation policy")
+
+			if err := m.rotations.DeletePolicy(policy.OrganizationID, policy.SecretID); err != nil {
+				errorHandler.Handle(err)
+			}
+
+			continue
+		} else if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		rotation, err := m.startSecretRotation(policy.OrganizationID, policy.SecretID, 0)
+		if err != nil {
+			errorHandler.Handle(err)
+
+			continue
+		}
+
+		logger.Info("rotating secret according to policy")
+
+		err = m.rotateSecret(ctx, rotation, secretItem.Name, scheduledSecretRotationUpdatedBy)
+		if err != nil {
+			errorHandler.Handle(emperror.Wrap(err, "scheduled secret rotation failed"))
+		}
+	}
+
+	return nil
+}
+
+// checkSecretRotatable returns a validation error if a secret cannot be rotated.
+// Read only secrets are managed by Pipeline itself and are not rotated.
+func checkSecretRotatable(secretItem *secret.SecretItemResponse) error {
+	if err := secret.CheckRotatable(secretItem); err != nil {
+		return pkgCluster.NewValidationError(err.Error())
+	}
+
+	if err := secret.HasForbiddenTag(secretItem.Tags); err != nil {
+		return pkgCluster.NewValidationError(err.Error())
+	}
+
+	for _, tag := range secretItem.Tags {
+		if tag == pkgSecret.TagBanzaiReadonly {
+			return pkgCluster.NewValidationError(fmt.Sprintf("secret [%s] is read only", secretItem.ID))
+		}
+	}
+
+	return nil
+}
+
+// startSecretRotation persists a new running rotation of a secret.
+func (m *Manager) startSecretRotation(organizationID uint, secretID string, userID uint) (*intCluster.SecretRotationModel, error) {
+	rotation := &intCluster.SecretRotationModel{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+		State:          pkgCluster.SecretRotationRunning,
+		Actor:          userID,
+		StartedAt:      time.Now(),
+	}
+
+	if err := m.rotations.CreateRotation(rotation); err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// rotateSecret stores a new version of a secret with generated values and updates
+// the Kubernetes secrets of the clusters the secret is installed to.
+// The result of the propagation is recorded per Kubernetes secret.
+func (m *Manager) rotateSecret(ctx context.Context, rotation *intCluster.SecretRotationModel, secretName string, updatedBy string) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": rotation.OrganizationID,
+		"secret":       rotation.SecretID,
+	})
+
+	err := m.propagateSecretRotation(ctx, rotation, secretName, updatedBy)
+
+	finishedAt := time.Now()
+	rotation.FinishedAt = &finishedAt
+	rotation.State = pkgCluster.SecretRotationSucceeded
+
+	if err != nil {
+		rotation.State = pkgCluster.SecretRotationFailed
+		rotation.Error = err.Error()
+	}
+
+	if err := m.rotations.SaveRotation(rotation); err != nil {
+		return err
+	}
+
+	if err == nil {
+		logger.WithField("version", rotation.Version).Info("secret rotated")
+	}
+
+	return err
+}
+
+func (m *Manager) propagateSecretRotation(ctx context.Context, rotation *intCluster.SecretRotationModel, secretName string, updatedBy string) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": rotation.OrganizationID,
+		"secret":       rotation.SecretID,
+	})
+
+	version, err := secret.RestrictedStore.Rotate(rotation.OrganizationID, rotation.SecretID, updatedBy)
+	if err != nil {
+		return emperror.Wrap(err, "could not generate new secret values")
+	}
+
+	rotation.Version = version
+
+	if err := m.rotations.SaveRotation(rotation); err != nil {
+		return err
+	}
+
+	installations, err := m.rotations.FindInstallationsBySecret(rotation.OrganizationID, rotation.SecretID)
+	if err != nil {
+		return err
+	}
+
+	type clusterKubeConfig struct {
+		kubeConfig []byte
+		err        error
+	}
+
+	kubeConfigs := make(map[uint]clusterKubeConfig)
+	failed := 0
+
+	for _, installation := range installations {
+		result := intCluster.SecretRotationClusterModel{
+			ClusterID: installation.ClusterID,
+			Namespace: installation.Namespace,
+			Name:      installation.Name,
+			State:     pkgCluster.SecretRotationSucceeded,
+		}
+
+		config, ok := kubeConfigs[installation.ClusterID]
+		if !ok {
+			config.kubeConfig, config.err = m.getSecretRotationKubeConfig(rotation.OrganizationID, installation.ClusterID)
+			kubeConfigs[installation.ClusterID] = config
+
+			if config.err == nil && config.kubeConfig == nil {
+				logger.WithField("cluster", installation.ClusterID).Info("cluster not found, deleting its secret installations")
+
+				if err := m.rotations.DeleteInstallationsByClusterID(installation.ClusterID); err != nil {
+					m.getErrorHandler(ctx).Handle(err)
+				}
+			}
+		}
+
+		err := config.err
+		if err == nil && config.kubeConfig != nil {
+			err = propagateSecret(config.kubeConfig, rotation.OrganizationID, secretName, installation)
+		}
+
+		if err == nil && config.kubeConfig == nil {
+			result.State = SecretRotationSkipped
+			result.Error = "cluster not found"
+		} else if err == ErrKubernetesSecretNotFound {
+			result.State = SecretRotationSkipped
+			result.Error = err.Error()
+		} else if err != nil {
+			logger.WithField("cluster", installation.ClusterID).Errorf("could not propagate rotated secret: %s", err.Error())
+
+			result.State = pkgCluster.SecretRotationFailed
+			result.Error = err.Error()
+
+			failed++
+		}
+
+		rotation.Clusters = append(rotation.Clusters, result)
+	}
+
+	if failed > 0 {
+		return errors.Errorf("could not propagate rotated secret to %d of %d Kubernetes secrets", failed, len(installations))
+	}
+
+	return nil
+}
+
+// getSecretRotationKubeConfig returns the Kubernetes config of a cluster, or nil if the cluster no longer exists.
+func (m *Manager) getSecretRotationKubeConfig(organizationID uint, clusterID uint) ([]byte, error) {
+	clusterModel, err := m.clusters.FindOneByID(organizationID, clusterID)
+	if isNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	cluster, err := m.getClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, emperror.Wrap(err, "converting cluster model to common cluster failed")
+	}
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get kubernetes config")
+	}
+
+	return kubeConfig, nil
+}
+
+// propagateSecret updates a Kubernetes secret installed from (or merged with) a secret with the current values of the secret.
+func propagateSecret(kubeConfig []byte, organizationID uint, secretName string, installation *intCluster.SecretInstallationModel) error {
+	req := InstallSecretRequest{
+		SourceSecretName: secretName,
+		Namespace:        installation.Namespace,
+	}
+
+	if installation.Spec != "" {
+		if err := json.Unmarshal([]byte(installation.Spec), &req.Spec); err != nil {
+			return emperror.With(errors.Wrap(err, "could not unmarshal secret spec"), "secret", installation.Name)
+		}
+	}
+
+	// installed secrets contain the values of the secret only, so merging replaces all of them
+	_, err := MergeSecretByK8SConfig(kubeConfig, organizationID, installation.Name, req)
+
+	return err
+}
@@ -0,0 +1,36 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// GetClusterStatusHistory returns the status transitions of a cluster in the order they happened.
+func (m *Manager) GetClusterStatusHistory(ctx context.Context, cluster CommonCluster) ([]*pkgCluster.StatusHistoryItem, error) {
+	history, err := m.clusters.FindStatusHistory(cluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*pkgCluster.StatusHistoryItem, 0, len(history))
+	for _, transition := range history {
+		response = append(response, transition.ConvertModelToEntity())
+	}
+
+	return response, nil
+}
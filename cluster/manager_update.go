@@ -41,6 +41,39 @@ type clusterUpdater interface {
 	Update(ctx context.Context) error
 }
 
+type clusterUpdatePlanner interface {
+	// Validate validates the cluster update context.
+	Validate(ctx context.Context) error
+
+	// Plan describes the changes an update would make without touching the cluster.
+	Plan(ctx context.Context) (*pkgCluster.UpdatePlanResponse, error)
+}
+
+// PlanClusterUpdate validates a cluster update and returns the changes it would make (dry run).
+func (m *Manager) PlanClusterUpdate(ctx context.Context, updateCtx UpdateContext, planner clusterUpdatePlanner) (*pkgCluster.UpdatePlanResponse, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": updateCtx.OrganizationID,
+		"user":         updateCtx.UserID,
+		"cluster":      updateCtx.ClusterID,
+	})
+
+	logger.Info("validating update context")
+
+	err := planner.Validate(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cluster update validation failed")
+	}
+
+	logger.Info("planning cluster update")
+
+	plan, err := planner.Plan(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not plan cluster update")
+	}
+
+	return plan, nil
+}
+
 // UpdateCluster updates a cluster.
 func (m *Manager) UpdateCluster(ctx context.Context, updateCtx UpdateContext, updater clusterUpdater) error {
 	logger := m.getLogger(ctx).WithFields(logrus.Fields{
@@ -65,23 +98,40 @@ func (m *Manager) UpdateCluster(ctx context.Context, updateCtx UpdateContext, up
 
 	logger.Info("update context is valid")
 
+	logger.Info("checking organization quota")
+	if err := m.checkUpdateQuota(ctx, updateCtx.OrganizationID, updater); err != nil {
+		return err
+	}
+
 	logger.Info("preparing cluster update")
 
+	lock, err := m.lockCluster(ctx, updateCtx.ClusterID, pkgCluster.OperationUpdate)
+	if err != nil {
+		return err
+	}
+
 	cluster, err := updater.Prepare(ctx)
 	if err != nil {
+		lock.Release()
+
 		return errors.WithMessage(err, "could not prepare cluster")
 	}
 
 	if err := cluster.UpdateStatus(pkgCluster.Updating, pkgCluster.UpdatingMessage); err != nil {
+		lock.Release()
+
 		return emperror.With(err, "could not update cluster status")
 	}
 
-	logger.Info("updating cluster")
+	operation := m.startOperation(ctx, cluster, pkgCluster.OperationUpdate, updateCtx.UserID, lock)
+
+	logger.WithField("operation", operation.ID()).Info("updating cluster")
 
 	go func() {
 		defer emperror.HandleRecover(m.errorHandler)
 
-		err := m.updateCluster(ctx, updateCtx, cluster, updater)
+		err := m.updateCluster(ctx, updateCtx, cluster, updater, operation)
+		operation.Finish(err)
 		if err != nil {
 			errorHandler.Handle(err)
 		}
@@ -90,7 +140,7 @@ func (m *Manager) UpdateCluster(ctx context.Context, updateCtx UpdateContext, up
 	return nil
 }
 
-func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cluster CommonCluster, updater clusterUpdater) error {
+func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cluster CommonCluster, updater clusterUpdater, operation *clusterOperation) error {
 	logger := m.getLogger(ctx).WithFields(logrus.Fields{
 		"organization": updateCtx.OrganizationID,
 		"user":         updateCtx.UserID,
@@ -98,6 +148,7 @@ func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cl
 	})
 
 	logger.Info("updating cluster")
+	operation.Step("UpdateCluster", "updating cluster at the provider")
 
 	err := updater.Update(ctx)
 	if err != nil {
@@ -111,11 +162,13 @@ func (m *Manager) updateCluster(ctx context.Context, updateCtx UpdateContext, cl
 	}
 
 	logger.Info("deploying cluster autoscaler")
+	operation.Step("DeployClusterAutoscaler", "deploying cluster autoscaler")
 	if err := DeployClusterAutoscaler(cluster); err != nil {
 		return emperror.Wrap(err, "deploying cluster autoscaler failed")
 	}
 
 	logger.Info("adding labels to nodes")
+	operation.Step("LabelNodes", "adding labels to nodes")
 	if err := LabelNodes(cluster); err != nil {
 		return emperror.Wrap(err, "adding labels to nodes failed")
 	}
@@ -0,0 +1,249 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Masterminds/semver"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// kubernetesUpgrader is implemented by clusters whose control plane and node pools
+// can be upgraded to a new Kubernetes version separately.
+type kubernetesUpgrader interface {
+	// UpgradeControlPlane upgrades the control plane of the cluster.
+	UpgradeControlPlane(version string) error
+
+	// UpgradeNodePool upgrades a single node pool of the cluster.
+	UpgradeNodePool(name string, version string) error
+}
+
+// ValidateKubernetesUpgrade checks whether a cluster can be upgraded from the current version to the target version.
+// Only upgrades to an available version within the same major version are allowed, without skipping minor versions.
+func ValidateKubernetesUpgrade(current string, target string, available []string) error {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return emperror.Wrap(err, "could not parse current Kubernetes version")
+	}
+
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return pkgCluster.NewValidationError(fmt.Sprintf("invalid Kubernetes version [%s]: %s", target, err.Error()))
+	}
+
+	if !targetVersion.GreaterThan(currentVersion) {
+		return pkgCluster.NewValidationError(
+			fmt.Sprintf("target version [%s] must be greater than the current version [%s]", target, current),
+		)
+	}
+
+	if targetVersion.Major() != currentVersion.Major() {
+		return pkgCluster.NewValidationError("upgrading to a different major version is not supported")
+	}
+
+	if targetVersion.Minor() > currentVersion.Minor()+1 {
+		return pkgCluster.NewValidationError(fmt.Sprintf(
+			"skipping minor versions is not allowed, upgrade to %d.%d first",
+			currentVersion.Major(),
+			currentVersion.Minor()+1,
+		))
+	}
+
+	for _, version := range available {
+		if version == target {
+			return nil
+		}
+	}
+
+	return pkgCluster.NewValidationError(fmt.Sprintf("Kubernetes version [%s] is not available", target))
+}
+
+// validateClusterUpgrade checks whether a cluster can be upgraded to a Kubernetes version right now.
+func validateClusterUpgrade(cluster CommonCluster, version string) (kubernetesUpgrader, error) {
+	distribution, err := GetDistribution(cluster.GetCloud())
+	if err != nil {
+		return nil, err
+	}
+
+	upgrader, ok := cluster.(kubernetesUpgrader)
+	if !ok || distribution.KubernetesVersions == nil {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("upgrading the Kubernetes version of %s clusters is not supported", cluster.GetCloud()),
+		)
+	}
+
+	status, err := cluster.GetStatus()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster status")
+	}
+
+	if status.Status != pkgCluster.Running && status.Status != pkgCluster.Warning {
+		return nil, pkgCluster.NewPreconditionFailedError(
+			fmt.Sprintf("cluster is not in %s or %s state yet", pkgCluster.Running, pkgCluster.Warning),
+		)
+	}
+
+	available, err := distribution.KubernetesVersions(cluster)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get available Kubernetes versions")
+	}
+
+	err = ValidateKubernetesUpgrade(status.Version, version, available)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cluster upgrade validation failed")
+	}
+
+	return upgrader, nil
+}
+
+// UpgradeCluster upgrades the Kubernetes version of a cluster: first the control plane, then the node pools one by one.
+func (m *Manager) UpgradeCluster(ctx context.Context, cluster CommonCluster, version string, userID uint) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"user":         userID,
+		"cluster":      cluster.GetID(),
+		"version":      version,
+	})
+
+	errorHandler := emperror.HandlerWith(
+		m.getErrorHandler(ctx),
+		"organization", cluster.GetOrganizationId(),
+		"user", userID,
+		"cluster", cluster.GetID(),
+	)
+
+	logger.Info("validating Kubernetes version")
+
+	upgrader, err := validateClusterUpgrade(cluster, version)
+	if err != nil {
+		return err
+	}
+
+	lock, err := m.lockCluster(ctx, cluster.GetID(), pkgCluster.OperationUpgrade)
+	if err != nil {
+		return err
+	}
+
+	if err := cluster.UpdateStatus(pkgCluster.Updating, fmt.Sprintf("Upgrading Kubernetes to %s", version)); err != nil {
+		lock.Release()
+
+		return emperror.With(err, "could not update cluster status")
+	}
+
+	operation := m.startOperation(ctx, cluster, pkgCluster.OperationUpgrade, userID, lock)
+
+	logger.WithField("operation", operation.ID()).Info("upgrading cluster")
+
+	go func() {
+		defer emperror.HandleRecover(m.errorHandler)
+
+		err := m.upgradeCluster(ctx, cluster, upgrader, version, operation)
+		operation.Finish(err)
+		if err != nil {
+			errorHandler.Handle(err)
+		}
+	}()
+
+	return nil
+}
+
+func (m *Manager) upgradeCluster(
+	ctx context.Context,
+	cluster CommonCluster,
+	upgrader kubernetesUpgrader,
+	version string,
+	operation *clusterOperation,
+) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+		"version":      version,
+	})
+
+	// every step is persisted together with the status, so that the current versions are always stored
+	message := fmt.Sprintf("Upgrading control plane to %s", version)
+
+	logger.Info("upgrading control plane")
+	operation.Step("UpgradeControlPlane", message)
+	if err := cluster.Persist(pkgCluster.Updating, message); err != nil {
+		return emperror.Wrap(err, "could not update cluster status")
+	}
+
+	if err := upgrader.UpgradeControlPlane(version); err != nil {
+		cluster.Persist(pkgCluster.Warning, err.Error())
+
+		return emperror.Wrap(err, "error upgrading control plane")
+	}
+
+	// node pools upgraded along with the control plane are skipped
+	nodePools, err := getOutdatedNodePools(cluster, version)
+	if err != nil {
+		return err
+	}
+
+	for i, name := range nodePools {
+		message := fmt.Sprintf("Upgrading node pool %s to %s (%d/%d)", name, version, i+1, len(nodePools))
+
+		logger.WithField("nodePool", name).Info("upgrading node pool")
+		operation.Step("UpgradeNodePool", message)
+		if err := cluster.Persist(pkgCluster.Updating, message); err != nil {
+			return emperror.Wrap(err, "could not update cluster status")
+		}
+
+		if err := upgrader.UpgradeNodePool(name, version); err != nil {
+			cluster.Persist(pkgCluster.Warning, err.Error())
+
+			return emperror.With(emperror.Wrap(err, "error upgrading node pool"), "nodePool", name)
+		}
+	}
+
+	if err := cluster.Persist(pkgCluster.Running, pkgCluster.RunningMessage); err != nil {
+		return emperror.Wrap(err, "could not update cluster status")
+	}
+
+	logger.Info("adding labels to nodes")
+	operation.Step("LabelNodes", "adding labels to nodes")
+	if err := LabelNodes(cluster); err != nil {
+		return emperror.Wrap(err, "adding labels to nodes failed")
+	}
+
+	logger.Info("cluster upgraded successfully")
+
+	return nil
+}
+
+// getOutdatedNodePools returns the names of the node pools of a cluster not running the given Kubernetes version.
+func getOutdatedNodePools(cluster CommonCluster, version string) ([]string, error) {
+	status, err := cluster.GetStatus()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster status")
+	}
+
+	var nodePools []string
+	for name, nodePool := range status.NodePools {
+		if nodePool != nil && nodePool.Version != version {
+			nodePools = append(nodePools, name)
+		}
+	}
+	sort.Strings(nodePools)
+
+	return nodePools, nil
+}
@@ -0,0 +1,93 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestValidateKubernetesUpgrade(t *testing.T) {
+	available := []string{"1.9.7-gke.11", "1.10.9-gke.5", "1.11.2-gke.18", "1.11"}
+
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		invalid bool
+	}{
+		{name: "patch", current: "1.10.6-gke.2", target: "1.10.9-gke.5"},
+		{name: "minor", current: "1.10.9-gke.5", target: "1.11.2-gke.18"},
+		{name: "minor alias", current: "1.10.9-gke.5", target: "1.11"},
+		{name: "skipping minor", current: "1.9.7-gke.11", target: "1.11.2-gke.18", invalid: true},
+		{name: "downgrade", current: "1.11.2-gke.18", target: "1.10.9-gke.5", invalid: true},
+		{name: "same version", current: "1.10.9-gke.5", target: "1.10.9-gke.5", invalid: true},
+		{name: "not available", current: "1.10.9-gke.5", target: "1.11.1-gke.1", invalid: true},
+		{name: "malformed", current: "1.10.9-gke.5", target: "latest", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateKubernetesUpgrade(test.current, test.target, available)
+			if test.invalid {
+				if _, ok := err.(*pkgCluster.ValidationError); !ok {
+					t.Fatalf("expected a validation error, got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+type upgradeStatusCluster struct {
+	CommonCluster
+
+	status *pkgCluster.GetClusterStatusResponse
+}
+
+func (c *upgradeStatusCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
+	return c.status, nil
+}
+
+func TestGetOutdatedNodePools(t *testing.T) {
+	cluster := &upgradeStatusCluster{
+		status: &pkgCluster.GetClusterStatusResponse{
+			Version: "1.11.5",
+			NodePools: map[string]*pkgCluster.NodePoolStatus{
+				"pool3": {Version: "1.10.9"},
+				"pool1": {Version: "1.11.5"},
+				"pool2": {Version: "1.10.9"},
+				"pool4": nil,
+			},
+		},
+	}
+
+	nodePools, err := getOutdatedNodePools(cluster, "1.11.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := []string{"pool2", "pool3"}
+	if !reflect.DeepEqual(nodePools, expected) {
+		t.Errorf("expected node pools %v, got %v", expected, nodePools)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/dummy"
+	pkgEks "github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	pkgClusterGoogle "github.com/banzaicloud/pipeline/pkg/cluster/gke"
+	oke "github.com/banzaicloud/pipeline/pkg/providers/oracle/cluster"
+)
+
+// IsNodePoolShrink tells whether a node pool change may remove nodes: deleting the node pool (nil request),
+// lowering its node count or lowering its maximum size when autoscaling is enabled.
+func IsNodePoolShrink(current *pkgCluster.NodePoolStatus, nodePool *pkgCluster.NodePoolRequest) bool {
+	if current == nil {
+		return false
+	}
+
+	if nodePool == nil {
+		return true
+	}
+
+	if nodePool.Autoscaling {
+		return nodePool.MaxCount < current.Count || (current.Autoscaling && nodePool.MaxCount < current.MaxCount)
+	}
+
+	return nodePool.Count < current.Count
+}
+
+// NewNodePoolUpdateRequest returns an update request which adds or changes a single node pool of a cluster
+// (or removes it when the node pool is nil) and keeps the rest of the node pools as they are.
+// Existing node pools can only be resized, their instance type can not be changed.
+func NewNodePoolUpdateRequest(
+	cluster CommonCluster,
+	name string,
+	nodePool *pkgCluster.NodePoolRequest,
+) (*pkgCluster.UpdateClusterRequest, error) {
+	if name == "" {
+		return nil, pkgCluster.NewValidationError("node pool name is empty")
+	}
+
+	spec, err := GetClusterSpec(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution, err := GetDistribution(cluster.GetCloud())
+	if err != nil || distribution.SetNodePool == nil {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("managing node pools of %s clusters is not supported", cluster.GetCloud()),
+		)
+	}
+
+	if err := distribution.SetNodePool(spec.Properties, name, nodePool); err != nil {
+		return nil, err
+	}
+
+	return NewUpdateRequestFromSpec(cluster, spec)
+}
+
+func setEKSNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error {
+	nodePools := properties.CreateClusterEKS.NodePools
+
+	current, ok := nodePools[name]
+	if err := validateNodePoolChange(name, nodePool, ok, len(nodePools), true); err != nil {
+		return err
+	}
+
+	if nodePool == nil {
+		delete(nodePools, name)
+	} else if ok {
+		if err := validateNodePoolInstanceType(name, nodePool, current.InstanceType); err != nil {
+			return err
+		}
+
+		current.Autoscaling = nodePool.Autoscaling
+		current.MinCount = nodePool.MinCount
+		current.MaxCount = nodePool.MaxCount
+		current.Count = nodePool.Count
+	} else {
+		nodePools[name] = &pkgEks.NodePool{
+			InstanceType: nodePool.InstanceType,
+			SpotPrice:    nodePool.SpotPrice,
+			Autoscaling:  nodePool.Autoscaling,
+			MinCount:     nodePool.MinCount,
+			MaxCount:     nodePool.MaxCount,
+			Count:        nodePool.Count,
+			Image:        nodePool.Image,
+		}
+	}
+
+	return nil
+}
+
+// Azure does not support adding and deleting node pools of existing clusters
+func setAKSNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error {
+	nodePools := properties.CreateClusterAKS.NodePools
+
+	current, ok := nodePools[name]
+	if err := validateNodePoolChange(name, nodePool, ok, len(nodePools), false); err != nil {
+		return err
+	}
+
+	if err := validateNodePoolInstanceType(name, nodePool, current.NodeInstanceType); err != nil {
+		return err
+	}
+
+	current.Autoscaling = nodePool.Autoscaling
+	current.MinCount = nodePool.MinCount
+	current.MaxCount = nodePool.MaxCount
+	current.Count = nodePool.Count
+
+	return nil
+}
+
+func setGKENodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error {
+	nodePools := properties.CreateClusterGKE.NodePools
+
+	current, ok := nodePools[name]
+	if err := validateNodePoolChange(name, nodePool, ok, len(nodePools), true); err != nil {
+		return err
+	}
+
+	if nodePool == nil {
+		delete(nodePools, name)
+	} else if ok {
+		if err := validateNodePoolInstanceType(name, nodePool, current.NodeInstanceType); err != nil {
+			return err
+		}
+
+		current.Autoscaling = nodePool.Autoscaling
+		current.MinCount = nodePool.MinCount
+		current.MaxCount = nodePool.MaxCount
+		current.Count = nodePool.Count
+	} else {
+		nodePools[name] = &pkgClusterGoogle.NodePool{
+			Autoscaling:      nodePool.Autoscaling,
+			MinCount:         nodePool.MinCount,
+			MaxCount:         nodePool.MaxCount,
+			Count:            nodePool.Count,
+			NodeInstanceType: nodePool.InstanceType,
+		}
+	}
+
+	return nil
+}
+
+// ACSK only supports changing the node count of existing node pools
+func setACSKNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error {
+	nodePools := properties.CreateClusterACSK.NodePools
+
+	current, ok := nodePools[name]
+	if err := validateNodePoolChange(name, nodePool, ok, len(nodePools), false); err != nil {
+		return err
+	}
+
+	if err := validateNodePoolInstanceType(name, nodePool, current.InstanceType); err != nil {
+		return err
+	}
+
+	if nodePool.Autoscaling {
+		return pkgCluster.NewValidationError("autoscaling is not supported for ACSK node pools")
+	}
+
+	current.Count = nodePool.Count
+
+	return nil
+}
+
+func setOKENodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error {
+	nodePools := properties.CreateClusterOKE.NodePools
+
+	current, ok := nodePools[name]
+	if err := validateNodePoolChange(name, nodePool, ok, len(nodePools), true); err != nil {
+		return err
+	}
+
+	if nodePool != nil && nodePool.Autoscaling {
+		return pkgCluster.NewValidationError("autoscaling is not supported for OKE node pools")
+	}
+
+	if nodePool != nil && nodePool.Count < 0 {
+		return pkgCluster.NewValidationError("node count must not be negative")
+	}
+
+	if nodePool == nil {
+		delete(nodePools, name)
+	} else if ok {
+		if err := validateNodePoolInstanceType(name, nodePool, current.Shape); err != nil {
+			return err
+		}
+
+		current.Count = uint(nodePool.Count)
+	} else {
+		nodePools[name] = &oke.NodePool{
+			Version: properties.CreateClusterOKE.Version,
+			Count:   uint(nodePool.Count),
+			Image:   nodePool.Image,
+			Shape:   nodePool.InstanceType,
+		}
+	}
+
+	return nil
+}
+
+func setDummyNodePool(properties *pkgCluster.CreateClusterProperties, name string, nodePool *pkgCluster.NodePoolRequest) error {
+	nodePools := properties.CreateClusterDummy.NodePools
+
+	current, ok := nodePools[name]
+	if err := validateNodePoolChange(name, nodePool, ok, len(nodePools), true); err != nil {
+		return err
+	}
+
+	if nodePool == nil {
+		delete(nodePools, name)
+	} else if ok {
+		if err := validateNodePoolInstanceType(name, nodePool, current.InstanceType); err != nil {
+			return err
+		}
+
+		current.Autoscaling = nodePool.Autoscaling
+		current.MinCount = nodePool.MinCount
+		current.MaxCount = nodePool.MaxCount
+		current.Count = nodePool.Count
+	} else {
+		nodePools[name] = &dummy.NodePool{
+			InstanceType: nodePool.InstanceType,
+			Autoscaling:  nodePool.Autoscaling,
+			MinCount:     nodePool.MinCount,
+			MaxCount:     nodePool.MaxCount,
+			Count:        nodePool.Count,
+		}
+	}
+
+	return nil
+}
+
+// validateNodePoolChange checks whether a node pool can be added, changed or removed.
+func validateNodePoolChange(name string, nodePool *pkgCluster.NodePoolRequest, exists bool, count int, addRemove bool) error {
+	if nodePool == nil {
+		if !exists {
+			return pkgCluster.NewValidationError(fmt.Sprintf("node pool [%s] does not exist", name))
+		}
+
+		if !addRemove {
+			return pkgCluster.NewValidationError("removing node pools is not supported for this cluster")
+		}
+
+		if count <= 1 {
+			return pkgCluster.NewValidationError("the last node pool of a cluster cannot be removed")
+		}
+
+		return nil
+	}
+
+	if !exists && !addRemove {
+		return pkgCluster.NewValidationError("adding node pools is not supported for this cluster")
+	}
+
+	if !exists && nodePool.InstanceType == "" {
+		return pkgCluster.NewValidationError("instance type of a new node pool must be specified")
+	}
+
+	return nil
+}
+
+// validateNodePoolInstanceType checks that the instance type of an existing node pool is not changed.
+func validateNodePoolInstanceType(name string, nodePool *pkgCluster.NodePoolRequest, instanceType string) error {
+	if nodePool.InstanceType != "" && nodePool.InstanceType != instanceType {
+		return pkgCluster.NewValidationError(
+			fmt.Sprintf("instance type of node pool [%s] cannot be changed from [%s] to [%s]", name, instanceType, nodePool.InstanceType),
+		)
+	}
+
+	return nil
+}
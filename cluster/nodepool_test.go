@@ -0,0 +1,89 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestValidateNodePoolChange(t *testing.T) {
+	tests := []struct {
+		name      string
+		nodePool  *pkgCluster.NodePoolRequest
+		exists    bool
+		count     int
+		addRemove bool
+		invalid   bool
+	}{
+		{name: "add", nodePool: &pkgCluster.NodePoolRequest{InstanceType: "m4.xlarge"}, count: 1, addRemove: true},
+		{name: "add without instance type", nodePool: &pkgCluster.NodePoolRequest{}, count: 1, addRemove: true, invalid: true},
+		{name: "add not supported", nodePool: &pkgCluster.NodePoolRequest{InstanceType: "m4.xlarge"}, count: 1, invalid: true},
+		{name: "resize", nodePool: &pkgCluster.NodePoolRequest{Count: 3}, exists: true, count: 1},
+		{name: "remove", exists: true, count: 2, addRemove: true},
+		{name: "remove missing", count: 2, addRemove: true, invalid: true},
+		{name: "remove last", exists: true, count: 1, addRemove: true, invalid: true},
+		{name: "remove not supported", exists: true, count: 2, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateNodePoolChange("pool1", test.nodePool, test.exists, test.count, test.addRemove)
+			if test.invalid {
+				if _, ok := err.(*pkgCluster.ValidationError); !ok {
+					t.Fatalf("expected a validation error, got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestIsNodePoolShrink(t *testing.T) {
+	current := &pkgCluster.NodePoolStatus{Count: 3}
+	autoscaling := &pkgCluster.NodePoolStatus{Autoscaling: true, Count: 3, MinCount: 1, MaxCount: 5}
+
+	tests := []struct {
+		name     string
+		current  *pkgCluster.NodePoolStatus
+		nodePool *pkgCluster.NodePoolRequest
+		shrink   bool
+	}{
+		{name: "add", nodePool: &pkgCluster.NodePoolRequest{Count: 1}},
+		{name: "delete", current: current, shrink: true},
+		{name: "grow", current: current, nodePool: &pkgCluster.NodePoolRequest{Count: 4}},
+		{name: "same size", current: current, nodePool: &pkgCluster.NodePoolRequest{Count: 3}},
+		{name: "shrink", current: current, nodePool: &pkgCluster.NodePoolRequest{Count: 2}, shrink: true},
+		{name: "enable autoscaling", current: current, nodePool: &pkgCluster.NodePoolRequest{Autoscaling: true, MinCount: 1, MaxCount: 5}},
+		{name: "enable autoscaling below count", current: current, nodePool: &pkgCluster.NodePoolRequest{Autoscaling: true, MinCount: 1, MaxCount: 2}, shrink: true},
+		{name: "raise maximum", current: autoscaling, nodePool: &pkgCluster.NodePoolRequest{Autoscaling: true, MinCount: 1, MaxCount: 6}},
+		{name: "lower maximum", current: autoscaling, nodePool: &pkgCluster.NodePoolRequest{Autoscaling: true, MinCount: 1, MaxCount: 4}, shrink: true},
+		{name: "disable autoscaling", current: autoscaling, nodePool: &pkgCluster.NodePoolRequest{Count: 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if shrink := IsNodePoolShrink(test.current, test.nodePool); shrink != test.shrink {
+				t.Fatalf("expected shrink to be %t", test.shrink)
+			}
+		})
+	}
+}
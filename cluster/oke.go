@@ -17,6 +17,7 @@ package cluster
 import (
 	"fmt"
 
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/model"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
@@ -47,6 +48,52 @@ func CreateOKEClusterFromModel(clusterModel *model.ClusterModel) (*OKECluster, e
 	return &okeCluster, nil
 }
 
+// loadOKEClusterFromModel creates the cluster from the model and loads its Oracle properties from the database
+func loadOKEClusterFromModel(clusterModel *model.ClusterModel) (CommonCluster, error) {
+	okeCluster, err := CreateOKEClusterFromModel(clusterModel)
+	if err != nil {
+		return nil, err
+	}
+
+	db := config.DB()
+
+	log.Debug("Load Oracle props from database")
+	err = db.Where(modelOracle.Cluster{ClusterModelID: okeCluster.modelCluster.ID}).Preload("NodePools.Subnets").Preload("NodePools.Labels").First(&okeCluster.modelCluster.OKE).Error
+
+	return okeCluster, err
+}
+
+func init() {
+	RegisterDistribution(Distribution{
+		Cloud: pkgCluster.Oracle,
+		CreateFromRequest: func(request *pkgCluster.CreateClusterRequest, orgID uint, userID uint) (CommonCluster, error) {
+			cluster, err := CreateOKEClusterFromRequest(request, orgID, userID)
+			if err != nil {
+				return nil, err
+			}
+
+			return cluster, nil
+		},
+		CreateFromModel: loadOKEClusterFromModel,
+		AddDefaults: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterOKE.AddDefaults()
+		},
+		Validate: func(request *pkgCluster.CreateClusterRequest) error {
+			return request.Properties.CreateClusterOKE.Validate(false)
+		},
+		ValidateUpdate: func(request *pkgCluster.UpdateClusterRequest) error {
+			return request.OKE.Validate(true)
+		},
+		KubernetesVersions: func(cluster CommonCluster) ([]string, error) {
+			return GetOKEKubernetesVersions(cluster.GetOrganizationId(), cluster.GetSecretId(), cluster.GetLocation())
+		},
+		GetSpec:          getOKESpec,
+		UpdateFromSpec:   updateOKEFromSpec,
+		SetNodePool:      setOKENodePool,
+		OverrideNodePool: overrideOKENodePool,
+	})
+}
+
 // CreateOKEClusterFromRequest creates ClusterModel struct from the request
 func CreateOKEClusterFromRequest(request *pkgCluster.CreateClusterRequest, orgId, userId uint) (*OKECluster, error) {
 	log.Debug("Create ClusterModel struct from the request")
@@ -136,6 +183,81 @@ func (o *OKECluster) UpdateCluster(r *pkgCluster.UpdateClusterRequest, userId ui
 	return err
 }
 
+// GetOKEKubernetesVersions returns the Kubernetes versions available in a region
+func GetOKEKubernetesVersions(orgID uint, secretID string, region string) ([]string, error) {
+
+	oc, err := CreateOKEClusterFromModel(&model.ClusterModel{
+		OrganizationId: orgID,
+		SecretId:       secretID,
+		Cloud:          pkgCluster.Oracle,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	oci, err := oc.GetOCI()
+	if err != nil {
+		return nil, err
+	}
+
+	return oci.GetSupportedK8SVersionsInARegion(region)
+}
+
+// UpgradeControlPlane upgrades the cluster to the given Kubernetes version, the node pools are left as they are
+func (o *OKECluster) UpgradeControlPlane(version string) error {
+
+	cm, err := o.GetClusterManager()
+	if err != nil {
+		return err
+	}
+
+	previousVersion := o.modelCluster.OKE.Version
+	o.modelCluster.OKE.Version = version
+
+	err = cm.UpdateCluster(&o.modelCluster.OKE)
+	if err != nil {
+		o.modelCluster.OKE.Version = previousVersion
+		return err
+	}
+
+	return nil
+}
+
+// UpgradeNodePool upgrades a node pool to the given Kubernetes version
+func (o *OKECluster) UpgradeNodePool(name string, version string) error {
+
+	nodePool := o.modelCluster.OKE.GetNodePoolByName(name)
+	if nodePool.ID == 0 {
+		return fmt.Errorf("NodePool[%s] not found", name)
+	}
+
+	cm, err := o.GetClusterManager()
+	if err != nil {
+		return err
+	}
+
+	previousVersion := nodePool.Version
+	nodePool.Version = version
+
+	err = cm.UpdateNodePool(&o.modelCluster.OKE, nodePool)
+	if err != nil {
+		nodePool.Version = previousVersion
+		return err
+	}
+
+	oci, err := o.GetOCIWithRegion(o.modelCluster.Location)
+	if err != nil {
+		return err
+	}
+
+	ce, err := oci.NewContainerEngineClient()
+	if err != nil {
+		return err
+	}
+
+	return ce.WaitingForClusterNodePoolActiveState(&o.modelCluster.OKE.OCID, map[string]bool{name: true})
+}
+
 // DeleteCluster deletes cluster
 func (o *OKECluster) DeleteCluster() error {
 
@@ -0,0 +1,182 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+)
+
+// postHookGraph describes the dependencies between the posthooks of a single run.
+// Dependencies on posthooks which are not part of the run are considered to be satisfied.
+type postHookGraph struct {
+	names        []string
+	dependencies []int
+	dependents   [][]int
+}
+
+// newPostHookGraph builds the dependency graph of the given posthooks and checks it for cycles.
+func newPostHookGraph(names []string, dependencies [][]string) (*postHookGraph, error) {
+	indexes := make(map[string]int, len(names))
+	for i, name := range names {
+		indexes[name] = i
+	}
+
+	graph := &postHookGraph{
+		names:        names,
+		dependencies: make([]int, len(names)),
+		dependents:   make([][]int, len(names)),
+	}
+
+	for i := range names {
+		seen := make(map[int]bool)
+
+		for _, dependency := range dependencies[i] {
+			j, ok := indexes[dependency]
+			if !ok || j == i || seen[j] {
+				continue
+			}
+
+			seen[j] = true
+			graph.dependencies[i]++
+			graph.dependents[j] = append(graph.dependents[j], i)
+		}
+	}
+
+	if err := graph.checkCycles(); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// checkCycles makes sure every posthook can be started eventually.
+func (g *postHookGraph) checkCycles() error {
+	remaining := make([]int, len(g.dependencies))
+	copy(remaining, g.dependencies)
+
+	var ready []int
+	for i, count := range remaining {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	visited := 0
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		visited++
+
+		for _, j := range g.dependents[i] {
+			remaining[j]--
+			if remaining[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	if visited == len(remaining) {
+		return nil
+	}
+
+	var cyclic []string
+	for i, count := range remaining {
+		if count > 0 {
+			cyclic = append(cyclic, g.names[i])
+		}
+	}
+
+	return pkgCluster.NewValidationError(fmt.Sprintf("posthooks have cyclic dependencies: %v", cyclic))
+}
+
+type postHookResult struct {
+	index int
+	err   error
+}
+
+// run executes the posthooks on at most the given number of workers, starting each one as soon as its dependencies succeeded.
+// The finished callback is called sequentially from the calling goroutine.
+// Once a posthook fails no more posthooks are started, the running ones are waited for and the first error is returned.
+func (g *postHookGraph) run(workers int, do func(i int) error, finished func(i int, err error)) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	remaining := make([]int, len(g.dependencies))
+	copy(remaining, g.dependencies)
+
+	var ready []int
+	for i, count := range remaining {
+		if count == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	results := make(chan postHookResult)
+	running := 0
+
+	var firstErr error
+
+	for len(ready) > 0 || running > 0 {
+		for firstErr == nil && len(ready) > 0 && running < workers {
+			i := ready[0]
+			ready = ready[1:]
+			running++
+
+			go func(i int) {
+				var err error
+
+				defer func() {
+					if r := recover(); r != nil {
+						err = emperror.Recover(r)
+					}
+
+					results <- postHookResult{index: i, err: err}
+				}()
+
+				err = do(i)
+			}(i)
+		}
+
+		if running == 0 {
+			break
+		}
+
+		result := <-results
+		running--
+
+		finished(result.index, result.err)
+
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = result.err
+			}
+
+			continue
+		}
+
+		for _, j := range g.dependents[result.index] {
+			remaining[j]--
+			if remaining[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,172 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewPostHookGraphCycles(t *testing.T) {
+	tests := []struct {
+		name         string
+		names        []string
+		dependencies [][]string
+		valid        bool
+	}{
+		{
+			name:         "no dependencies",
+			names:        []string{"a", "b"},
+			dependencies: [][]string{nil, nil},
+			valid:        true,
+		},
+		{
+			name:         "missing dependencies are satisfied",
+			names:        []string{"a", "b"},
+			dependencies: [][]string{{"x"}, {"a", "a", "b"}},
+			valid:        true,
+		},
+		{
+			name:         "cycle",
+			names:        []string{"a", "b", "c"},
+			dependencies: [][]string{nil, {"a", "c"}, {"b"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newPostHookGraph(test.names, test.dependencies)
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if !test.valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPostHookGraphRun(t *testing.T) {
+	names := []string{"kubeconfig", "helm", "dashboard", "hpa", "monitoring"}
+	dependencies := [][]string{nil, {"kubeconfig"}, {"helm"}, {"helm"}, {"dashboard", "hpa"}}
+
+	graph, err := newPostHookGraph(names, dependencies)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var mu sync.Mutex
+	started := make(map[string]bool)
+	running, maxRunning := 0, 0
+
+	do := func(i int) error {
+		mu.Lock()
+		for _, dependency := range dependencies[i] {
+			if !started[dependency] {
+				t.Errorf("%s started before its dependency %s", names[i], dependency)
+			}
+		}
+
+		started[names[i]] = true
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil
+	}
+
+	var finished []string
+	err = graph.run(2, do, func(i int, err error) {
+		finished = append(finished, names[i])
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(finished) != len(names) || finished[0] != "kubeconfig" || finished[1] != "helm" || finished[4] != "monitoring" {
+		t.Errorf("unexpected order of posthooks: %v", finished)
+	}
+
+	if maxRunning != 2 {
+		t.Errorf("expected 2 posthooks running at once, got %d", maxRunning)
+	}
+}
+
+func TestPostHookGraphRunFailure(t *testing.T) {
+	names := []string{"helm", "dashboard", "hpa", "monitoring"}
+	dependencies := [][]string{nil, {"helm"}, {"helm"}, {"dashboard"}}
+
+	graph, err := newPostHookGraph(names, dependencies)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	failure := errors.New("dashboard failed")
+
+	var mu sync.Mutex
+	var started []string
+
+	do := func(i int) error {
+		mu.Lock()
+		started = append(started, names[i])
+		mu.Unlock()
+
+		if names[i] == "dashboard" {
+			return failure
+		}
+
+		return nil
+	}
+
+	var failed []string
+	err = graph.run(1, do, func(i int, err error) {
+		if err != nil {
+			failed = append(failed, names[i])
+		}
+	})
+	if err != failure {
+		t.Errorf("expected the first error to be returned, got %v", err)
+	}
+
+	if !reflect.DeepEqual(started, []string{"helm", "dashboard"}) {
+		t.Errorf("expected no posthooks to start after the failure, started %v", started)
+	}
+
+	if !reflect.DeepEqual(failed, []string{"dashboard"}) {
+		t.Errorf("unexpected failed posthooks: %v", failed)
+	}
+}
+
+func TestPostHookGraphRunPanic(t *testing.T) {
+	graph, err := newPostHookGraph([]string{"hpa"}, [][]string{nil})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = graph.run(1, func(i int) error { panic("hpa panicked") }, func(i int, err error) {})
+	if err == nil {
+		t.Error("expected the panic to be returned as an error")
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/acsk"
+	pkgAzure "github.com/banzaicloud/pipeline/pkg/cluster/aks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/dummy"
+	pkgEks "github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	pkgClusterGoogle "github.com/banzaicloud/pipeline/pkg/cluster/gke"
+)
+
+// GetClusterSpec returns the stored spec of a cluster in the shape of a create request.
+func GetClusterSpec(cluster CommonCluster) (*pkgCluster.CreateClusterRequest, error) {
+	distribution, err := GetDistribution(cluster.GetCloud())
+	if err != nil || distribution.GetSpec == nil {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("exporting the spec of %s clusters is not supported", cluster.GetCloud()),
+		)
+	}
+
+	properties, err := distribution.GetSpec(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkgCluster.CreateClusterRequest{
+		Name:       cluster.GetName(),
+		Location:   cluster.GetLocation(),
+		Cloud:      cluster.GetCloud(),
+		SecretId:   cluster.GetSecretId(),
+		Properties: properties,
+	}, nil
+}
+
+// NewUpdateRequestFromSpec turns the differences between a cluster and a desired spec into an update request.
+// Properties which cannot be changed on an existing cluster are rejected when they differ.
+func NewUpdateRequestFromSpec(cluster CommonCluster, spec *pkgCluster.CreateClusterRequest) (*pkgCluster.UpdateClusterRequest, error) {
+	if spec.Cloud != cluster.GetCloud() {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("cloud provider [%s] does not match the cluster's cloud provider [%s]", spec.Cloud, cluster.GetCloud()),
+		)
+	}
+
+	if spec.Location != "" && spec.Location != cluster.GetLocation() {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("location of an existing cluster cannot be changed from [%s] to [%s]", cluster.GetLocation(), spec.Location),
+		)
+	}
+
+	if spec.SecretId != "" && spec.SecretId != cluster.GetSecretId() {
+		return nil, pkgCluster.NewValidationError("secret of an existing cluster cannot be changed")
+	}
+
+	if spec.Properties == nil {
+		return nil, pkgCluster.NewValidationError("properties field is empty")
+	}
+
+	distribution, err := GetDistribution(cluster.GetCloud())
+	if err != nil || distribution.UpdateFromSpec == nil {
+		return nil, pkgCluster.NewValidationError(
+			fmt.Sprintf("applying a spec to %s clusters is not supported", cluster.GetCloud()),
+		)
+	}
+
+	request := &pkgCluster.UpdateClusterRequest{
+		Cloud: spec.Cloud,
+	}
+
+	if err := distribution.UpdateFromSpec(spec.Properties, request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func getEKSSpec(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error) {
+	c, ok := cluster.(*EKSCluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	nodePools := make(map[string]*pkgEks.NodePool, len(c.modelCluster.EKS.NodePools))
+	for _, np := range c.modelCluster.EKS.NodePools {
+		nodePools[np.Name] = &pkgEks.NodePool{
+			InstanceType: np.NodeInstanceType,
+			SpotPrice:    np.NodeSpotPrice,
+			Autoscaling:  np.Autoscaling,
+			MinCount:     np.NodeMinCount,
+			MaxCount:     np.NodeMaxCount,
+			Count:        np.Count,
+			Image:        np.NodeImage,
+		}
+	}
+
+	return &pkgCluster.CreateClusterProperties{
+		CreateClusterEKS: &pkgEks.CreateClusterEKS{
+			Version:   c.modelCluster.EKS.Version,
+			NodePools: nodePools,
+		},
+	}, nil
+}
+
+func updateEKSFromSpec(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error {
+	if properties.CreateClusterEKS == nil {
+		return pkgCluster.NewValidationError("eks properties are missing")
+	}
+
+	request.EKS = &pkgEks.UpdateClusterAmazonEKS{
+		NodePools: properties.CreateClusterEKS.NodePools,
+	}
+
+	return nil
+}
+
+func getAKSSpec(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error) {
+	c, ok := cluster.(*AKSCluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	nodePools := make(map[string]*pkgAzure.NodePoolCreate, len(c.modelCluster.AKS.NodePools))
+	for _, np := range c.modelCluster.AKS.NodePools {
+		if np == nil {
+			continue
+		}
+
+		nodePools[np.Name] = &pkgAzure.NodePoolCreate{
+			Autoscaling:      np.Autoscaling,
+			MinCount:         np.NodeMinCount,
+			MaxCount:         np.NodeMaxCount,
+			Count:            np.Count,
+			NodeInstanceType: np.NodeInstanceType,
+		}
+	}
+
+	return &pkgCluster.CreateClusterProperties{
+		CreateClusterAKS: &pkgAzure.CreateClusterAKS{
+			ResourceGroup:     c.modelCluster.AKS.ResourceGroup,
+			KubernetesVersion: c.modelCluster.AKS.KubernetesVersion,
+			NodePools:         nodePools,
+		},
+	}, nil
+}
+
+func updateAKSFromSpec(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error {
+	if properties.CreateClusterAKS == nil {
+		return pkgCluster.NewValidationError("aks properties are missing")
+	}
+
+	nodePools := make(map[string]*pkgAzure.NodePoolUpdate, len(properties.CreateClusterAKS.NodePools))
+	for name, np := range properties.CreateClusterAKS.NodePools {
+		if np == nil {
+			continue
+		}
+
+		nodePools[name] = &pkgAzure.NodePoolUpdate{
+			Autoscaling: np.Autoscaling,
+			MinCount:    np.MinCount,
+			MaxCount:    np.MaxCount,
+			Count:       np.Count,
+		}
+	}
+
+	request.AKS = &pkgAzure.UpdateClusterAzure{
+		NodePools: nodePools,
+	}
+
+	return nil
+}
+
+func getGKESpec(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error) {
+	c, ok := cluster.(*GKECluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	nodePools := make(map[string]*pkgClusterGoogle.NodePool, len(c.model.NodePools))
+	for _, np := range c.model.NodePools {
+		nodePools[np.Name] = &pkgClusterGoogle.NodePool{
+			Autoscaling:      np.Autoscaling,
+			MinCount:         np.NodeMinCount,
+			MaxCount:         np.NodeMaxCount,
+			Count:            np.NodeCount,
+			NodeInstanceType: np.NodeInstanceType,
+		}
+	}
+
+	return &pkgCluster.CreateClusterProperties{
+		CreateClusterGKE: &pkgClusterGoogle.CreateClusterGKE{
+			NodeVersion: c.model.NodeVersion,
+			NodePools:   nodePools,
+			Master: &pkgClusterGoogle.Master{
+				Version: c.model.MasterVersion,
+			},
+			ProjectId: c.model.ProjectId,
+		},
+	}, nil
+}
+
+func updateGKEFromSpec(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error {
+	if properties.CreateClusterGKE == nil {
+		return pkgCluster.NewValidationError("gke properties are missing")
+	}
+
+	request.GKE = &pkgClusterGoogle.UpdateClusterGoogle{
+		NodeVersion: properties.CreateClusterGKE.NodeVersion,
+		NodePools:   properties.CreateClusterGKE.NodePools,
+		Master:      properties.CreateClusterGKE.Master,
+	}
+
+	return nil
+}
+
+func getACSKSpec(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error) {
+	c, ok := cluster.(*ACSKCluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	nodePools := make(acsk.NodePools, len(c.modelCluster.ACSK.NodePools))
+	for _, np := range c.modelCluster.ACSK.NodePools {
+		nodePools[np.Name] = &acsk.NodePool{
+			InstanceType:       np.InstanceType,
+			SystemDiskCategory: np.SystemDiskCategory,
+			SystemDiskSize:     np.SystemDiskSize,
+			Count:              np.Count,
+		}
+	}
+
+	return &pkgCluster.CreateClusterProperties{
+		CreateClusterACSK: &acsk.CreateClusterACSK{
+			RegionID:                 c.modelCluster.ACSK.RegionID,
+			ZoneID:                   c.modelCluster.ACSK.ZoneID,
+			MasterInstanceType:       c.modelCluster.ACSK.MasterInstanceType,
+			MasterSystemDiskCategory: c.modelCluster.ACSK.MasterSystemDiskCategory,
+			MasterSystemDiskSize:     c.modelCluster.ACSK.MasterSystemDiskSize,
+			NodePools:                nodePools,
+		},
+	}, nil
+}
+
+func updateACSKFromSpec(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error {
+	if properties.CreateClusterACSK == nil {
+		return pkgCluster.NewValidationError("acsk properties are missing")
+	}
+
+	request.ACSK = &acsk.UpdateClusterACSK{
+		NodePools: properties.CreateClusterACSK.NodePools,
+	}
+
+	return nil
+}
+
+func getOKESpec(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error) {
+	c, ok := cluster.(*OKECluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	return &pkgCluster.CreateClusterProperties{
+		CreateClusterOKE: c.modelCluster.OKE.GetClusterRequestFromModel(),
+	}, nil
+}
+
+func updateOKEFromSpec(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error {
+	if properties.CreateClusterOKE == nil {
+		return pkgCluster.NewValidationError("oke properties are missing")
+	}
+
+	request.OKE = properties.CreateClusterOKE
+
+	return nil
+}
+
+func getDummySpec(cluster CommonCluster) (*pkgCluster.CreateClusterProperties, error) {
+	c, ok := cluster.(*DummyCluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	nodePools, err := c.getNodePools()
+	if err != nil {
+		return nil, err
+	}
+
+	simulation, err := c.getSimulation()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkgCluster.CreateClusterProperties{
+		CreateClusterDummy: &dummy.CreateClusterDummy{
+			Node: &dummy.Node{
+				KubernetesVersion: c.modelCluster.Dummy.KubernetesVersion,
+				Count:             c.modelCluster.Dummy.NodeCount,
+			},
+			NodePools:  nodePools,
+			Simulation: simulation,
+		},
+	}, nil
+}
+
+func updateDummyFromSpec(properties *pkgCluster.CreateClusterProperties, request *pkgCluster.UpdateClusterRequest) error {
+	if properties.CreateClusterDummy == nil {
+		return pkgCluster.NewValidationError("dummy properties are missing")
+	}
+
+	request.Dummy = &dummy.UpdateClusterDummy{
+		Node:       properties.CreateClusterDummy.Node,
+		NodePools:  properties.CreateClusterDummy.NodePools,
+		Simulation: properties.CreateClusterDummy.Simulation,
+	}
+
+	return nil
+}
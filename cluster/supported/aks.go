@@ -20,6 +20,12 @@ import (
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
 
+func init() {
+	RegisterCloudInfoProvider(pkgCluster.Azure, func(fields BaseFields) CloudInfoProvider {
+		return &AzureInfo{BaseFields: fields}
+	})
+}
+
 // AzureInfo describes AKS with supported info
 type AzureInfo struct {
 	BaseFields
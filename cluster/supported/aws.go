@@ -21,6 +21,12 @@ import (
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
 
+func init() {
+	RegisterCloudInfoProvider(pkgCluster.Amazon, func(fields BaseFields) CloudInfoProvider {
+		return &AmazonInfo{BaseFields: fields}
+	})
+}
+
 // AmazonInfo describes AWS with supported info
 type AmazonInfo struct {
 	BaseFields
@@ -15,6 +15,9 @@
 package supported
 
 import (
+	"fmt"
+	"sync"
+
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
@@ -36,46 +39,43 @@ type BaseFields struct {
 	SecretId string
 }
 
+// CloudInfoProviderFactory creates the CloudInfoProvider of a cloud type
+type CloudInfoProviderFactory func(fields BaseFields) CloudInfoProvider
+
+var (
+	cloudInfoProvidersMu sync.RWMutex
+	cloudInfoProviders   = make(map[string]CloudInfoProviderFactory)
+)
+
+// RegisterCloudInfoProvider makes the cloud info of a cloud type available.
+// It panics if a provider is registered twice for the same cloud type.
+func RegisterCloudInfoProvider(cloudType string, factory CloudInfoProviderFactory) {
+	cloudInfoProvidersMu.Lock()
+	defer cloudInfoProvidersMu.Unlock()
+
+	if _, ok := cloudInfoProviders[cloudType]; ok {
+		panic(fmt.Sprintf("supported: cloud info provider of %s is registered twice", cloudType))
+	}
+
+	cloudInfoProviders[cloudType] = factory
+}
+
 // GetCloudInfoModel creates CloudInfoProvider
 func GetCloudInfoModel(cloudType string, r *pkgCluster.CloudInfoRequest) (CloudInfoProvider, error) {
 	log.Infof("Cloud type: %s", cloudType)
-	switch cloudType {
-
-	case pkgCluster.Amazon:
-		return &AmazonInfo{
-			BaseFields: BaseFields{
-				OrgId:    r.OrganizationId,
-				SecretId: r.SecretId,
-			},
-		}, nil
-
-	case pkgCluster.Google:
-		return &GoogleInfo{
-			BaseFields: BaseFields{
-				OrgId:    r.OrganizationId,
-				SecretId: r.SecretId,
-			},
-		}, nil
-
-	case pkgCluster.Azure:
-		return &AzureInfo{
-			BaseFields: BaseFields{
-				OrgId:    r.OrganizationId,
-				SecretId: r.SecretId,
-			},
-		}, nil
-
-	case pkgCluster.Oracle:
-		return &OracleInfo{
-			BaseFields: BaseFields{
-				OrgId:    r.OrganizationId,
-				SecretId: r.SecretId,
-			},
-		}, nil
-
-	default:
+
+	cloudInfoProvidersMu.RLock()
+	factory, ok := cloudInfoProviders[cloudType]
+	cloudInfoProvidersMu.RUnlock()
+
+	if !ok {
 		return nil, pkgErrors.ErrorNotSupportedCloudType
 	}
+
+	return factory(BaseFields{
+		OrgId:    r.OrganizationId,
+		SecretId: r.SecretId,
+	}), nil
 }
 
 // ProcessFilter returns the proper supported fields, the CloudInfoRequest decide which
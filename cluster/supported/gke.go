@@ -20,6 +20,12 @@ import (
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 )
 
+func init() {
+	RegisterCloudInfoProvider(pkgCluster.Google, func(fields BaseFields) CloudInfoProvider {
+		return &GoogleInfo{BaseFields: fields}
+	})
+}
+
 // GoogleInfo describes GKE with supported info
 type GoogleInfo struct {
 	BaseFields
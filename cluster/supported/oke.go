@@ -27,6 +27,12 @@ const (
 	RegexpOKEName = `^[A-z0-9-_]{1,255}$`
 )
 
+func init() {
+	RegisterCloudInfoProvider(pkgCluster.Oracle, func(fields BaseFields) CloudInfoProvider {
+		return &OracleInfo{BaseFields: fields}
+	})
+}
+
 // OracleInfo describes OKE with supported info
 type OracleInfo struct {
 	BaseFields
@@ -144,17 +150,17 @@ func (oi *OracleInfo) GetKubernetesVersion(filter *pkgCluster.KubernetesFilter)
 		return nil, pkgErrors.ErrorRequiredSecretId
 	}
 
-	oci, err := oi.GetOCI(oi.BaseFields.OrgId, oi.BaseFields.SecretId)
-	if err != nil {
-		return nil, err
-	}
-
 	if filter == nil || len(filter.Location) == 0 {
+		oci, err := oi.GetOCI(oi.BaseFields.OrgId, oi.BaseFields.SecretId)
+		if err != nil {
+			return nil, err
+		}
+
 		return oci.GetSupportedK8SVersions()
 	}
 
 	versionsByRegion := make(map[string][]string, 0)
-	versions, err := oci.GetSupportedK8SVersionsInARegion(filter.Location)
+	versions, err := cluster.GetOKEKubernetesVersions(oi.BaseFields.OrgId, oi.BaseFields.SecretId, filter.Location)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,312 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// planClusterUpdate describes what the provider would do when updating the cluster with the given request.
+// The request is expected to be defaulted and validated already, the provider is never called.
+func planClusterUpdate(cluster CommonCluster, r *pkgCluster.UpdateClusterRequest) (*pkgCluster.UpdatePlanResponse, error) {
+	distribution, err := GetDistribution(cluster.GetCloud())
+	if err != nil {
+		return nil, err
+	}
+
+	if distribution.PlanUpdate == nil {
+		return &pkgCluster.UpdatePlanResponse{
+			NodePools: diffNodePools(nil, nil),
+			Actions:   []string{fmt.Sprintf("update %s cluster at the provider", cluster.GetCloud())},
+		}, nil
+	}
+
+	return distribution.PlanUpdate(cluster, r)
+}
+
+func planEKSUpdate(cluster CommonCluster, r *pkgCluster.UpdateClusterRequest) (*pkgCluster.UpdatePlanResponse, error) {
+	c, ok := cluster.(*EKSCluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	current := make(map[string]pkgCluster.NodePoolPlan)
+	for _, np := range c.modelCluster.EKS.NodePools {
+		current[np.Name] = pkgCluster.NodePoolPlan{
+			Name:         np.Name,
+			InstanceType: np.NodeInstanceType,
+			NodePoolSize: pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.NodeMinCount,
+				MaxCount:    np.NodeMaxCount,
+				Count:       np.Count,
+			},
+		}
+	}
+
+	requested := make(map[string]pkgCluster.NodePoolPlan)
+	if r.EKS != nil {
+		for name, np := range r.EKS.NodePools {
+			requested[name] = pkgCluster.NodePoolPlan{
+				Name:         name,
+				InstanceType: np.InstanceType,
+				NodePoolSize: pkgCluster.NodePoolSize{
+					Autoscaling: np.Autoscaling,
+					MinCount:    np.MinCount,
+					MaxCount:    np.MaxCount,
+					Count:       np.Count,
+				},
+			}
+		}
+	}
+
+	diff := diffNodePools(current, requested)
+
+	actions := []string{}
+	for _, np := range diff.Removed {
+		actions = append(actions, fmt.Sprintf("delete CloudFormation stack of node pool [%s]", np.Name))
+	}
+	for _, np := range diff.Added {
+		actions = append(actions, fmt.Sprintf("create CloudFormation stack for node pool [%s] with %d %s instance(s)", np.Name, np.Count, np.InstanceType))
+	}
+	for _, np := range diff.Resized {
+		actions = append(actions, fmt.Sprintf("update CloudFormation stack of node pool [%s] to %s", np.Name, describeNodePoolSize(np.To)))
+	}
+	for _, np := range diff.InstanceTypeChanged {
+		actions = append(actions, fmt.Sprintf("keep instance type %s of node pool [%s], instance type of existing node pools cannot be changed", np.From, np.Name))
+	}
+
+	return &pkgCluster.UpdatePlanResponse{
+		NodePools: diff,
+		Actions:   actions,
+	}, nil
+}
+
+func planAKSUpdate(cluster CommonCluster, r *pkgCluster.UpdateClusterRequest) (*pkgCluster.UpdatePlanResponse, error) {
+	c, ok := cluster.(*AKSCluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	actions := []string{}
+
+	existing := make(map[string]pkgCluster.NodePoolPlan)
+	for _, np := range c.modelCluster.AKS.NodePools {
+		if np == nil {
+			continue
+		}
+
+		existing[np.Name] = pkgCluster.NodePoolPlan{
+			Name:         np.Name,
+			InstanceType: np.NodeInstanceType,
+			NodePoolSize: pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.NodeMinCount,
+				MaxCount:    np.NodeMaxCount,
+				Count:       np.Count,
+			},
+		}
+	}
+
+	// Azure does not support adding and deleting node pools: unknown node pools are skipped
+	// and node pools missing from the request are left untouched
+	current := make(map[string]pkgCluster.NodePoolPlan)
+	requested := make(map[string]pkgCluster.NodePoolPlan)
+	if r.AKS != nil {
+		names := make([]string, 0, len(r.AKS.NodePools))
+		for name := range r.AKS.NodePools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			np := r.AKS.NodePools[name]
+			if np == nil {
+				continue
+			}
+
+			currentNodePool, ok := existing[name]
+			if !ok {
+				actions = append(actions, fmt.Sprintf("skip node pool [%s], node pools cannot be added to AKS clusters", name))
+				continue
+			}
+
+			current[name] = currentNodePool
+			requested[name] = pkgCluster.NodePoolPlan{
+				Name:         name,
+				InstanceType: currentNodePool.InstanceType,
+				NodePoolSize: pkgCluster.NodePoolSize{
+					Autoscaling: np.Autoscaling,
+					MinCount:    np.MinCount,
+					MaxCount:    np.MaxCount,
+					Count:       np.Count,
+				},
+			}
+		}
+	}
+
+	diff := diffNodePools(current, requested)
+
+	for _, np := range diff.Resized {
+		actions = append(actions, fmt.Sprintf("update agent pool [%s] to %s", np.Name, describeNodePoolSize(np.To)))
+	}
+
+	return &pkgCluster.UpdatePlanResponse{
+		NodePools: diff,
+		Actions:   actions,
+	}, nil
+}
+
+func planGKEUpdate(cluster CommonCluster, r *pkgCluster.UpdateClusterRequest) (*pkgCluster.UpdatePlanResponse, error) {
+	c, ok := cluster.(*GKECluster)
+	if !ok {
+		return nil, ErrInvalidClusterInstance
+	}
+
+	actions := []string{}
+
+	current := make(map[string]pkgCluster.NodePoolPlan)
+	for _, np := range c.model.NodePools {
+		current[np.Name] = pkgCluster.NodePoolPlan{
+			Name:         np.Name,
+			InstanceType: np.NodeInstanceType,
+			NodePoolSize: pkgCluster.NodePoolSize{
+				Autoscaling: np.Autoscaling,
+				MinCount:    np.NodeMinCount,
+				MaxCount:    np.NodeMaxCount,
+				Count:       np.NodeCount,
+			},
+		}
+	}
+
+	requested := make(map[string]pkgCluster.NodePoolPlan)
+	if r.GKE != nil {
+		if r.GKE.Master != nil && r.GKE.Master.Version != "" && r.GKE.Master.Version != c.model.MasterVersion {
+			actions = append(actions, fmt.Sprintf("upgrade master from version %s to %s", c.model.MasterVersion, r.GKE.Master.Version))
+		}
+
+		for name, np := range r.GKE.NodePools {
+			requested[name] = pkgCluster.NodePoolPlan{
+				Name:         name,
+				InstanceType: np.NodeInstanceType,
+				NodePoolSize: pkgCluster.NodePoolSize{
+					Autoscaling: np.Autoscaling,
+					MinCount:    np.MinCount,
+					MaxCount:    np.MaxCount,
+					Count:       np.Count,
+				},
+			}
+		}
+	}
+
+	diff := diffNodePools(current, requested)
+
+	for _, np := range diff.Removed {
+		actions = append(actions, fmt.Sprintf("delete node pool [%s]", np.Name))
+	}
+	for _, np := range diff.Added {
+		actions = append(actions, fmt.Sprintf("create node pool [%s] with %d %s instance(s)", np.Name, np.Count, np.InstanceType))
+	}
+	if r.GKE != nil && r.GKE.NodeVersion != "" && r.GKE.NodeVersion != c.model.NodeVersion {
+		for _, name := range sortedNames(current) {
+			if _, ok := requested[name]; ok {
+				actions = append(actions, fmt.Sprintf("upgrade node pool [%s] from version %s to %s", name, c.model.NodeVersion, r.GKE.NodeVersion))
+			}
+		}
+	}
+	for _, np := range diff.Resized {
+		if np.From.Autoscaling != np.To.Autoscaling || np.From.MinCount != np.To.MinCount || np.From.MaxCount != np.To.MaxCount {
+			actions = append(actions, fmt.Sprintf("set autoscaling of node pool [%s] to %s", np.Name, describeNodePoolSize(np.To)))
+		}
+		if np.From.Count != np.To.Count {
+			actions = append(actions, fmt.Sprintf("set size of node pool [%s] to %d", np.Name, np.To.Count))
+		}
+	}
+	for _, np := range diff.InstanceTypeChanged {
+		actions = append(actions, fmt.Sprintf("keep instance type %s of node pool [%s], instance type of existing node pools cannot be changed", np.From, np.Name))
+	}
+
+	return &pkgCluster.UpdatePlanResponse{
+		NodePools: diff,
+		Actions:   actions,
+	}, nil
+}
+
+// diffNodePools compares the current and the requested node pools of a cluster.
+// The result is sorted by node pool name.
+func diffNodePools(current, requested map[string]pkgCluster.NodePoolPlan) pkgCluster.NodePoolsDiff {
+	diff := pkgCluster.NodePoolsDiff{
+		Added:               []pkgCluster.NodePoolPlan{},
+		Removed:             []pkgCluster.NodePoolPlan{},
+		Resized:             []pkgCluster.NodePoolResize{},
+		InstanceTypeChanged: []pkgCluster.NodePoolInstanceTypeChange{},
+	}
+
+	for _, name := range sortedNames(current) {
+		if _, ok := requested[name]; !ok {
+			diff.Removed = append(diff.Removed, current[name])
+		}
+	}
+
+	for _, name := range sortedNames(requested) {
+		np := requested[name]
+
+		currentNodePool, ok := current[name]
+		if !ok {
+			diff.Added = append(diff.Added, np)
+			continue
+		}
+
+		if currentNodePool.NodePoolSize != np.NodePoolSize {
+			diff.Resized = append(diff.Resized, pkgCluster.NodePoolResize{
+				Name: name,
+				From: currentNodePool.NodePoolSize,
+				To:   np.NodePoolSize,
+			})
+		}
+
+		if np.InstanceType != "" && currentNodePool.InstanceType != np.InstanceType {
+			diff.InstanceTypeChanged = append(diff.InstanceTypeChanged, pkgCluster.NodePoolInstanceTypeChange{
+				Name: name,
+				From: currentNodePool.InstanceType,
+				To:   np.InstanceType,
+			})
+		}
+	}
+
+	return diff
+}
+
+func describeNodePoolSize(size pkgCluster.NodePoolSize) string {
+	if size.Autoscaling {
+		return fmt.Sprintf("autoscaling between %d and %d node(s)", size.MinCount, size.MaxCount)
+	}
+
+	return fmt.Sprintf("%d node(s)", size.Count)
+}
+
+func sortedNames(nodePools map[string]pkgCluster.NodePoolPlan) []string {
+	names := make([]string, 0, len(nodePools))
+	for name := range nodePools {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,115 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestDiffNodePools(t *testing.T) {
+	current := map[string]pkgCluster.NodePoolPlan{
+		"kept": {
+			Name:         "kept",
+			InstanceType: "m4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+		"resized": {
+			Name:         "resized",
+			InstanceType: "m4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+		"retyped": {
+			Name:         "retyped",
+			InstanceType: "m4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+		"removed": {
+			Name:         "removed",
+			InstanceType: "m4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+	}
+
+	requested := map[string]pkgCluster.NodePoolPlan{
+		"kept": {
+			Name:         "kept",
+			InstanceType: "m4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+		"resized": {
+			Name:         "resized",
+			InstanceType: "m4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 1},
+		},
+		"retyped": {
+			Name:         "retyped",
+			InstanceType: "m4.xlarge",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+		"added": {
+			Name:         "added",
+			InstanceType: "c4.large",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 2},
+		},
+	}
+
+	expected := pkgCluster.NodePoolsDiff{
+		Added: []pkgCluster.NodePoolPlan{
+			requested["added"],
+		},
+		Removed: []pkgCluster.NodePoolPlan{
+			current["removed"],
+		},
+		Resized: []pkgCluster.NodePoolResize{
+			{
+				Name: "resized",
+				From: pkgCluster.NodePoolSize{Count: 1},
+				To:   pkgCluster.NodePoolSize{Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 1},
+			},
+		},
+		InstanceTypeChanged: []pkgCluster.NodePoolInstanceTypeChange{
+			{
+				Name: "retyped",
+				From: "m4.large",
+				To:   "m4.xlarge",
+			},
+		},
+	}
+
+	diff := diffNodePools(current, requested)
+
+	if !reflect.DeepEqual(expected, diff) {
+		t.Errorf("expected: %v, got: %v", expected, diff)
+	}
+}
+
+func TestDiffNodePools_NoChanges(t *testing.T) {
+	nodePools := map[string]pkgCluster.NodePoolPlan{
+		"pool1": {
+			Name:         "pool1",
+			InstanceType: "n1-standard-1",
+			NodePoolSize: pkgCluster.NodePoolSize{Count: 1},
+		},
+	}
+
+	diff := diffNodePools(nodePools, nodePools)
+
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 || len(diff.Resized) != 0 || len(diff.InstanceTypeChanged) != 0 {
+		t.Errorf("expected no changes, got: %v", diff)
+	}
+}
@@ -35,7 +35,6 @@ import (
 	"github.com/banzaicloud/pipeline/dns"
 	arkSync "github.com/banzaicloud/pipeline/internal/ark/sync"
 	"github.com/banzaicloud/pipeline/internal/audit"
-	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/dashboard"
 	"github.com/banzaicloud/pipeline/internal/monitor"
 	ginternal "github.com/banzaicloud/pipeline/internal/platform/gin"
@@ -125,9 +124,9 @@ func main() {
 
 	clusterEventBus := evbus.New()
 	clusterEvents := cluster.NewClusterEvents(clusterEventBus)
-	clusters := intCluster.NewClusters(db)
 	secretValidator := providers.NewSecretValidator(secret.Store)
-	clusterManager := cluster.NewManager(clusters, secretValidator, clusterEvents, log, errorHandler)
+	prices := config.PriceCatalog()
+	clusterManager := cluster.NewManager(cluster.NewRepositories(db), secretValidator, clusterEvents, prices, log, errorHandler)
 
 	if viper.GetBool(config.MonitorEnabled) {
 		client, err := k8sclient.NewInClusterClient()
@@ -150,7 +149,7 @@ func main() {
 		}
 	}
 
-	clusterAPI := api.NewClusterAPI(clusterManager, log, errorHandler)
+	clusterAPI := api.NewClusterAPI(clusterManager, prices, log, errorHandler)
 
 	//Initialise Gin router
 	router := gin.New()
@@ -216,14 +215,54 @@ func main() {
 			orgs.POST("/:orgid/clusters", clusterAPI.CreateClusterRequest)
 			//v1.GET("/status", api.Status)
 			orgs.GET("/:orgid/clusters", clusterAPI.GetClusters)
-			orgs.GET("/:orgid/clusters/:id", api.GetClusterStatus)
+			// the estimate route is dispatched by the handler, because it would conflict with the :id wildcard
+			orgs.POST("/:orgid/clusters/:id", clusterAPI.EstimateClusterCost)
+			// by-name routes cannot live under /clusters, because they would conflict with the :id wildcard
+			orgs.PUT("/:orgid/clusters-by-name/:name", clusterAPI.ApplyCluster)
+			orgs.GET("/:orgid/clusters/:id", clusterAPI.GetClusterStatus)
+			orgs.GET("/:orgid/clusters/:id/spec", clusterAPI.GetClusterSpec)
 			orgs.GET("/:orgid/clusters/:id/details", api.GetClusterDetails)
 			orgs.GET("/:orgid/clusters/:id/pods", api.GetPodDetails)
 			orgs.PUT("/:orgid/clusters/:id", clusterAPI.UpdateCluster)
-			orgs.PUT("/:orgid/clusters/:id/posthooks", api.ReRunPostHooks)
-			orgs.POST("/:orgid/clusters/:id/secrets", api.InstallSecretsToCluster)
-			orgs.POST("/:orgid/clusters/:id/secrets/:secretName", api.InstallSecretToCluster)
-			orgs.PATCH("/:orgid/clusters/:id/secrets/:secretName", api.MergeSecretInCluster)
+			orgs.POST("/:orgid/clusters/:id/upgrade", clusterAPI.UpgradeCluster)
+			orgs.POST("/:orgid/clusters/:id/clone", clusterAPI.CloneCluster)
+			orgs.GET("/:orgid/clusters/:id/statushistory", clusterAPI.GetClusterStatusHistory)
+			orgs.GET("/:orgid/clusters/:id/drift", clusterAPI.GetClusterDrift)
+			orgs.POST("/:orgid/clusters/:id/drift", clusterAPI.CheckClusterDrift)
+			orgs.POST("/:orgid/clusters/:id/drift/correct", clusterAPI.CorrectClusterDrift)
+			orgs.GET("/:orgid/clusters/:id/posthooks", clusterAPI.GetPostHooks)
+			orgs.PUT("/:orgid/clusters/:id/posthooks", clusterAPI.ReRunPostHooks)
+			orgs.GET("/:orgid/clusters/:id/features", clusterAPI.GetClusterFeatures)
+			orgs.PUT("/:orgid/clusters/:id/features/:feature", clusterAPI.SetClusterFeature)
+			orgs.PUT("/:orgid/clusters/:id/expiration", clusterAPI.SetClusterExpiration)
+			orgs.DELETE("/:orgid/clusters/:id/expiration", clusterAPI.DeleteClusterExpiration)
+			orgs.GET("/:orgid/clusters/:id/nodepools", clusterAPI.ListNodePools)
+			orgs.POST("/:orgid/clusters/:id/nodepools", clusterAPI.CreateNodePool)
+			orgs.GET("/:orgid/clusters/:id/nodepools/:name", clusterAPI.GetNodePool)
+			orgs.PUT("/:orgid/clusters/:id/nodepools/:name", clusterAPI.UpdateNodePool)
+			orgs.DELETE("/:orgid/clusters/:id/nodepools/:name", clusterAPI.DeleteNodePool)
+			orgs.GET("/:orgid/clusters/:id/nodepools/:name/schedules", clusterAPI.ListNodePoolSchedules)
+			orgs.POST("/:orgid/clusters/:id/nodepools/:name/schedules", clusterAPI.CreateNodePoolSchedule)
+			orgs.DELETE("/:orgid/clusters/:id/nodepools/:name/schedules/:scheduleid", clusterAPI.DeleteNodePoolSchedule)
+			orgs.GET("/:orgid/clusters/:id/operations", clusterAPI.ListOperations)
+			orgs.GET("/:orgid/clusters/:id/operations/:opid", clusterAPI.GetOperation)
+			orgs.GET("/:orgid/clusters/:id/maintenancewindows", clusterAPI.ListClusterMaintenanceWindows)
+			orgs.POST("/:orgid/clusters/:id/maintenancewindows", clusterAPI.CreateClusterMaintenanceWindow)
+			orgs.DELETE("/:orgid/clusters/:id/maintenancewindows/:windowid", clusterAPI.DeleteClusterMaintenanceWindow)
+			orgs.GET("/:orgid/clusters/:id/queuedoperations", clusterAPI.ListQueuedOperations)
+			orgs.DELETE("/:orgid/clusters/:id/queuedoperations/:opid", clusterAPI.CancelQueuedOperation)
+			orgs.GET("/:orgid/maintenancewindows", clusterAPI.ListOrganizationMaintenanceWindows)
+			orgs.POST("/:orgid/maintenancewindows", clusterAPI.CreateOrganizationMaintenanceWindow)
+			orgs.DELETE("/:orgid/maintenancewindows/:windowid", clusterAPI.DeleteOrganizationMaintenanceWindow)
+			orgs.GET("/:orgid/quota", clusterAPI.GetQuota)
+			orgs.GET("/:orgid/posthooks", clusterAPI.ListCustomPostHooks)
+			orgs.POST("/:orgid/posthooks", clusterAPI.CreateCustomPostHook)
+			orgs.GET("/:orgid/posthooks/:name", clusterAPI.GetCustomPostHook)
+			orgs.PUT("/:orgid/posthooks/:name", clusterAPI.UpdateCustomPostHook)
+			orgs.DELETE("/:orgid/posthooks/:name", clusterAPI.DeleteCustomPostHook)
+			orgs.POST("/:orgid/clusters/:id/secrets", clusterAPI.InstallSecretsToCluster)
+			orgs.POST("/:orgid/clusters/:id/secrets/:secretName", clusterAPI.InstallSecretToCluster)
+			orgs.PATCH("/:orgid/clusters/:id/secrets/:secretName", clusterAPI.MergeSecretInCluster)
 			orgs.Any("/:orgid/clusters/:id/proxy/*path", api.ProxyToCluster)
 			orgs.DELETE("/:orgid/clusters/:id", clusterAPI.DeleteCluster)
 			orgs.HEAD("/:orgid/clusters/:id", api.ClusterHEAD)
@@ -280,6 +319,14 @@ func main() {
 			orgs.PUT("/:orgid/secrets/:id", api.UpdateSecrets)
 			orgs.DELETE("/:orgid/secrets/:id", api.DeleteSecrets)
 			orgs.GET("/:orgid/secrets/:id/validate", api.ValidateSecret)
+			orgs.GET("/:orgid/secrets/:id/versions", api.ListSecretVersions)
+			orgs.GET("/:orgid/secrets/:id/versions/:version", api.GetSecretVersion)
+			orgs.POST("/:orgid/secrets/:id/rollback", api.RollbackSecret)
+			orgs.GET("/:orgid/secrets/:id/rotation", clusterAPI.GetSecretRotationPolicy)
+			orgs.PUT("/:orgid/secrets/:id/rotation", clusterAPI.SetSecretRotationPolicy)
+			orgs.DELETE("/:orgid/secrets/:id/rotation", clusterAPI.DeleteSecretRotationPolicy)
+			orgs.POST("/:orgid/secrets/:id/rotate", clusterAPI.RotateSecret)
+			orgs.GET("/:orgid/secrets/:id/rotations", clusterAPI.ListSecretRotations)
 			orgs.GET("/:orgid/users", api.GetUsers)
 			orgs.GET("/:orgid/users/:id", api.GetUsers)
 			orgs.POST("/:orgid/users/:id", api.AddUser)
@@ -314,6 +361,15 @@ func main() {
 		v1.GET("/allowed/secrets", api.ListAllowedSecretTypes)
 		v1.GET("/allowed/secrets/:type", api.ListAllowedSecretTypes)
 
+		admin := v1.Group("/admin")
+		{
+			admin.Use(auth.RequireAdmin)
+
+			admin.GET("/orgs/:orgid/quota", clusterAPI.GetOrganizationQuota)
+			admin.PUT("/orgs/:orgid/quota", clusterAPI.SetOrganizationQuota)
+			admin.DELETE("/orgs/:orgid/quota", clusterAPI.DeleteOrganizationQuota)
+		}
+
 		backups.AddRoutes(orgs.Group("/:orgid/clusters/:id/backups"))
 		backupservice.AddRoutes(orgs.Group("/:orgid/clusters/:id/backupservice"))
 		restores.AddRoutes(orgs.Group("/:orgid/clusters/:id/restores"))
@@ -338,6 +394,32 @@ func main() {
 		)
 	}
 
+	if viper.GetBool(config.ClusterReaperEnabled) {
+		clusterManager.StartClusterReaper(
+			viper.GetDuration(config.ClusterReaperInterval),
+			viper.GetDuration(config.ClusterExpirationWarningPeriod),
+		)
+	}
+
+	if viper.GetBool(config.ClusterDriftEnabled) {
+		clusterManager.StartDriftReconciler(
+			viper.GetDuration(config.ClusterDriftInterval),
+			viper.GetBool(config.ClusterDriftAutoCorrect),
+		)
+	}
+
+	if viper.GetBool(config.ClusterMaintenanceEnabled) {
+		clusterManager.StartMaintenanceScheduler(viper.GetDuration(config.ClusterMaintenanceInterval))
+	}
+
+	if viper.GetBool(config.ClusterNodePoolScalerEnabled) {
+		clusterManager.StartNodePoolScaler(viper.GetDuration(config.ClusterNodePoolScalerInterval))
+	}
+
+	if viper.GetBool(config.ClusterSecretRotatorEnabled) {
+		clusterManager.StartSecretRotator(viper.GetDuration(config.ClusterSecretRotatorInterval))
+	}
+
 	router.GET(basePath+"/api", api.MetaHandler(router, basePath+"/api"))
 
 	notify.SlackNotify("API is already running")
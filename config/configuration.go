@@ -105,11 +105,46 @@ const (
 
 	SetCookieDomain = "auth.setCookieDomain"
 
+	// AuthAdmins lists the logins of the Pipeline administrators (eg. managing organization quotas)
+	AuthAdmins = "auth.admins"
+
 	// Logging constants
 	LoggingReleaseName = "logging-operator"
 
 	// Spotguides constants
 	SpotguideAllowPrereleases = "spotguide.allowPrereleases"
+
+	// Cluster reaper deleting expired clusters
+	ClusterReaperEnabled           = "cluster.reaper.enabled"
+	ClusterReaperInterval          = "cluster.reaper.interval"
+	ClusterExpirationWarningPeriod = "cluster.reaper.warningPeriod"
+
+	// Cluster drift reconciler comparing clusters with the provider
+	ClusterDriftEnabled     = "cluster.drift.enabled"
+	ClusterDriftInterval    = "cluster.drift.interval"
+	ClusterDriftAutoCorrect = "cluster.drift.autoCorrect"
+
+	// Cluster maintenance scheduler starting queued operations inside maintenance windows
+	ClusterMaintenanceEnabled  = "cluster.maintenance.enabled"
+	ClusterMaintenanceInterval = "cluster.maintenance.interval"
+
+	// Node pool scaler applying the scaling schedules of node pools
+	ClusterNodePoolScalerEnabled  = "cluster.nodePoolScaler.enabled"
+	ClusterNodePoolScalerInterval = "cluster.nodePoolScaler.interval"
+
+	// Secret rotator rotating the secrets with due rotation policies
+	ClusterSecretRotatorEnabled  = "cluster.secretRotator.enabled"
+	ClusterSecretRotatorInterval = "cluster.secretRotator.interval"
+
+	// Posthook runner executing independent posthooks concurrently
+	ClusterPostHookWorkers = "cluster.posthook.workers"
+	ClusterPostHookTimeout = "cluster.posthook.timeout"
+
+	// Organization quotas
+	ClusterQuotaDenyUnknownInstanceTypes = "cluster.quota.denyUnknownInstanceTypes"
+
+	// PricingCatalog is the path of the price catalog used for cost estimates and vCPU quotas
+	PricingCatalog = "pricing.catalog"
 )
 
 //Init initializes the configurations
@@ -209,6 +244,30 @@ func init() {
 
 	viper.SetDefault(SpotguideAllowPrereleases, false)
 
+	viper.SetDefault(ClusterReaperEnabled, true)
+	viper.SetDefault(ClusterReaperInterval, "1m")
+	viper.SetDefault(ClusterExpirationWarningPeriod, "1h")
+
+	viper.SetDefault(ClusterDriftEnabled, true)
+	viper.SetDefault(ClusterDriftInterval, "10m")
+	viper.SetDefault(ClusterDriftAutoCorrect, false)
+
+	viper.SetDefault(ClusterMaintenanceEnabled, true)
+	viper.SetDefault(ClusterMaintenanceInterval, "1m")
+
+	viper.SetDefault(ClusterNodePoolScalerEnabled, true)
+	viper.SetDefault(ClusterNodePoolScalerInterval, "1m")
+
+	viper.SetDefault(ClusterSecretRotatorEnabled, true)
+	viper.SetDefault(ClusterSecretRotatorInterval, "10m")
+
+	viper.SetDefault(ClusterPostHookWorkers, 4)
+	viper.SetDefault(ClusterPostHookTimeout, "15m")
+
+	viper.SetDefault(ClusterQuotaDenyUnknownInstanceTypes, false)
+
+	viper.SetDefault(PricingCatalog, "config/price-catalog.yaml")
+
 	// Find and read the config file
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading config file, %s", err)
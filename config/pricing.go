@@ -0,0 +1,43 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"sync"
+
+	"github.com/banzaicloud/pipeline/pkg/pricing"
+	"github.com/spf13/viper"
+)
+
+var priceCatalogOnce sync.Once
+var priceCatalog *pricing.Catalog
+
+func initPriceCatalog() {
+	var err error
+
+	priceCatalog, err = pricing.LoadCatalogFile(viper.GetString(PricingCatalog))
+	if err != nil {
+		Logger().Warnf("price catalog could not be loaded, costs cannot be estimated: %s", err.Error())
+
+		priceCatalog = pricing.NewCatalog("USD")
+	}
+}
+
+// PriceCatalog returns the price catalog used for cost estimates and vCPU quotas.
+func PriceCatalog() pricing.Source {
+	priceCatalogOnce.Do(initPriceCatalog)
+
+	return priceCatalog
+}
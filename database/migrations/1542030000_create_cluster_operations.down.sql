@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `cluster_operation_steps`;
+DROP TABLE IF EXISTS `cluster_operations`;
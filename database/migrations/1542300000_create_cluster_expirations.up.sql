@@ -0,0 +1,9 @@
+CREATE TABLE `cluster_expirations` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `expires_at` timestamp NULL DEFAULT NULL,
+  `warned_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_expiration_cluster_id` (`cluster_id`),
+  KEY `idx_cluster_expiration_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
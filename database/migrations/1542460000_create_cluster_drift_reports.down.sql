@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `cluster_drift_findings`;
+DROP TABLE IF EXISTS `cluster_drift_reports`;
@@ -0,0 +1,20 @@
+CREATE TABLE `cluster_drift_reports` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `checked_at` timestamp NULL DEFAULT NULL,
+  `error` text COLLATE utf8mb4_unicode_ci,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_drift_report_cluster_id` (`cluster_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `cluster_drift_findings` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `report_id` int(10) unsigned NOT NULL,
+  `node_pool` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `field` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `expected` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `actual` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `detected_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_cluster_drift_findings_report_id` (`report_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,11 @@
+CREATE TABLE `cluster_locks` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `operation_id` int(10) unsigned DEFAULT NULL,
+  `operation_type` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `holder` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `acquired_at` timestamp NULL DEFAULT NULL,
+  `expires_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_lock_cluster_id` (`cluster_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `cluster_queued_operations`;
+DROP TABLE IF EXISTS `maintenance_windows`;
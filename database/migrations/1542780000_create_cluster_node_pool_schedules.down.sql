@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `cluster_node_pool_schedules`;
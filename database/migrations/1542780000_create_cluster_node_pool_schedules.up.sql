@@ -0,0 +1,15 @@
+CREATE TABLE `cluster_node_pool_schedules` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `organization_id` int(10) unsigned NOT NULL,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `node_pool` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `schedule` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `time_zone` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `count` int(11) DEFAULT NULL,
+  `last_run_at` timestamp NULL DEFAULT NULL,
+  `last_error` text COLLATE utf8mb4_unicode_ci,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `created_by` int(10) unsigned DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_cluster_node_pool_schedules_cluster_id` (`cluster_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+CREATE TABLE `organization_quotas` (
+  `organization_id` int(10) unsigned NOT NULL,
+  `limits` text COLLATE utf8mb4_unicode_ci,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `updated_by` int(10) unsigned DEFAULT NULL,
+  PRIMARY KEY (`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
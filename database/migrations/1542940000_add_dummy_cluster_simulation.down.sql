@@ -0,0 +1,2 @@
+ALTER TABLE `dummy_clusters` DROP COLUMN `simulation`;
+ALTER TABLE `dummy_clusters` DROP COLUMN `node_pools`;
@@ -0,0 +1,2 @@
+ALTER TABLE `dummy_clusters` ADD COLUMN `node_pools` text COLLATE utf8mb4_unicode_ci;
+ALTER TABLE `dummy_clusters` ADD COLUMN `simulation` text COLLATE utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+ALTER TABLE `custom_posthooks` DROP COLUMN `depends_on`;
@@ -0,0 +1 @@
+ALTER TABLE `custom_posthooks` ADD COLUMN `depends_on` text COLLATE utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `secret_rotation_clusters`;
+DROP TABLE IF EXISTS `secret_rotations`;
+DROP TABLE IF EXISTS `secret_rotation_policies`;
+DROP TABLE IF EXISTS `secret_installations`;
@@ -0,0 +1,60 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// LogEvent records an event (eg. turning off a safety feature) of a request in the audit log.
+// Unlike the request logging middleware it is called after the operation succeeded,
+// so the record contains the outcome instead of the raw request body.
+func LogEvent(db *gorm.DB, c *gin.Context, statusCode int, event interface{}) error {
+	rawBody, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal audit event")
+	}
+
+	body := string(rawBody)
+
+	var userID uint
+	if user := auth.GetCurrentUser(c.Request); user != nil {
+		userID = user.ID
+	}
+
+	auditEvent := AuditEvent{
+		Time:       time.Now(),
+		ClientIP:   c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+		UserID:     userID,
+		StatusCode: statusCode,
+		Method:     c.Request.Method,
+		Path:       c.Request.URL.Path,
+		Body:       &body,
+		Headers:    "{}",
+	}
+
+	if err := db.Save(&auditEvent).Error; err != nil {
+		return errors.Wrap(err, "could not save audit event")
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@
 package cluster
 
 import (
+	"time"
+
 	"github.com/banzaicloud/pipeline/model"
 	"github.com/goph/emperror"
 	"github.com/jinzhu/gorm"
@@ -61,7 +63,7 @@ func (c *Clusters) All() ([]*model.ClusterModel, error) {
 func (c *Clusters) FindByOrganization(organizationID uint) ([]*model.ClusterModel, error) {
 	var clusters []*model.ClusterModel
 
-	err := c.db.Find(&clusters, map[string]interface{}{"organization_id": organizationID}).Error
+	err := c.db.Preload("Labels").Preload("Expiration").Preload("DeletionProtection").Find(&clusters, map[string]interface{}{"organization_id": organizationID}).Error
 	if err != nil {
 		return nil, errors.Wrap(err, "could not fetch clusters")
 	}
@@ -123,7 +125,7 @@ func (c *Clusters) findOneBy(organizationID uint, field string, criteria interfa
 		cluster.Name = name
 	}
 
-	err := c.db.Where(cluster).First(&cluster).Error
+	err := c.db.Where(cluster).Preload("Labels").Preload("Expiration").Preload("DeletionProtection").First(&cluster).Error
 	if gorm.IsRecordNotFoundError(err) {
 		return nil, errors.WithStack(&clusterModelNotFoundError{
 			cluster:        criteria,
@@ -157,3 +159,138 @@ func (c *Clusters) FindBySecret(organizationID uint, secretID string) ([]*model.
 
 	return clusters, nil
 }
+
+// SetLabels replaces the labels of a cluster.
+func (c *Clusters) SetLabels(clusterID uint, labels map[string]string) error {
+	tx := c.db.Begin()
+
+	err := tx.Where(model.ClusterLabelModel{ClusterID: clusterID}).Delete(model.ClusterLabelModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return emperror.With(errors.Wrap(err, "could not delete cluster labels"), "cluster", clusterID)
+	}
+
+	for key, value := range labels {
+		label := model.ClusterLabelModel{
+			ClusterID: clusterID,
+			Key:       key,
+			Value:     value,
+		}
+
+		if err := tx.Create(&label).Error; err != nil {
+			tx.Rollback()
+
+			return emperror.With(errors.Wrap(err, "could not create cluster label"), "cluster", clusterID, "label", key)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return emperror.With(errors.Wrap(err, "could not save cluster labels"), "cluster", clusterID)
+	}
+
+	return nil
+}
+
+// FindExpiringBefore returns all cluster instances which expire before the given time.
+func (c *Clusters) FindExpiringBefore(t time.Time) ([]*model.ClusterModel, error) {
+	var clusters []*model.ClusterModel
+
+	err := c.db.
+		Joins("JOIN cluster_expirations ON cluster_expirations.cluster_id = clusters.id").
+		Where("cluster_expirations.expires_at <= ?", t).
+		Preload("Expiration").
+		Preload("DeletionProtection").
+		Find(&clusters).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch expiring clusters")
+	}
+
+	return clusters, nil
+}
+
+// SetExpiration sets the expiration time of a cluster. A nil value removes the expiration.
+func (c *Clusters) SetExpiration(clusterID uint, expiresAt *time.Time) error {
+	tx := c.db.Begin()
+
+	err := tx.Where(model.ClusterExpirationModel{ClusterID: clusterID}).Delete(model.ClusterExpirationModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return emperror.With(errors.Wrap(err, "could not delete cluster expiration"), "cluster", clusterID)
+	}
+
+	if expiresAt != nil {
+		expiration := model.ClusterExpirationModel{
+			ClusterID: clusterID,
+			ExpiresAt: *expiresAt,
+		}
+
+		if err := tx.Create(&expiration).Error; err != nil {
+			tx.Rollback()
+
+			return emperror.With(errors.Wrap(err, "could not create cluster expiration"), "cluster", clusterID)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return emperror.With(errors.Wrap(err, "could not save cluster expiration"), "cluster", clusterID)
+	}
+
+	return nil
+}
+
+// SetExpirationWarned records that the owners of a cluster have been warned about its expiration.
+func (c *Clusters) SetExpirationWarned(clusterID uint, warnedAt time.Time) error {
+	err := c.db.Model(model.ClusterExpirationModel{}).
+		Where(model.ClusterExpirationModel{ClusterID: clusterID}).
+		Update("warned_at", warnedAt).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not update cluster expiration"), "cluster", clusterID)
+	}
+
+	return nil
+}
+
+// SetDeletionProtection enables or disables the deletion protection of a cluster.
+func (c *Clusters) SetDeletionProtection(clusterID uint, enabled bool, userID uint) error {
+	protection := model.DeletionProtectionModel{
+		ClusterID: clusterID,
+	}
+
+	var err error
+	if enabled {
+		err = c.db.Where(protection).Attrs(model.DeletionProtectionModel{CreatedBy: userID}).FirstOrCreate(&protection).Error
+	} else {
+		err = c.db.Where(protection).Delete(model.DeletionProtectionModel{}).Error
+	}
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not set cluster deletion protection"), "cluster", clusterID)
+	}
+
+	return nil
+}
+
+// SetFeatureFlag sets a feature flag (eg. monitoring or logging) of a cluster.
+func (c *Clusters) SetFeatureFlag(clusterID uint, flag string, enabled bool) error {
+	err := c.db.Model(model.ClusterModel{}).
+		Where("id = ?", clusterID).
+		Update(flag, enabled).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not update cluster feature flag"), "cluster", clusterID, "flag", flag)
+	}
+
+	return nil
+}
+
+// FindStatusHistory returns the status transitions of a cluster in the order they happened.
+func (c *Clusters) FindStatusHistory(clusterID uint) ([]*model.ClusterStatusHistoryModel, error) {
+	var history []*model.ClusterStatusHistoryModel
+
+	err := c.db.Where(model.ClusterStatusHistoryModel{ClusterID: clusterID}).Order("id").Find(&history).Error
+	if err != nil {
+		return nil, emperror.With(errors.Wrap(err, "could not get cluster status history"), "cluster", clusterID)
+	}
+
+	return history, nil
+}
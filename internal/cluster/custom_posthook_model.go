@@ -0,0 +1,129 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	customPostHooksTableName = "custom_posthooks"
+)
+
+// CustomPostHookModel describes a user-defined posthook of an organization.
+type CustomPostHookModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_custom_posthook_organization_name;not null"`
+	Name           string `gorm:"unique_index:idx_custom_posthook_organization_name"`
+
+	Chart      string
+	Version    string
+	Repository string
+	Namespace  string
+	Values     string `sql:"type:text;"`
+
+	// Params contains the JSON encoded list of the required params
+	Params string `sql:"type:text;"`
+
+	// DependsOn contains the JSON encoded list of the posthooks which have to succeed before the posthook runs
+	DependsOn string `sql:"type:text;"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (CustomPostHookModel) TableName() string {
+	return customPostHooksTableName
+}
+
+// PostHook returns the definition of the user-defined posthook.
+func (m *CustomPostHookModel) PostHook() (*pkgCluster.CustomPostHook, error) {
+	postHook := &pkgCluster.CustomPostHook{
+		Name:       m.Name,
+		Chart:      m.Chart,
+		Version:    m.Version,
+		Repository: m.Repository,
+		Namespace:  m.Namespace,
+		Values:     m.Values,
+	}
+
+	if m.Params != "" {
+		if err := json.Unmarshal([]byte(m.Params), &postHook.Params); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.DependsOn != "" {
+		if err := json.Unmarshal([]byte(m.DependsOn), &postHook.DependsOn); err != nil {
+			return nil, err
+		}
+	}
+
+	return postHook, nil
+}
+
+// SetPostHook stores the definition of the user-defined posthook.
+func (m *CustomPostHookModel) SetPostHook(postHook *pkgCluster.CustomPostHook) error {
+	m.Name = postHook.Name
+	m.Chart = postHook.Chart
+	m.Version = postHook.Version
+	m.Repository = postHook.Repository
+	m.Namespace = postHook.Namespace
+	m.Values = postHook.Values
+	m.Params = ""
+
+	if len(postHook.Params) > 0 {
+		params, err := json.Marshal(postHook.Params)
+		if err != nil {
+			return err
+		}
+
+		m.Params = string(params)
+	}
+
+	m.DependsOn = ""
+
+	if len(postHook.DependsOn) > 0 {
+		dependsOn, err := json.Marshal(postHook.DependsOn)
+		if err != nil {
+			return err
+		}
+
+		m.DependsOn = string(dependsOn)
+	}
+
+	return nil
+}
+
+// ConvertModelToEntity converts a CustomPostHookModel to an API response.
+func (m *CustomPostHookModel) ConvertModelToEntity() (*pkgCluster.CustomPostHookResponse, error) {
+	postHook, err := m.PostHook()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkgCluster.CustomPostHookResponse{
+		CustomPostHook: *postHook,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+		CreatedBy:      m.CreatedBy,
+	}, nil
+}
@@ -0,0 +1,119 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// CustomPostHooks acts as a repository for the user-defined posthooks of organizations.
+type CustomPostHooks struct {
+	db *gorm.DB
+}
+
+// NewCustomPostHooks returns a new CustomPostHooks instance.
+func NewCustomPostHooks(db *gorm.DB) *CustomPostHooks {
+	return &CustomPostHooks{db: db}
+}
+
+// FindByOrganization returns the user-defined posthooks of an organization.
+func (p *CustomPostHooks) FindByOrganization(organizationID uint) ([]*CustomPostHookModel, error) {
+	var postHooks []*CustomPostHookModel
+
+	err := p.db.Where(CustomPostHookModel{OrganizationID: organizationID}).Order("name").Find(&postHooks).Error
+	if err != nil {
+		return nil, emperror.With(errors.Wrap(err, "could not fetch custom posthooks"), "organization", organizationID)
+	}
+
+	return postHooks, nil
+}
+
+// FindOneByName returns a user-defined posthook of an organization.
+func (p *CustomPostHooks) FindOneByName(organizationID uint, name string) (*CustomPostHookModel, error) {
+	var postHook CustomPostHookModel
+
+	err := p.db.Where(CustomPostHookModel{OrganizationID: organizationID, Name: name}).First(&postHook).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, errors.WithStack(&customPostHookNotFoundError{
+			organizationID: organizationID,
+			name:           name,
+		})
+	} else if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch custom posthook"),
+			"organization", organizationID,
+			"posthook", name,
+		)
+	}
+
+	return &postHook, nil
+}
+
+type customPostHookNotFoundError struct {
+	organizationID uint
+	name           string
+}
+
+func (e *customPostHookNotFoundError) Error() string {
+	return "custom posthook not found"
+}
+
+func (e *customPostHookNotFoundError) Context() []interface{} {
+	return []interface{}{
+		"organization", e.organizationID,
+		"posthook", e.name,
+	}
+}
+
+func (e *customPostHookNotFoundError) NotFound() bool {
+	return true
+}
+
+// Save persists a user-defined posthook.
+func (p *CustomPostHooks) Save(postHook *CustomPostHookModel) error {
+	err := p.db.Save(postHook).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not save custom posthook"),
+			"organization", postHook.OrganizationID,
+			"posthook", postHook.Name,
+		)
+	}
+
+	return nil
+}
+
+// Delete deletes a user-defined posthook of an organization.
+func (p *CustomPostHooks) Delete(organizationID uint, name string) error {
+	result := p.db.Where("organization_id = ? AND name = ?", organizationID, name).Delete(CustomPostHookModel{})
+	if result.Error != nil {
+		return emperror.With(
+			errors.Wrap(result.Error, "could not delete custom posthook"),
+			"organization", organizationID,
+			"posthook", name,
+		)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.WithStack(&customPostHookNotFoundError{
+			organizationID: organizationID,
+			name:           name,
+		})
+	}
+
+	return nil
+}
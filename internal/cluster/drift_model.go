@@ -0,0 +1,85 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	driftReportsTableName  = "cluster_drift_reports"
+	driftFindingsTableName = "cluster_drift_findings"
+)
+
+// DriftReportModel describes the result of the latest drift check of a cluster.
+type DriftReportModel struct {
+	ID        uint `gorm:"primary_key"`
+	ClusterID uint `gorm:"unique_index:idx_cluster_drift_report_cluster_id;not null"`
+
+	CheckedAt time.Time
+	Error     string `sql:"type:text;"`
+
+	Findings []DriftFindingModel `gorm:"foreignkey:ReportID"`
+}
+
+// TableName changes the default table name.
+func (DriftReportModel) TableName() string {
+	return driftReportsTableName
+}
+
+// DriftFindingModel describes a single difference between the recorded and the observed state of a cluster.
+type DriftFindingModel struct {
+	ID       uint `gorm:"primary_key"`
+	ReportID uint `gorm:"index;not null"`
+
+	NodePool string
+	Field    string
+	Expected string
+	Actual   string
+
+	DetectedAt time.Time
+}
+
+// TableName changes the default table name.
+func (DriftFindingModel) TableName() string {
+	return driftFindingsTableName
+}
+
+// ConvertModelToEntity converts a DriftReportModel to an API response.
+func (m *DriftReportModel) ConvertModelToEntity() *pkgCluster.ClusterDriftResponse {
+	checkedAt := m.CheckedAt
+
+	response := &pkgCluster.ClusterDriftResponse{
+		CheckedAt: &checkedAt,
+		Drifted:   len(m.Findings) > 0,
+		Error:     m.Error,
+		Findings:  make([]pkgCluster.DriftFinding, 0, len(m.Findings)),
+	}
+
+	for _, finding := range m.Findings {
+		response.Findings = append(response.Findings, pkgCluster.DriftFinding{
+			NodePool:   finding.NodePool,
+			Field:      finding.Field,
+			Expected:   finding.Expected,
+			Actual:     finding.Actual,
+			DetectedAt: finding.DetectedAt,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,108 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Drifts acts as a repository for the drift reports of clusters.
+type Drifts struct {
+	db *gorm.DB
+}
+
+// NewDrifts returns a new Drifts instance.
+func NewDrifts(db *gorm.DB) *Drifts {
+	return &Drifts{db: db}
+}
+
+// FindByClusterID returns the latest drift report of a cluster or nil if the cluster has not been checked yet.
+func (d *Drifts) FindByClusterID(clusterID uint) (*DriftReportModel, error) {
+	var report DriftReportModel
+
+	err := d.db.Where(DriftReportModel{ClusterID: clusterID}).Preload("Findings", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&report).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch cluster drift report"),
+			"cluster", clusterID,
+		)
+	}
+
+	return &report, nil
+}
+
+// Save replaces the drift report (and its findings) of a cluster.
+func (d *Drifts) Save(report *DriftReportModel) error {
+	tx := d.db.Begin()
+
+	var previous DriftReportModel
+	err := tx.Where(DriftReportModel{ClusterID: report.ClusterID}).First(&previous).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		tx.Rollback()
+
+		return emperror.With(errors.Wrap(err, "could not fetch cluster drift report"), "cluster", report.ClusterID)
+	}
+
+	if previous.ID != 0 {
+		if err := tx.Where(DriftFindingModel{ReportID: previous.ID}).Delete(DriftFindingModel{}).Error; err != nil {
+			tx.Rollback()
+
+			return emperror.With(errors.Wrap(err, "could not delete cluster drift findings"), "cluster", report.ClusterID)
+		}
+
+		if err := tx.Delete(&previous).Error; err != nil {
+			tx.Rollback()
+
+			return emperror.With(errors.Wrap(err, "could not delete cluster drift report"), "cluster", report.ClusterID)
+		}
+	}
+
+	// the findings are created together with the report
+	if err := tx.Create(report).Error; err != nil {
+		tx.Rollback()
+
+		return emperror.With(errors.Wrap(err, "could not create cluster drift report"), "cluster", report.ClusterID)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return emperror.With(errors.Wrap(err, "could not save cluster drift report"), "cluster", report.ClusterID)
+	}
+
+	return nil
+}
+
+// DeleteByClusterID deletes the drift report of a cluster.
+func (d *Drifts) DeleteByClusterID(clusterID uint) error {
+	report, err := d.FindByClusterID(clusterID)
+	if err != nil || report == nil {
+		return err
+	}
+
+	err = d.db.Where(DriftFindingModel{ReportID: report.ID}).Delete(DriftFindingModel{}).Error
+	if err == nil {
+		err = d.db.Delete(report).Error
+	}
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not delete cluster drift report"), "cluster", clusterID)
+	}
+
+	return nil
+}
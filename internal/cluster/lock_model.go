@@ -0,0 +1,43 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+// TableName constants
+const (
+	locksTableName = "cluster_locks"
+)
+
+// LockModel describes the lock held by the operation running on a cluster.
+// The lock expires unless its holder keeps renewing it, so that a crashed Pipeline instance cannot keep a cluster locked.
+type LockModel struct {
+	ID        uint `gorm:"primary_key"`
+	ClusterID uint `gorm:"unique_index:idx_cluster_lock_cluster_id;not null"`
+
+	OperationID   uint
+	OperationType string
+	Holder        string
+
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// TableName changes the default table name.
+func (LockModel) TableName() string {
+	return locksTableName
+}
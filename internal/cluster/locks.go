@@ -0,0 +1,97 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Locks acts as a repository for the operation locks of clusters.
+type Locks struct {
+	db *gorm.DB
+}
+
+// NewLocks returns a new Locks instance.
+func NewLocks(db *gorm.DB) *Locks {
+	return &Locks{db: db}
+}
+
+// Acquire persists a new lock for a cluster unless another (not yet expired) lock is held on the cluster.
+// The unique index on the cluster makes sure that only one lock can be acquired, even by multiple Pipeline instances.
+func (l *Locks) Acquire(lock *LockModel) error {
+	err := l.db.
+		Where("cluster_id = ? AND expires_at < ?", lock.ClusterID, lock.AcquiredAt).
+		Delete(LockModel{}).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not delete expired cluster lock"), "cluster", lock.ClusterID)
+	}
+
+	err = l.db.Create(lock).Error
+	if err == nil {
+		return nil
+	}
+
+	// creating the lock fails if it is already held, tell who holds it
+	var holder LockModel
+	if findErr := l.db.Where(LockModel{ClusterID: lock.ClusterID}).First(&holder).Error; findErr == nil {
+		return errors.WithStack(&pkgCluster.OperationInProgressError{
+			ClusterID:     holder.ClusterID,
+			OperationID:   holder.OperationID,
+			OperationType: holder.OperationType,
+		})
+	}
+
+	return emperror.With(errors.Wrap(err, "could not acquire cluster lock"), "cluster", lock.ClusterID)
+}
+
+// SetOperation records the operation holding a lock.
+func (l *Locks) SetOperation(lockID uint, operationID uint) error {
+	err := l.db.Model(LockModel{}).Where("id = ?", lockID).Update("operation_id", operationID).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not update cluster lock"), "lock", lockID, "operation", operationID)
+	}
+
+	return nil
+}
+
+// Renew extends the expiration of a lock.
+// It fails if the lock has expired and has been acquired by someone else in the meantime.
+func (l *Locks) Renew(lockID uint, expiresAt time.Time) error {
+	result := l.db.Model(LockModel{}).Where("id = ?", lockID).Update("expires_at", expiresAt)
+	if result.Error != nil {
+		return emperror.With(errors.Wrap(result.Error, "could not renew cluster lock"), "lock", lockID)
+	}
+
+	if result.RowsAffected == 0 {
+		return emperror.With(errors.New("cluster lock has been lost"), "lock", lockID)
+	}
+
+	return nil
+}
+
+// Release deletes a lock.
+func (l *Locks) Release(lockID uint) error {
+	err := l.db.Where("id = ?", lockID).Delete(LockModel{}).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not release cluster lock"), "lock", lockID)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Maintenance acts as a repository for maintenance windows and the operations queued until they open.
+type Maintenance struct {
+	db *gorm.DB
+}
+
+// NewMaintenance returns a new Maintenance instance.
+func NewMaintenance(db *gorm.DB) *Maintenance {
+	return &Maintenance{db: db}
+}
+
+// FindWindows returns the maintenance windows of an organization (when clusterID is 0) or of a cluster.
+func (m *Maintenance) FindWindows(organizationID uint, clusterID uint) ([]*MaintenanceWindowModel, error) {
+	var windows []*MaintenanceWindowModel
+
+	err := m.db.
+		Where("organization_id = ? AND cluster_id = ?", organizationID, clusterID).
+		Order("id").
+		Find(&windows).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch maintenance windows"),
+			"organization", organizationID,
+			"cluster", clusterID,
+		)
+	}
+
+	return windows, nil
+}
+
+// CreateWindow persists a new maintenance window.
+func (m *Maintenance) CreateWindow(window *MaintenanceWindowModel) error {
+	err := m.db.Create(window).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not create maintenance window"),
+			"organization", window.OrganizationID,
+			"cluster", window.ClusterID,
+		)
+	}
+
+	return nil
+}
+
+type maintenanceWindowNotFoundError struct {
+	windowID       uint
+	clusterID      uint
+	organizationID uint
+}
+
+func (e *maintenanceWindowNotFoundError) Error() string {
+	return "maintenance window not found"
+}
+
+func (e *maintenanceWindowNotFoundError) Context() []interface{} {
+	return []interface{}{
+		"window", e.windowID,
+		"cluster", e.clusterID,
+		"organization", e.organizationID,
+	}
+}
+
+func (e *maintenanceWindowNotFoundError) NotFound() bool {
+	return true
+}
+
+// DeleteWindow deletes a maintenance window of an organization (when clusterID is 0) or of a cluster.
+func (m *Maintenance) DeleteWindow(organizationID uint, clusterID uint, windowID uint) error {
+	result := m.db.
+		Where("id = ? AND organization_id = ? AND cluster_id = ?", windowID, organizationID, clusterID).
+		Delete(MaintenanceWindowModel{})
+	if result.Error != nil {
+		return emperror.With(
+			errors.Wrap(result.Error, "could not delete maintenance window"),
+			"window", windowID,
+			"cluster", clusterID,
+			"organization", organizationID,
+		)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.WithStack(&maintenanceWindowNotFoundError{
+			windowID:       windowID,
+			clusterID:      clusterID,
+			organizationID: organizationID,
+		})
+	}
+
+	return nil
+}
+
+// CreateQueuedOperation persists a new queued operation.
+func (m *Maintenance) CreateQueuedOperation(operation *QueuedOperationModel) error {
+	err := m.db.Create(operation).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not queue cluster operation"),
+			"cluster", operation.ClusterID,
+			"organization", operation.OrganizationID,
+		)
+	}
+
+	return nil
+}
+
+// SaveQueuedOperation updates an existing queued operation.
+func (m *Maintenance) SaveQueuedOperation(operation *QueuedOperationModel) error {
+	err := m.db.Save(operation).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not save queued cluster operation"), "operation", operation.ID)
+	}
+
+	return nil
+}
+
+// FindQueuedOperations returns the queued operations of a cluster, the latest first.
+func (m *Maintenance) FindQueuedOperations(organizationID uint, clusterID uint) ([]*QueuedOperationModel, error) {
+	var operations []*QueuedOperationModel
+
+	query := QueuedOperationModel{
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+	}
+
+	err := m.db.Where(query).Order("id desc").Find(&operations).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch queued cluster operations"),
+			"cluster", clusterID,
+			"organization", organizationID,
+		)
+	}
+
+	return operations, nil
+}
+
+// FindPendingQueuedOperations returns the operations still waiting for a maintenance window, the oldest first.
+func (m *Maintenance) FindPendingQueuedOperations() ([]*QueuedOperationModel, error) {
+	var operations []*QueuedOperationModel
+
+	err := m.db.Where(QueuedOperationModel{State: pkgCluster.QueuedOperationQueued}).Order("id").Find(&operations).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch pending queued cluster operations")
+	}
+
+	return operations, nil
+}
+
+// StartQueuedOperation marks a queued operation started unless it has been started or cancelled in the meantime.
+// Only one Pipeline instance succeeds in starting an operation.
+func (m *Maintenance) StartQueuedOperation(operation *QueuedOperationModel, startedAt time.Time) (bool, error) {
+	result := m.db.Model(QueuedOperationModel{}).
+		Where("id = ? AND state = ?", operation.ID, pkgCluster.QueuedOperationQueued).
+		Updates(map[string]interface{}{
+			"state":      pkgCluster.QueuedOperationStarted,
+			"started_at": startedAt,
+		})
+	if result.Error != nil {
+		return false, emperror.With(errors.Wrap(result.Error, "could not start queued cluster operation"), "operation", operation.ID)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	operation.State = pkgCluster.QueuedOperationStarted
+	operation.StartedAt = &startedAt
+
+	return true, nil
+}
+
+type queuedOperationNotFoundError struct {
+	operationID    uint
+	clusterID      uint
+	organizationID uint
+}
+
+func (e *queuedOperationNotFoundError) Error() string {
+	return "queued cluster operation not found"
+}
+
+func (e *queuedOperationNotFoundError) Context() []interface{} {
+	return []interface{}{
+		"operation", e.operationID,
+		"cluster", e.clusterID,
+		"organization", e.organizationID,
+	}
+}
+
+func (e *queuedOperationNotFoundError) NotFound() bool {
+	return true
+}
+
+type queuedOperationNotCancellableError struct {
+	state string
+}
+
+func (e *queuedOperationNotCancellableError) Error() string {
+	return "only queued operations can be cancelled, operation is " + e.state
+}
+
+func (e *queuedOperationNotCancellableError) PreconditionFailed() bool {
+	return true
+}
+
+// CancelQueuedOperation cancels an operation still waiting for a maintenance window.
+func (m *Maintenance) CancelQueuedOperation(organizationID uint, clusterID uint, operationID uint) error {
+	operation := QueuedOperationModel{
+		ID:             operationID,
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+	}
+
+	err := m.db.Where(operation).First(&operation).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return errors.WithStack(&queuedOperationNotFoundError{
+			operationID:    operationID,
+			clusterID:      clusterID,
+			organizationID: organizationID,
+		})
+	} else if err != nil {
+		return emperror.With(errors.Wrap(err, "could not get queued cluster operation"), "operation", operationID)
+	}
+
+	result := m.db.Model(QueuedOperationModel{}).
+		Where("id = ? AND state = ?", operationID, pkgCluster.QueuedOperationQueued).
+		Update("state", pkgCluster.QueuedOperationCancelled)
+	if result.Error != nil {
+		return emperror.With(errors.Wrap(result.Error, "could not cancel queued cluster operation"), "operation", operationID)
+	}
+
+	// the operation might have been started since it was fetched
+	if result.RowsAffected == 0 {
+		state := operation.State
+		if state == pkgCluster.QueuedOperationQueued {
+			state = pkgCluster.QueuedOperationStarted
+		}
+
+		return errors.WithStack(&queuedOperationNotCancellableError{state: state})
+	}
+
+	return nil
+}
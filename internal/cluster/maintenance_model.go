@@ -0,0 +1,111 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	maintenanceWindowsTableName = "maintenance_windows"
+	queuedOperationsTableName   = "cluster_queued_operations"
+)
+
+// MaintenanceWindowModel describes a maintenance window of an organization or (when ClusterID is set) of a cluster.
+type MaintenanceWindowModel struct {
+	ID             uint `gorm:"primary_key"`
+	OrganizationID uint `gorm:"index;not null"`
+	ClusterID      uint `gorm:"index;not null"`
+
+	Schedule string
+	Duration string
+	TimeZone string
+
+	CreatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (MaintenanceWindowModel) TableName() string {
+	return maintenanceWindowsTableName
+}
+
+// Window returns the recurring period described by the model.
+func (m *MaintenanceWindowModel) Window() pkgCluster.MaintenanceWindow {
+	return pkgCluster.MaintenanceWindow{
+		Schedule: m.Schedule,
+		Duration: m.Duration,
+		TimeZone: m.TimeZone,
+	}
+}
+
+// ConvertModelToEntity converts a MaintenanceWindowModel to an API response.
+func (m *MaintenanceWindowModel) ConvertModelToEntity(now time.Time) *pkgCluster.MaintenanceWindowResponse {
+	response := &pkgCluster.MaintenanceWindowResponse{
+		ID:        m.ID,
+		ClusterID: m.ClusterID,
+		Schedule:  m.Schedule,
+		Duration:  m.Duration,
+		TimeZone:  m.TimeZone,
+		CreatedAt: m.CreatedAt,
+		CreatedBy: m.CreatedBy,
+	}
+
+	if nextStart, err := m.Window().NextStart(now); err == nil && !nextStart.IsZero() {
+		response.NextStart = &nextStart
+	}
+
+	return response
+}
+
+// QueuedOperationModel describes an operation submitted outside the maintenance windows of a cluster.
+type QueuedOperationModel struct {
+	ID             uint `gorm:"primary_key"`
+	OrganizationID uint `gorm:"index;not null"`
+	ClusterID      uint `gorm:"index;not null"`
+
+	Type    string
+	Request string `sql:"type:text;"`
+	State   string `gorm:"index"`
+	UserID  uint
+
+	CreatedAt   time.Time
+	ScheduledAt *time.Time
+	StartedAt   *time.Time
+	Error       string `sql:"type:text;"`
+}
+
+// TableName changes the default table name.
+func (QueuedOperationModel) TableName() string {
+	return queuedOperationsTableName
+}
+
+// ConvertModelToEntity converts a QueuedOperationModel to an API response.
+func (m *QueuedOperationModel) ConvertModelToEntity() *pkgCluster.QueuedOperationResponse {
+	return &pkgCluster.QueuedOperationResponse{
+		ID:          m.ID,
+		ClusterID:   m.ClusterID,
+		Type:        m.Type,
+		State:       m.State,
+		Actor:       m.UserID,
+		CreatedAt:   m.CreatedAt,
+		ScheduledAt: m.ScheduledAt,
+		StartedAt:   m.StartedAt,
+		Error:       m.Error,
+	}
+}
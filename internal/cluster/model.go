@@ -26,6 +26,21 @@ import (
 func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 	tables := []interface{}{
 		&ClusterModel{},
+		&OperationModel{},
+		&OperationStepModel{},
+		&PostHookModel{},
+		&DriftReportModel{},
+		&DriftFindingModel{},
+		&LockModel{},
+		&MaintenanceWindowModel{},
+		&QueuedOperationModel{},
+		&NodePoolScheduleModel{},
+		&OrganizationQuotaModel{},
+		&CustomPostHookModel{},
+		&SecretInstallationModel{},
+		&SecretRotationPolicyModel{},
+		&SecretRotationModel{},
+		&SecretRotationClusterModel{},
 	}
 
 	var tableNames string
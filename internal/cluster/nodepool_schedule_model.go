@@ -0,0 +1,80 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	nodePoolSchedulesTableName = "cluster_node_pool_schedules"
+)
+
+// NodePoolScheduleModel describes a scaling schedule of a node pool.
+type NodePoolScheduleModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"not null"`
+	ClusterID      uint   `gorm:"index;not null"`
+	NodePool       string `gorm:"not null"`
+
+	Schedule string
+	TimeZone string
+	Count    int
+
+	LastRunAt *time.Time
+	LastError string `sql:"type:text;"`
+
+	CreatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (NodePoolScheduleModel) TableName() string {
+	return nodePoolSchedulesTableName
+}
+
+// NodePoolSchedule returns the schedule described by the model.
+func (m *NodePoolScheduleModel) NodePoolSchedule() pkgCluster.NodePoolSchedule {
+	return pkgCluster.NodePoolSchedule{
+		Schedule: m.Schedule,
+		TimeZone: m.TimeZone,
+		Count:    m.Count,
+	}
+}
+
+// ConvertModelToEntity converts a NodePoolScheduleModel to an API response.
+func (m *NodePoolScheduleModel) ConvertModelToEntity(now time.Time) *pkgCluster.NodePoolScheduleResponse {
+	response := &pkgCluster.NodePoolScheduleResponse{
+		ID:        m.ID,
+		ClusterID: m.ClusterID,
+		NodePool:  m.NodePool,
+		Schedule:  m.Schedule,
+		TimeZone:  m.TimeZone,
+		Count:     m.Count,
+		LastRunAt: m.LastRunAt,
+		LastError: m.LastError,
+		CreatedAt: m.CreatedAt,
+		CreatedBy: m.CreatedBy,
+	}
+
+	if nextRun, err := m.NodePoolSchedule().NextRun(now); err == nil && !nextRun.IsZero() {
+		response.NextRun = &nextRun
+	}
+
+	return response
+}
@@ -0,0 +1,164 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// NodePoolSchedules acts as a repository for node pool scaling schedules.
+type NodePoolSchedules struct {
+	db *gorm.DB
+}
+
+// NewNodePoolSchedules returns a new NodePoolSchedules instance.
+func NewNodePoolSchedules(db *gorm.DB) *NodePoolSchedules {
+	return &NodePoolSchedules{db: db}
+}
+
+// All returns all node pool scaling schedules.
+func (s *NodePoolSchedules) All() ([]*NodePoolScheduleModel, error) {
+	var schedules []*NodePoolScheduleModel
+
+	err := s.db.Order("id").Find(&schedules).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node pool schedules")
+	}
+
+	return schedules, nil
+}
+
+// FindByNodePool returns the scaling schedules of a node pool.
+func (s *NodePoolSchedules) FindByNodePool(clusterID uint, nodePool string) ([]*NodePoolScheduleModel, error) {
+	var schedules []*NodePoolScheduleModel
+
+	err := s.db.Where(NodePoolScheduleModel{ClusterID: clusterID, NodePool: nodePool}).Order("id").Find(&schedules).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch node pool schedules"),
+			"cluster", clusterID,
+			"nodePool", nodePool,
+		)
+	}
+
+	return schedules, nil
+}
+
+// Create persists a new node pool scaling schedule.
+func (s *NodePoolSchedules) Create(schedule *NodePoolScheduleModel) error {
+	err := s.db.Create(schedule).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not create node pool schedule"),
+			"cluster", schedule.ClusterID,
+			"nodePool", schedule.NodePool,
+		)
+	}
+
+	return nil
+}
+
+type nodePoolScheduleNotFoundError struct {
+	scheduleID uint
+	clusterID  uint
+	nodePool   string
+}
+
+func (e *nodePoolScheduleNotFoundError) Error() string {
+	return "node pool schedule not found"
+}
+
+func (e *nodePoolScheduleNotFoundError) Context() []interface{} {
+	return []interface{}{
+		"schedule", e.scheduleID,
+		"cluster", e.clusterID,
+		"nodePool", e.nodePool,
+	}
+}
+
+func (e *nodePoolScheduleNotFoundError) NotFound() bool {
+	return true
+}
+
+// Delete deletes a scaling schedule of a node pool.
+func (s *NodePoolSchedules) Delete(clusterID uint, nodePool string, scheduleID uint) error {
+	result := s.db.
+		Where("id = ? AND cluster_id = ? AND node_pool = ?", scheduleID, clusterID, nodePool).
+		Delete(NodePoolScheduleModel{})
+	if result.Error != nil {
+		return emperror.With(
+			errors.Wrap(result.Error, "could not delete node pool schedule"),
+			"schedule", scheduleID,
+			"cluster", clusterID,
+			"nodePool", nodePool,
+		)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.WithStack(&nodePoolScheduleNotFoundError{
+			scheduleID: scheduleID,
+			clusterID:  clusterID,
+			nodePool:   nodePool,
+		})
+	}
+
+	return nil
+}
+
+// DeleteByClusterID deletes the scaling schedules of all node pools of a cluster.
+func (s *NodePoolSchedules) DeleteByClusterID(clusterID uint) error {
+	err := s.db.Where("cluster_id = ?", clusterID).Delete(NodePoolScheduleModel{}).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not delete node pool schedules"), "cluster", clusterID)
+	}
+
+	return nil
+}
+
+// Claim records a run of a schedule unless it has already been recorded (eg. by another Pipeline instance).
+func (s *NodePoolSchedules) Claim(schedule *NodePoolScheduleModel, runAt time.Time) (bool, error) {
+	result := s.db.Model(NodePoolScheduleModel{}).
+		Where("id = ? AND (last_run_at IS NULL OR last_run_at < ?)", schedule.ID, runAt).
+		Update("last_run_at", runAt)
+	if result.Error != nil {
+		return false, emperror.With(errors.Wrap(result.Error, "could not claim node pool schedule run"), "schedule", schedule.ID)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	schedule.LastRunAt = &runAt
+
+	return true, nil
+}
+
+// SetResult records the result of the last run of a schedule.
+// Passing nil as the time of the last run makes the run retried.
+func (s *NodePoolSchedules) SetResult(scheduleID uint, lastRunAt *time.Time, lastError string) error {
+	err := s.db.Model(NodePoolScheduleModel{}).Where("id = ?", scheduleID).Updates(map[string]interface{}{
+		"last_run_at": lastRunAt,
+		"last_error":  lastError,
+	}).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not save node pool schedule result"), "schedule", scheduleID)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	operationsTableName     = "cluster_operations"
+	operationStepsTableName = "cluster_operation_steps"
+)
+
+// OperationModel describes a cluster operation (create, update, delete, etc.) and its progress.
+type OperationModel struct {
+	ID             uint `gorm:"primary_key"`
+	ClusterID      uint `gorm:"index;not null"`
+	OrganizationID uint `gorm:"index;not null"`
+
+	Type  string
+	State string
+	Actor uint
+	Error string `sql:"type:text;"`
+
+	StartedAt  time.Time
+	FinishedAt *time.Time
+
+	Steps []OperationStepModel `gorm:"foreignkey:OperationID"`
+}
+
+// TableName changes the default table name.
+func (OperationModel) TableName() string {
+	return operationsTableName
+}
+
+// OperationStepModel describes a single step of a cluster operation.
+type OperationStepModel struct {
+	ID          uint `gorm:"primary_key"`
+	OperationID uint `gorm:"index;not null"`
+
+	Name    string
+	Message string `sql:"type:text;"`
+
+	CreatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (OperationStepModel) TableName() string {
+	return operationStepsTableName
+}
+
+// ConvertModelToEntity converts an OperationModel to an API response.
+func (m *OperationModel) ConvertModelToEntity() *pkgCluster.OperationResponse {
+	response := &pkgCluster.OperationResponse{
+		ID:         m.ID,
+		ClusterID:  m.ClusterID,
+		Type:       m.Type,
+		State:      m.State,
+		Actor:      m.Actor,
+		StartedAt:  m.StartedAt,
+		FinishedAt: m.FinishedAt,
+		Error:      m.Error,
+	}
+
+	for _, step := range m.Steps {
+		response.Steps = append(response.Steps, pkgCluster.OperationStepResponse{
+			Name:      step.Name,
+			Message:   step.Message,
+			CreatedAt: step.CreatedAt,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,148 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Operations acts as a repository for cluster operations.
+type Operations struct {
+	db *gorm.DB
+}
+
+// NewOperations returns a new Operations instance.
+func NewOperations(db *gorm.DB) *Operations {
+	return &Operations{db: db}
+}
+
+// Create persists a new cluster operation.
+func (o *Operations) Create(operation *OperationModel) error {
+	err := o.db.Create(operation).Error
+	if err != nil {
+		return errors.Wrap(err, "could not create cluster operation")
+	}
+
+	return nil
+}
+
+// Save updates an existing cluster operation.
+func (o *Operations) Save(operation *OperationModel) error {
+	err := o.db.Save(operation).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not save cluster operation"),
+			"operation", operation.ID,
+		)
+	}
+
+	return nil
+}
+
+// AddStep appends a step to the log of a cluster operation.
+func (o *Operations) AddStep(operationID uint, name string, message string) error {
+	step := OperationStepModel{
+		OperationID: operationID,
+		Name:        name,
+		Message:     message,
+	}
+
+	err := o.db.Create(&step).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not add cluster operation step"),
+			"operation", operationID,
+			"step", name,
+		)
+	}
+
+	return nil
+}
+
+// FindByClusterID returns all operations of a cluster, the latest first.
+func (o *Operations) FindByClusterID(organizationID uint, clusterID uint) ([]*OperationModel, error) {
+	var operations []*OperationModel
+
+	query := OperationModel{
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+	}
+
+	err := o.db.Where(query).Order("started_at desc, id desc").Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Find(&operations).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch cluster operations"),
+			"cluster", clusterID,
+			"organization", organizationID,
+		)
+	}
+
+	return operations, nil
+}
+
+type operationNotFoundError struct {
+	operationID    uint
+	clusterID      uint
+	organizationID uint
+}
+
+func (e *operationNotFoundError) Error() string {
+	return "cluster operation not found"
+}
+
+func (e *operationNotFoundError) Context() []interface{} {
+	return []interface{}{
+		"operation", e.operationID,
+		"cluster", e.clusterID,
+		"organization", e.organizationID,
+	}
+}
+
+func (e *operationNotFoundError) NotFound() bool {
+	return true
+}
+
+// FindOneByID returns a single operation of a cluster.
+func (o *Operations) FindOneByID(organizationID uint, clusterID uint, operationID uint) (*OperationModel, error) {
+	operation := OperationModel{
+		ID:             operationID,
+		OrganizationID: organizationID,
+		ClusterID:      clusterID,
+	}
+
+	err := o.db.Where(operation).Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&operation).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, errors.WithStack(&operationNotFoundError{
+			operationID:    operationID,
+			clusterID:      clusterID,
+			organizationID: organizationID,
+		})
+	} else if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not get cluster operation"),
+			"operation", operationID,
+			"cluster", clusterID,
+			"organization", organizationID,
+		)
+	}
+
+	return &operation, nil
+}
@@ -0,0 +1,68 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	postHooksTableName = "cluster_posthooks"
+)
+
+// PostHookModel describes the state of a single posthook of a cluster.
+type PostHookModel struct {
+	ID        uint   `gorm:"primary_key"`
+	ClusterID uint   `gorm:"unique_index:idx_cluster_posthook_name;not null"`
+	Name      string `gorm:"unique_index:idx_cluster_posthook_name"`
+
+	// Position is the place of the posthook in the order of execution.
+	Position int
+	Params   string `sql:"type:text;"`
+
+	State string
+	Error string `sql:"type:text;"`
+
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (PostHookModel) TableName() string {
+	return postHooksTableName
+}
+
+// ConvertModelToEntity converts a PostHookModel to an API response.
+func (m *PostHookModel) ConvertModelToEntity() *pkgCluster.PostHookStatus {
+	status := &pkgCluster.PostHookStatus{
+		Name:       m.Name,
+		State:      m.State,
+		StartedAt:  m.StartedAt,
+		FinishedAt: m.FinishedAt,
+		Error:      m.Error,
+	}
+
+	if m.StartedAt != nil && m.FinishedAt != nil {
+		status.Duration = m.FinishedAt.Sub(*m.StartedAt).String()
+	}
+
+	return status
+}
@@ -0,0 +1,73 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// PostHooks acts as a repository for the posthook states of clusters.
+type PostHooks struct {
+	db *gorm.DB
+}
+
+// NewPostHooks returns a new PostHooks instance.
+func NewPostHooks(db *gorm.DB) *PostHooks {
+	return &PostHooks{db: db}
+}
+
+// FindByClusterID returns the posthooks of a cluster in the order of execution.
+func (p *PostHooks) FindByClusterID(clusterID uint) ([]*PostHookModel, error) {
+	var postHooks []*PostHookModel
+
+	err := p.db.Where(PostHookModel{ClusterID: clusterID}).Order("position").Find(&postHooks).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch cluster posthooks"),
+			"cluster", clusterID,
+		)
+	}
+
+	return postHooks, nil
+}
+
+// Save creates or updates the state of a posthook.
+func (p *PostHooks) Save(postHook *PostHookModel) error {
+	err := p.db.Save(postHook).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not save cluster posthook"),
+			"cluster", postHook.ClusterID,
+			"posthook", postHook.Name,
+		)
+	}
+
+	return nil
+}
+
+// DeleteByClusterID deletes every posthook state of a cluster.
+func (p *PostHooks) DeleteByClusterID(clusterID uint) error {
+	err := p.db.Where(PostHookModel{ClusterID: clusterID}).Delete(PostHookModel{}).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not delete cluster posthooks"),
+			"cluster", clusterID,
+		)
+	}
+
+	return nil
+}
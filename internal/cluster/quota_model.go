@@ -0,0 +1,40 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+// TableName constants
+const (
+	organizationQuotasTableName = "organization_quotas"
+)
+
+// OrganizationQuotaModel describes the quota of an organization.
+type OrganizationQuotaModel struct {
+	OrganizationID uint `gorm:"primary_key;auto_increment:false"`
+
+	// Limits contains the JSON encoded limits of the organization
+	Limits string `sql:"type:text;"`
+
+	UpdatedAt time.Time
+	UpdatedBy uint
+}
+
+// TableName changes the default table name.
+func (OrganizationQuotaModel) TableName() string {
+	return organizationQuotasTableName
+}
@@ -0,0 +1,86 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Quotas acts as a repository for the quotas of organizations.
+type Quotas struct {
+	db *gorm.DB
+}
+
+// NewQuotas returns a new Quotas instance.
+func NewQuotas(db *gorm.DB) *Quotas {
+	return &Quotas{db: db}
+}
+
+// FindByOrganization returns the quota of an organization or nil if the organization has no quota.
+func (q *Quotas) FindByOrganization(organizationID uint) (*OrganizationQuotaModel, error) {
+	var quota OrganizationQuotaModel
+
+	err := q.db.Where(OrganizationQuotaModel{OrganizationID: organizationID}).First(&quota).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.With(errors.Wrap(err, "could not fetch organization quota"), "organization", organizationID)
+	}
+
+	return &quota, nil
+}
+
+// Lock locks the quota of an organization until the returned function is called.
+// Quota checks holding the lock are serialized, plain reads of the quota are not blocked.
+// Organizations without a quota are not locked.
+func (q *Quotas) Lock(organizationID uint) (func(), error) {
+	tx := q.db.Begin()
+
+	var quota OrganizationQuotaModel
+	err := tx.Set("gorm:query_option", "FOR UPDATE").Where(OrganizationQuotaModel{OrganizationID: organizationID}).First(&quota).Error
+	if gorm.IsRecordNotFoundError(err) {
+		tx.Rollback()
+
+		return func() {}, nil
+	} else if err != nil {
+		tx.Rollback()
+
+		return nil, emperror.With(errors.Wrap(err, "could not lock organization quota"), "organization", organizationID)
+	}
+
+	return func() { tx.Commit() }, nil
+}
+
+// Save creates or replaces the quota of an organization.
+func (q *Quotas) Save(quota *OrganizationQuotaModel) error {
+	err := q.db.Save(quota).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not save organization quota"), "organization", quota.OrganizationID)
+	}
+
+	return nil
+}
+
+// Delete removes the quota of an organization.
+func (q *Quotas) Delete(organizationID uint) error {
+	err := q.db.Delete(OrganizationQuotaModel{OrganizationID: organizationID}).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not delete organization quota"), "organization", organizationID)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// TableName constants
+const (
+	secretInstallationsTableName    = "secret_installations"
+	secretRotationPoliciesTableName = "secret_rotation_policies"
+	secretRotationsTableName        = "secret_rotations"
+	secretRotationClustersTableName = "secret_rotation_clusters"
+)
+
+// SecretInstallationModel describes a Kubernetes secret of a cluster created from (or merged with) a secret.
+type SecretInstallationModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"index:idx_secret_installation_secret;not null"`
+	SecretID       string `gorm:"index:idx_secret_installation_secret;not null"`
+	ClusterID      uint   `gorm:"unique_index:idx_secret_installation_cluster_secret;not null"`
+	Namespace      string `gorm:"unique_index:idx_secret_installation_cluster_secret"`
+	Name           string `gorm:"unique_index:idx_secret_installation_cluster_secret"`
+
+	// Spec contains the JSON encoded spec of the Kubernetes secret
+	Spec string `sql:"type:text;"`
+
+	// Merge is set when the secret was merged with an already existing Kubernetes secret
+	Merge bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (SecretInstallationModel) TableName() string {
+	return secretInstallationsTableName
+}
+
+// SecretRotationPolicyModel describes how often the values of a secret are regenerated.
+type SecretRotationPolicyModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"unique_index:idx_secret_rotation_policy_secret;not null"`
+	SecretID       string `gorm:"unique_index:idx_secret_rotation_policy_secret"`
+
+	IntervalDays   int
+	LastRotatedAt  *time.Time
+	NextRotationAt time.Time `gorm:"index"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (SecretRotationPolicyModel) TableName() string {
+	return secretRotationPoliciesTableName
+}
+
+// SecretRotationPolicy returns the policy described by the model.
+func (m *SecretRotationPolicyModel) SecretRotationPolicy() pkgCluster.SecretRotationPolicy {
+	return pkgCluster.SecretRotationPolicy{
+		IntervalDays: m.IntervalDays,
+	}
+}
+
+// ConvertModelToEntity converts a SecretRotationPolicyModel to an API response.
+func (m *SecretRotationPolicyModel) ConvertModelToEntity() *pkgCluster.SecretRotationPolicyResponse {
+	return &pkgCluster.SecretRotationPolicyResponse{
+		SecretID:       m.SecretID,
+		IntervalDays:   m.IntervalDays,
+		LastRotatedAt:  m.LastRotatedAt,
+		NextRotationAt: m.NextRotationAt,
+		CreatedAt:      m.CreatedAt,
+		CreatedBy:      m.CreatedBy,
+	}
+}
+
+// SecretRotationModel describes a rotation of a secret and its propagation to clusters.
+type SecretRotationModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"index:idx_secret_rotation_secret;not null"`
+	SecretID       string `gorm:"index:idx_secret_rotation_secret;not null"`
+
+	Version int
+	State   string
+	Actor   uint
+	Error   string `sql:"type:text;"`
+
+	StartedAt  time.Time
+	FinishedAt *time.Time
+
+	Clusters []SecretRotationClusterModel `gorm:"foreignkey:RotationID"`
+}
+
+// TableName changes the default table name.
+func (SecretRotationModel) TableName() string {
+	return secretRotationsTableName
+}
+
+// SecretRotationClusterModel describes the propagation of a rotated secret to a Kubernetes secret of a cluster.
+type SecretRotationClusterModel struct {
+	ID         uint `gorm:"primary_key"`
+	RotationID uint `gorm:"index;not null"`
+
+	ClusterID uint
+	Namespace string
+	Name      string
+	State     string
+	Error     string `sql:"type:text;"`
+}
+
+// TableName changes the default table name.
+func (SecretRotationClusterModel) TableName() string {
+	return secretRotationClustersTableName
+}
+
+// ConvertModelToEntity converts a SecretRotationModel to an API response.
+func (m *SecretRotationModel) ConvertModelToEntity() *pkgCluster.SecretRotationResponse {
+	response := &pkgCluster.SecretRotationResponse{
+		ID:         m.ID,
+		SecretID:   m.SecretID,
+		Version:    m.Version,
+		State:      m.State,
+		Actor:      m.Actor,
+		StartedAt:  m.StartedAt,
+		FinishedAt: m.FinishedAt,
+		Error:      m.Error,
+	}
+
+	for _, c := range m.Clusters {
+		response.Clusters = append(response.Clusters, pkgCluster.SecretRotationClusterResponse{
+			ClusterID: c.ClusterID,
+			Namespace: c.Namespace,
+			Name:      c.Name,
+			State:     c.State,
+			Error:     c.Error,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,257 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// SecretRotations acts as a repository for secret rotation policies, rotations
+// and the installations of secrets to clusters the rotated values are propagated to.
+type SecretRotations struct {
+	db *gorm.DB
+}
+
+// NewSecretRotations returns a new SecretRotations instance.
+func NewSecretRotations(db *gorm.DB) *SecretRotations {
+	return &SecretRotations{db: db}
+}
+
+// SaveInstallation records an installation of a secret to a cluster.
+// An earlier installation to the same Kubernetes secret is replaced.
+func (r *SecretRotations) SaveInstallation(installation *SecretInstallationModel) error {
+	var existing SecretInstallationModel
+
+	err := r.db.Where(SecretInstallationModel{
+		ClusterID: installation.ClusterID,
+		Namespace: installation.Namespace,
+		Name:      installation.Name,
+	}).First(&existing).Error
+	if err == nil {
+		installation.ID = existing.ID
+		installation.CreatedAt = existing.CreatedAt
+	} else if !gorm.IsRecordNotFoundError(err) {
+		return emperror.With(
+			errors.Wrap(err, "could not get secret installation"),
+			"cluster", installation.ClusterID,
+			"secret", installation.Name,
+		)
+	}
+
+	err = r.db.Save(installation).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not save secret installation"),
+			"cluster", installation.ClusterID,
+			"secret", installation.Name,
+		)
+	}
+
+	return nil
+}
+
+// FindInstallationsBySecret returns the installations of a secret to clusters.
+func (r *SecretRotations) FindInstallationsBySecret(organizationID uint, secretID string) ([]*SecretInstallationModel, error) {
+	var installations []*SecretInstallationModel
+
+	err := r.db.Where(SecretInstallationModel{OrganizationID: organizationID, SecretID: secretID}).Order("id").Find(&installations).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch secret installations"),
+			"organization", organizationID,
+			"secret", secretID,
+		)
+	}
+
+	return installations, nil
+}
+
+// DeleteInstallationsByClusterID deletes the secret installations of a cluster.
+func (r *SecretRotations) DeleteInstallationsByClusterID(clusterID uint) error {
+	err := r.db.Where("cluster_id = ?", clusterID).Delete(SecretInstallationModel{}).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not delete secret installations"), "cluster", clusterID)
+	}
+
+	return nil
+}
+
+type secretRotationPolicyNotFoundError struct {
+	organizationID uint
+	secretID       string
+}
+
+func (e *secretRotationPolicyNotFoundError) Error() string {
+	return "secret rotation policy not found"
+}
+
+func (e *secretRotationPolicyNotFoundError) Context() []interface{} {
+	return []interface{}{
+		"organization", e.organizationID,
+		"secret", e.secretID,
+	}
+}
+
+func (e *secretRotationPolicyNotFoundError) NotFound() bool {
+	return true
+}
+
+// FindPolicy returns the rotation policy of a secret.
+func (r *SecretRotations) FindPolicy(organizationID uint, secretID string) (*SecretRotationPolicyModel, error) {
+	policy := SecretRotationPolicyModel{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+	}
+
+	err := r.db.Where(policy).First(&policy).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, errors.WithStack(&secretRotationPolicyNotFoundError{
+			organizationID: organizationID,
+			secretID:       secretID,
+		})
+	} else if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not get secret rotation policy"),
+			"organization", organizationID,
+			"secret", secretID,
+		)
+	}
+
+	return &policy, nil
+}
+
+// FindDuePolicies returns the rotation policies of secrets due to be rotated.
+func (r *SecretRotations) FindDuePolicies(now time.Time) ([]*SecretRotationPolicyModel, error) {
+	var policies []*SecretRotationPolicyModel
+
+	err := r.db.Where("next_rotation_at <= ?", now).Order("next_rotation_at").Find(&policies).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch due secret rotation policies")
+	}
+
+	return policies, nil
+}
+
+// SavePolicy creates or updates the rotation policy of a secret.
+func (r *SecretRotations) SavePolicy(policy *SecretRotationPolicyModel) error {
+	err := r.db.Save(policy).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not save secret rotation policy"),
+			"organization", policy.OrganizationID,
+			"secret", policy.SecretID,
+		)
+	}
+
+	return nil
+}
+
+// DeletePolicy deletes the rotation policy of a secret.
+func (r *SecretRotations) DeletePolicy(organizationID uint, secretID string) error {
+	result := r.db.
+		Where("organization_id = ? AND secret_id = ?", organizationID, secretID).
+		Delete(SecretRotationPolicyModel{})
+	if result.Error != nil {
+		return emperror.With(
+			errors.Wrap(result.Error, "could not delete secret rotation policy"),
+			"organization", organizationID,
+			"secret", secretID,
+		)
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.WithStack(&secretRotationPolicyNotFoundError{
+			organizationID: organizationID,
+			secretID:       secretID,
+		})
+	}
+
+	return nil
+}
+
+// ClaimPolicy records a due rotation of a secret unless it has already been recorded (eg. by another Pipeline instance).
+func (r *SecretRotations) ClaimPolicy(policy *SecretRotationPolicyModel, now time.Time) (bool, error) {
+	next := now.Add(policy.SecretRotationPolicy().Interval())
+
+	result := r.db.Model(SecretRotationPolicyModel{}).
+		Where("id = ? AND next_rotation_at <= ?", policy.ID, now).
+		Updates(map[string]interface{}{
+			"last_rotated_at":  now,
+			"next_rotation_at": next,
+		})
+	if result.Error != nil {
+		return false, emperror.With(errors.Wrap(result.Error, "could not claim secret rotation"), "policy", policy.ID)
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	policy.LastRotatedAt = &now
+	policy.NextRotationAt = next
+
+	return true, nil
+}
+
+// CreateRotation persists a new rotation of a secret.
+func (r *SecretRotations) CreateRotation(rotation *SecretRotationModel) error {
+	err := r.db.Create(rotation).Error
+	if err != nil {
+		return emperror.With(
+			errors.Wrap(err, "could not create secret rotation"),
+			"organization", rotation.OrganizationID,
+			"secret", rotation.SecretID,
+		)
+	}
+
+	return nil
+}
+
+// SaveRotation updates an existing rotation of a secret together with its results per cluster.
+func (r *SecretRotations) SaveRotation(rotation *SecretRotationModel) error {
+	err := r.db.Save(rotation).Error
+	if err != nil {
+		return emperror.With(errors.Wrap(err, "could not save secret rotation"), "rotation", rotation.ID)
+	}
+
+	return nil
+}
+
+// FindRotationsBySecret returns the rotations of a secret, the latest first.
+func (r *SecretRotations) FindRotationsBySecret(organizationID uint, secretID string) ([]*SecretRotationModel, error) {
+	var rotations []*SecretRotationModel
+
+	query := SecretRotationModel{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+	}
+
+	err := r.db.Where(query).Order("started_at desc, id desc").Preload("Clusters", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Find(&rotations).Error
+	if err != nil {
+		return nil, emperror.With(
+			errors.Wrap(err, "could not fetch secret rotations"),
+			"organization", organizationID,
+			"secret", secretID,
+		)
+	}
+
+	return rotations, nil
+}
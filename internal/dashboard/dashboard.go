@@ -24,7 +24,6 @@ import (
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/config"
-	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/k8sutil"
@@ -76,18 +75,19 @@ type Status struct {
 }
 
 type Cluster struct {
-	Name                string    `json:"name"`
-	Id                  string    `json:"id"`
-	Status              string    `json:"status"`
-	Distribution        string    `json:"distribution"`
-	StatusMessage       string    `json:"statusMessage"`
-	Cloud               string    `json:"cloud"`
-	CreatedAt           time.Time `json:"createdAt"`
-	Region              string    `json:"region"`
-	Nodes               []Node    `json:"nodes"`
-	CpuUsagePercent     float64   `json:"cpuUsagePercent"`
-	StorageUsagePercent float64   `json:"storageUsagePercent"`
-	MemoryUsagePercent  float64   `json:"memoryUsagePercent"`
+	Name                string            `json:"name"`
+	Id                  string            `json:"id"`
+	Status              string            `json:"status"`
+	Distribution        string            `json:"distribution"`
+	StatusMessage       string            `json:"statusMessage"`
+	Cloud               string            `json:"cloud"`
+	CreatedAt           time.Time         `json:"createdAt"`
+	Region              string            `json:"region"`
+	Nodes               []Node            `json:"nodes"`
+	CpuUsagePercent     float64           `json:"cpuUsagePercent"`
+	StorageUsagePercent float64           `json:"storageUsagePercent"`
+	MemoryUsagePercent  float64           `json:"memoryUsagePercent"`
+	Labels              map[string]string `json:"labels,omitempty"`
 }
 
 // GetDashboardResponse Api object to be mapped to Get dashboard request
@@ -126,11 +126,11 @@ func GetDashboard(c *gin.Context) {
 
 	// TODO: move these to a struct and create them only once upon application init
 	secretValidator := providers.NewSecretValidator(secret.Store)
-	clusterManager := cluster.NewManager(intCluster.NewClusters(config.DB()), secretValidator, cluster.NewNopClusterEvents(), log, errorHandler)
+	clusterManager := cluster.NewManager(cluster.NewRepositories(config.DB()), secretValidator, cluster.NewNopClusterEvents(), config.PriceCatalog(), log, errorHandler)
 
 	logger.Info("fetching clusters")
 
-	clusters, err := clusterManager.GetClusters(context.Background(), organizationID)
+	clusters, err := clusterManager.ListClusters(context.Background(), organizationID, c.Query("labelSelector"))
 	if err != nil {
 		logger.Errorf("error listing clusters: %s", err.Error())
 		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
@@ -150,7 +150,8 @@ func GetDashboard(c *gin.Context) {
 		if err == nil {
 			if strings.ToUpper(status.Status) == "RUNNING" {
 				logger := logger.WithField("cluster", c.GetName())
-				go getClusterDashboard(logger, c, clusterResponseChan)
+
+				go getClusterDashboard(logger, c.CommonCluster, c.Metadata.Labels, clusterResponseChan)
 				i++
 			}
 		}
@@ -167,7 +168,7 @@ func GetDashboard(c *gin.Context) {
 
 }
 
-func getClusterDashboard(logger *logrus.Entry, commonCluster cluster.CommonCluster, clusterResponseChan chan Cluster) {
+func getClusterDashboard(logger *logrus.Entry, commonCluster cluster.CommonCluster, labels map[string]string, clusterResponseChan chan Cluster) {
 	nodeStates := make([]Node, 0)
 	cluster := Cluster{
 		Name:         commonCluster.GetName(),
@@ -175,6 +176,7 @@ func getClusterDashboard(logger *logrus.Entry, commonCluster cluster.CommonClust
 		Distribution: commonCluster.GetDistribution(),
 		Cloud:        commonCluster.GetCloud(),
 		Nodes:        nodeStates,
+		Labels:       labels,
 	}
 	kubeConfig, err := commonCluster.GetK8sConfig()
 	if err != nil {
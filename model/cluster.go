@@ -26,6 +26,7 @@ import (
 	"github.com/banzaicloud/pipeline/secret"
 	"github.com/banzaicloud/pipeline/utils"
 	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
 	"github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -43,36 +44,77 @@ const (
 	TableNameAzureNodePools       = "azure_aks_node_pools"
 	TableNameDummyProperties      = "dummy_clusters"
 	TableNameKubernetesProperties = "kubernetes_clusters"
+	TableNameClusterLabels        = "cluster_labels"
+	TableNameClusterExpirations   = "cluster_expirations"
+	TableNameDeletionProtections  = "cluster_deletion_protections"
+	TableNameClusterStatusHistory = "cluster_status_history"
 )
 
 //ClusterModel describes the common cluster model
 // Note: this model is being moved to github.com/banzaicloud/pipeline/pkg/model.ClusterModel
 type ClusterModel struct {
-	ID             uint   `gorm:"primary_key"`
-	UID            string `gorm:"unique_index:idx_uid"`
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	DeletedAt      *time.Time `gorm:"unique_index:idx_unique_id" sql:"index"`
-	Name           string     `gorm:"unique_index:idx_unique_id"`
-	Location       string
-	Cloud          string
-	Distribution   string
-	OrganizationId uint `gorm:"unique_index:idx_unique_id"`
-	SecretId       string
-	ConfigSecretId string
-	SshSecretId    string
-	Status         string
-	RbacEnabled    bool
-	Monitoring     bool
-	Logging        bool
-	StatusMessage  string                 `sql:"type:text;"`
-	ACSK           ACSKClusterModel       `gorm:"foreignkey:ID"`
-	AKS            AKSClusterModel        `gorm:"foreignkey:ID"`
-	EKS            EKSClusterModel        `gorm:"foreignkey:ID"`
-	Dummy          DummyClusterModel      `gorm:"foreignkey:ID"`
-	Kubernetes     KubernetesClusterModel `gorm:"foreignkey:ID"`
-	OKE            modelOracle.Cluster
-	CreatedBy      uint
+	ID                 uint   `gorm:"primary_key"`
+	UID                string `gorm:"unique_index:idx_uid"`
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	DeletedAt          *time.Time `gorm:"unique_index:idx_unique_id" sql:"index"`
+	Name               string     `gorm:"unique_index:idx_unique_id"`
+	Location           string
+	Cloud              string
+	Distribution       string
+	OrganizationId     uint `gorm:"unique_index:idx_unique_id"`
+	SecretId           string
+	ConfigSecretId     string
+	SshSecretId        string
+	Status             string
+	RbacEnabled        bool
+	Monitoring         bool
+	Logging            bool
+	StatusMessage      string                 `sql:"type:text;"`
+	ACSK               ACSKClusterModel       `gorm:"foreignkey:ID"`
+	AKS                AKSClusterModel        `gorm:"foreignkey:ID"`
+	EKS                EKSClusterModel        `gorm:"foreignkey:ID"`
+	Dummy              DummyClusterModel      `gorm:"foreignkey:ID"`
+	Kubernetes         KubernetesClusterModel `gorm:"foreignkey:ID"`
+	OKE                modelOracle.Cluster
+	Labels             []*ClusterLabelModel     `gorm:"foreignkey:ClusterID;save_associations:false"`
+	Expiration         *ClusterExpirationModel  `gorm:"foreignkey:ClusterID;save_associations:false"`
+	DeletionProtection *DeletionProtectionModel `gorm:"foreignkey:ClusterID;save_associations:false"`
+	CreatedBy          uint
+}
+
+// ClusterLabelModel describes a key/value label of a cluster
+type ClusterLabelModel struct {
+	ID        uint   `gorm:"primary_key"`
+	ClusterID uint   `gorm:"unique_index:idx_cluster_label_key;not null"`
+	Key       string `gorm:"unique_index:idx_cluster_label_key"`
+	Value     string
+}
+
+// ClusterExpirationModel describes when an ephemeral cluster expires
+type ClusterExpirationModel struct {
+	ID        uint      `gorm:"primary_key"`
+	ClusterID uint      `gorm:"unique_index:idx_cluster_expiration_cluster_id;not null"`
+	ExpiresAt time.Time `gorm:"index:idx_cluster_expiration_expires_at"`
+	WarnedAt  *time.Time
+}
+
+// DeletionProtectionModel marks a cluster protected from being deleted
+type DeletionProtectionModel struct {
+	ID        uint `gorm:"primary_key"`
+	ClusterID uint `gorm:"unique_index:idx_cluster_deletion_protection_cluster_id;not null"`
+	CreatedAt time.Time
+	CreatedBy uint
+}
+
+// ClusterStatusHistoryModel records a status transition of a cluster
+type ClusterStatusHistoryModel struct {
+	ID            uint `gorm:"primary_key"`
+	ClusterID     uint `gorm:"index:idx_cluster_status_history_cluster_id;not null"`
+	FromStatus    string
+	ToStatus      string
+	StatusMessage string `sql:"type:text;"`
+	CreatedAt     time.Time
 }
 
 // ACSKNodePoolModel describes Alibaba Cloud CS node groups model of a cluster
@@ -149,6 +191,7 @@ type AKSNodePoolModel struct {
 	NodeMaxCount     int
 	Count            int
 	NodeInstanceType string
+	Version          string
 }
 
 // DummyClusterModel describes the dummy cluster model
@@ -156,6 +199,8 @@ type DummyClusterModel struct {
 	ID                uint `gorm:"primary_key"`
 	KubernetesVersion string
 	NodeCount         int
+	NodePools         string `sql:"type:text;"` // JSON encoded simulated node pools
+	Simulation        string `sql:"type:text;"` // JSON encoded simulation settings
 }
 
 //KubernetesClusterModel describes the build your own cluster model
@@ -245,6 +290,62 @@ func (ClusterModel) TableName() string {
 	return TableNameClusters
 }
 
+// TableName sets ClusterLabelModel's table name
+func (ClusterLabelModel) TableName() string {
+	return TableNameClusterLabels
+}
+
+// TableName sets ClusterStatusHistoryModel's table name
+func (ClusterStatusHistoryModel) TableName() string {
+	return TableNameClusterStatusHistory
+}
+
+// ConvertModelToEntity converts a status transition to its API representation
+func (m *ClusterStatusHistoryModel) ConvertModelToEntity() *pkgCluster.StatusHistoryItem {
+	return &pkgCluster.StatusHistoryItem{
+		FromStatus:    m.FromStatus,
+		ToStatus:      m.ToStatus,
+		StatusMessage: m.StatusMessage,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+// TableName sets ClusterExpirationModel's table name
+func (ClusterExpirationModel) TableName() string {
+	return TableNameClusterExpirations
+}
+
+// TableName sets DeletionProtectionModel's table name
+func (DeletionProtectionModel) TableName() string {
+	return TableNameDeletionProtections
+}
+
+// IsDeletionProtected tells whether the cluster is protected from being deleted
+func (cs *ClusterModel) IsDeletionProtected() bool {
+	return cs.DeletionProtection != nil
+}
+
+// GetExpiresAt returns the expiration time of the cluster or nil if the cluster does not expire
+func (cs *ClusterModel) GetExpiresAt() *time.Time {
+	if cs.Expiration == nil {
+		return nil
+	}
+
+	expiresAt := cs.Expiration.ExpiresAt
+
+	return &expiresAt
+}
+
+// GetLabels returns the labels of the cluster as a map
+func (cs *ClusterModel) GetLabels() map[string]string {
+	labels := make(map[string]string, len(cs.Labels))
+	for _, label := range cs.Labels {
+		labels[label.Key] = label.Value
+	}
+
+	return labels
+}
+
 // String method prints formatted cluster fields
 func (cs *ClusterModel) String() string {
 	var buffer bytes.Buffer
@@ -343,9 +444,101 @@ func (a *EKSClusterModel) AfterUpdate(scope *gorm.Scope) error {
 
 // UpdateStatus updates the model's status and status message in database
 func (cs *ClusterModel) UpdateStatus(status, statusMessage string) error {
-	cs.Status = status
-	cs.StatusMessage = statusMessage
-	return cs.Save()
+	return SaveClusterWithStatus(config.DB(), cs.ID, cs.Status, cs.StatusMessage, status, statusMessage, func() (uint, error) {
+		cs.Status = status
+		cs.StatusMessage = statusMessage
+		err := cs.Save()
+
+		return cs.ID, err
+	})
+}
+
+// SaveClusterWithStatus changes the status of a cluster through the cluster state machine around saving it.
+// The save function sets the new status on the model, stores it and returns the ID of the stored cluster.
+func SaveClusterWithStatus(
+	db *gorm.DB,
+	clusterID uint,
+	from string,
+	fromMessage string,
+	status string,
+	statusMessage string,
+	save func() (uint, error),
+) error {
+	created := clusterID == 0
+
+	if created {
+		// the cluster is stored for the first time, the transition can only be recorded afterwards
+		if err := pkgCluster.ValidateStatusTransition("", status); err != nil {
+			return err
+		}
+	} else if from != status || fromMessage != statusMessage {
+		if err := TransitionClusterStatus(db, clusterID, from, status, statusMessage); err != nil {
+			return err
+		}
+	}
+
+	clusterID, err := save()
+	if err != nil {
+		return err
+	}
+
+	if created {
+		return TransitionClusterStatus(db, clusterID, "", status, statusMessage)
+	}
+
+	return nil
+}
+
+// TransitionClusterStatus changes the status of a stored cluster if the transition is legal and records it in the status history.
+// The status is only changed if it is still the one the transition starts from,
+// so that conflicting operations cannot overwrite each other's status.
+// Transitions from the empty status are only recorded, since new clusters are stored with their initial status.
+func TransitionClusterStatus(db *gorm.DB, clusterID uint, from string, to string, statusMessage string) error {
+	if err := pkgCluster.ValidateStatusTransition(from, to); err != nil {
+		return err
+	}
+
+	tx := db.Begin()
+
+	if from != "" {
+		result := tx.Table(TableNameClusters).
+			Where("id = ? AND status = ? AND deleted_at IS NULL", clusterID, from).
+			Updates(map[string]interface{}{
+				"status":         to,
+				"status_message": statusMessage,
+				"updated_at":     time.Now(),
+			})
+		if result.Error != nil {
+			tx.Rollback()
+
+			return errors.Wrap(result.Error, "could not update cluster status")
+		}
+
+		if result.RowsAffected == 0 {
+			tx.Rollback()
+
+			return &pkgCluster.StatusTransitionError{From: from, To: to, Conflict: true}
+		}
+	}
+
+	history := ClusterStatusHistoryModel{
+		ClusterID:     clusterID,
+		FromStatus:    from,
+		ToStatus:      to,
+		StatusMessage: statusMessage,
+	}
+
+	if err := tx.Create(&history).Error; err != nil {
+		tx.Rollback()
+
+		return errors.Wrap(err, "could not record cluster status transition")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return errors.Wrap(err, "could not update cluster status")
+	}
+
+	return nil
 }
 
 // UpdateConfigSecret updates the model's config secret id in database
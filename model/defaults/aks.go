@@ -20,6 +20,43 @@ import (
 	"github.com/banzaicloud/pipeline/pkg/cluster/aks"
 )
 
+func init() {
+	RegisterProfileProvider(ProfileProvider{
+		Cloud:        pkgCluster.Azure,
+		Distribution: pkgCluster.AKS,
+		DefaultProfile: func() ClusterProfile {
+			return &AKSProfile{
+				DefaultModel: DefaultModel{Name: GetDefaultProfileName()},
+				NodePools: []*AKSNodePoolProfile{{
+					NodeName: DefaultNodeName,
+				}},
+			}
+		},
+		NewProfile: func() ClusterProfile {
+			return &AKSProfile{}
+		},
+		GetProfiles: func() ([]ClusterProfile, error) {
+			var aksProfiles []AKSProfile
+			config.DB().Find(&aksProfiles)
+
+			var profiles []ClusterProfile
+			for i := range aksProfiles {
+				profiles = append(profiles, &aksProfiles[i])
+			}
+
+			return profiles, nil
+		},
+		GetProfile: func(name string) (ClusterProfile, error) {
+			var aksProfile AKSProfile
+			if err := config.DB().Where(AKSProfile{DefaultModel: DefaultModel{Name: name}}).First(&aksProfile).Error; err != nil {
+				return nil, err
+			}
+
+			return &aksProfile, nil
+		},
+	})
+}
+
 // AKSProfile describes an Azure cluster profile
 type AKSProfile struct {
 	DefaultModel
@@ -16,13 +16,13 @@ package defaults
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/banzaicloud/pipeline/config"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
-	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
 	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
-	oracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/model"
 	"github.com/spf13/viper"
 )
 
@@ -88,118 +88,108 @@ func save(i interface{}) error {
 	return database.Save(i).Error
 }
 
-// GetDefaultProfiles returns all types of clouds with default profile name.
-func GetDefaultProfiles() []ClusterProfile {
-	return []ClusterProfile{
-		&EKSProfile{
-			DefaultModel: DefaultModel{Name: GetDefaultProfileName()},
-			NodePools: []*EKSNodePoolProfile{{
-				AmazonNodePoolProfileBaseFields: AmazonNodePoolProfileBaseFields{
-					NodeName:  DefaultNodeName,
-					SpotPrice: eks.DefaultSpotPrice,
-				},
-				Image: eks.DefaultImages[eks.DefaultRegion],
-			}},
-		},
-		&AKSProfile{
-			DefaultModel: DefaultModel{Name: GetDefaultProfileName()},
-			NodePools: []*AKSNodePoolProfile{{
-				NodeName: DefaultNodeName,
-			}},
-		},
-		&GKEProfile{
-			DefaultModel: DefaultModel{Name: GetDefaultProfileName()},
-			NodePools: []*GKENodePoolProfile{{
-				NodeName: DefaultNodeName,
-			}},
-		},
-		&oracle.Profile{
-			Name: GetDefaultProfileName(),
-			NodePools: []*oracle.ProfileNodePool{{
-				Name: DefaultNodeName,
-			}},
-		},
-	}
-}
-
-// GetAllProfiles loads all saved cluster profile from database by given cloud type
-func GetAllProfiles(distribution string) ([]ClusterProfile, error) {
+// ProfileProvider creates and loads the cluster profiles of a distribution
+type ProfileProvider struct {
+	// Cloud and Distribution identify the clusters the profiles are used for
+	Cloud        string
+	Distribution string
 
-	var defaults []ClusterProfile
-	db := config.DB()
+	// DefaultProfile returns the profile saved into the database on startup
+	DefaultProfile func() ClusterProfile
 
-	switch distribution {
+	// NewProfile returns an empty profile
+	NewProfile func() ClusterProfile
 
-	case pkgCluster.EKS:
-		var eksProfiles []EKSProfile
-		db.Find(&eksProfiles)
-		for i := range eksProfiles {
-			defaults = append(defaults, &eksProfiles[i])
-		}
+	// GetProfiles loads all saved profiles from the database
+	GetProfiles func() ([]ClusterProfile, error)
 
-	case pkgCluster.AKS:
-		var aksProfiles []AKSProfile
-		db.Find(&aksProfiles)
-		for i := range aksProfiles {
-			defaults = append(defaults, &aksProfiles[i])
-		}
+	// GetProfile loads a saved profile from the database by its name
+	GetProfile func(name string) (ClusterProfile, error)
+}
 
-	case pkgCluster.GKE:
-		var gkeProfiles []GKEProfile
-		db.Find(&gkeProfiles)
-		for i := range gkeProfiles {
-			defaults = append(defaults, &gkeProfiles[i])
-		}
+var (
+	profileProvidersMu sync.RWMutex
+	profileProviders   = make(map[string]ProfileProvider)
+)
 
-	case pkgCluster.OKE:
-		okeProfiles := oracle.GetProfiles()
-		for i := range okeProfiles {
-			defaults = append(defaults, &okeProfiles[i])
-		}
+// RegisterProfileProvider makes the cluster profiles of a distribution available.
+// It panics if a provider is registered twice for the same distribution.
+func RegisterProfileProvider(provider ProfileProvider) {
+	profileProvidersMu.Lock()
+	defer profileProvidersMu.Unlock()
 
-	default:
-		return nil, pkgErrors.ErrorNotSupportedCloudType
+	if _, ok := profileProviders[provider.Distribution]; ok {
+		panic(fmt.Sprintf("defaults: profile provider of %s is registered twice", provider.Distribution))
 	}
 
-	return defaults, nil
+	profileProviders[provider.Distribution] = provider
+}
+
+// GetProfileProvider returns the profile provider of a distribution
+func GetProfileProvider(distribution string) (ProfileProvider, error) {
+	profileProvidersMu.RLock()
+	defer profileProvidersMu.RUnlock()
 
+	provider, ok := profileProviders[distribution]
+	if !ok {
+		return ProfileProvider{}, pkgErrors.ErrorNotSupportedCloudType
+	}
+
+	return provider, nil
 }
 
-// GetProfile finds cluster profile from database by given name and cloud type
-func GetProfile(distribution string, name string) (ClusterProfile, error) {
-	db := config.DB()
+// GetCloudProfileProvider returns the profile provider of a cloud type
+func GetCloudProfileProvider(cloud string) (ProfileProvider, error) {
+	profileProvidersMu.RLock()
+	defer profileProvidersMu.RUnlock()
 
-	switch distribution {
-	case pkgCluster.EKS:
-		var eksProfile EKSProfile
-		if err := db.Where(EKSProfile{DefaultModel: DefaultModel{Name: name}}).First(&eksProfile).Error; err != nil {
-			return nil, err
+	for _, provider := range profileProviders {
+		if provider.Cloud == cloud {
+			return provider, nil
 		}
-		return &eksProfile, nil
+	}
 
-	case pkgCluster.AKS:
-		var aksProfile AKSProfile
-		if err := db.Where(GKEProfile{DefaultModel: DefaultModel{Name: name}}).First(&aksProfile).Error; err != nil {
-			return nil, err
-		}
-		return &aksProfile, nil
+	return ProfileProvider{}, pkgErrors.ErrorNotSupportedCloudType
+}
 
-	case pkgCluster.GKE:
-		var gkeProfile GKEProfile
-		if err := db.Where(GKEProfile{DefaultModel: DefaultModel{Name: name}}).First(&gkeProfile).Error; err != nil {
-			return nil, err
-		}
-		return &gkeProfile, nil
+// GetDefaultProfiles returns all types of clouds with default profile name.
+func GetDefaultProfiles() []ClusterProfile {
+	profileProvidersMu.RLock()
+	defer profileProvidersMu.RUnlock()
 
-	case pkgCluster.OKE:
-		var okeProfile oracle.Profile
-		okeProfile, err := oracle.GetProfileByName(name)
-		return &okeProfile, err
+	distributions := make([]string, 0, len(profileProviders))
+	for distribution := range profileProviders {
+		distributions = append(distributions, distribution)
+	}
+
+	sort.Strings(distributions)
 
-	default:
-		return nil, pkgErrors.ErrorNotSupportedCloudType
+	defaults := make([]ClusterProfile, 0, len(distributions))
+	for _, distribution := range distributions {
+		defaults = append(defaults, profileProviders[distribution].DefaultProfile())
+	}
+
+	return defaults
+}
+
+// GetAllProfiles loads all saved cluster profile from database by given cloud type
+func GetAllProfiles(distribution string) ([]ClusterProfile, error) {
+	provider, err := GetProfileProvider(distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.GetProfiles()
+}
+
+// GetProfile finds cluster profile from database by given name and cloud type
+func GetProfile(distribution string, name string) (ClusterProfile, error) {
+	provider, err := GetProfileProvider(distribution)
+	if err != nil {
+		return nil, err
 	}
 
+	return provider.GetProfile(name)
 }
 
 // GetDefaultProfileName reads the default profile name env var
@@ -21,6 +21,47 @@ import (
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 )
 
+func init() {
+	RegisterProfileProvider(ProfileProvider{
+		Cloud:        pkgCluster.Amazon,
+		Distribution: pkgCluster.EKS,
+		DefaultProfile: func() ClusterProfile {
+			return &EKSProfile{
+				DefaultModel: DefaultModel{Name: GetDefaultProfileName()},
+				NodePools: []*EKSNodePoolProfile{{
+					AmazonNodePoolProfileBaseFields: AmazonNodePoolProfileBaseFields{
+						NodeName:  DefaultNodeName,
+						SpotPrice: eks.DefaultSpotPrice,
+					},
+					Image: eks.DefaultImages[eks.DefaultRegion],
+				}},
+			}
+		},
+		NewProfile: func() ClusterProfile {
+			return &EKSProfile{}
+		},
+		GetProfiles: func() ([]ClusterProfile, error) {
+			var eksProfiles []EKSProfile
+			config.DB().Find(&eksProfiles)
+
+			var profiles []ClusterProfile
+			for i := range eksProfiles {
+				profiles = append(profiles, &eksProfiles[i])
+			}
+
+			return profiles, nil
+		},
+		GetProfile: func(name string) (ClusterProfile, error) {
+			var eksProfile EKSProfile
+			if err := config.DB().Where(EKSProfile{DefaultModel: DefaultModel{Name: name}}).First(&eksProfile).Error; err != nil {
+				return nil, err
+			}
+
+			return &eksProfile, nil
+		},
+	})
+}
+
 // EKSProfile describes an Amazon EKS cluster profile
 type EKSProfile struct {
 	DefaultModel
@@ -20,6 +20,43 @@ import (
 	"github.com/banzaicloud/pipeline/pkg/cluster/gke"
 )
 
+func init() {
+	RegisterProfileProvider(ProfileProvider{
+		Cloud:        pkgCluster.Google,
+		Distribution: pkgCluster.GKE,
+		DefaultProfile: func() ClusterProfile {
+			return &GKEProfile{
+				DefaultModel: DefaultModel{Name: GetDefaultProfileName()},
+				NodePools: []*GKENodePoolProfile{{
+					NodeName: DefaultNodeName,
+				}},
+			}
+		},
+		NewProfile: func() ClusterProfile {
+			return &GKEProfile{}
+		},
+		GetProfiles: func() ([]ClusterProfile, error) {
+			var gkeProfiles []GKEProfile
+			config.DB().Find(&gkeProfiles)
+
+			var profiles []ClusterProfile
+			for i := range gkeProfiles {
+				profiles = append(profiles, &gkeProfiles[i])
+			}
+
+			return profiles, nil
+		},
+		GetProfile: func(name string) (ClusterProfile, error) {
+			var gkeProfile GKEProfile
+			if err := config.DB().Where(GKEProfile{DefaultModel: DefaultModel{Name: name}}).First(&gkeProfile).Error; err != nil {
+				return nil, err
+			}
+
+			return &gkeProfile, nil
+		},
+	})
+}
+
 // GKEProfile describes a Google cluster profile
 type GKEProfile struct {
 	DefaultModel
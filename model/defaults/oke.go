@@ -0,0 +1,53 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package defaults
+
+import (
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	oracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/model"
+)
+
+func init() {
+	RegisterProfileProvider(ProfileProvider{
+		Cloud:        pkgCluster.Oracle,
+		Distribution: pkgCluster.OKE,
+		DefaultProfile: func() ClusterProfile {
+			return &oracle.Profile{
+				Name: GetDefaultProfileName(),
+				NodePools: []*oracle.ProfileNodePool{{
+					Name: DefaultNodeName,
+				}},
+			}
+		},
+		NewProfile: func() ClusterProfile {
+			return &oracle.Profile{}
+		},
+		GetProfiles: func() ([]ClusterProfile, error) {
+			okeProfiles := oracle.GetProfiles()
+
+			var profiles []ClusterProfile
+			for i := range okeProfiles {
+				profiles = append(profiles, &okeProfiles[i])
+			}
+
+			return profiles, nil
+		},
+		GetProfile: func(name string) (ClusterProfile, error) {
+			okeProfile, err := oracle.GetProfileByName(name)
+
+			return &okeProfile, err
+		},
+	})
+}
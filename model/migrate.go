@@ -34,6 +34,10 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		&AKSNodePoolModel{},
 		&DummyClusterModel{},
 		&KubernetesClusterModel{},
+		&ClusterLabelModel{},
+		&ClusterExpirationModel{},
+		&DeletionProtectionModel{},
+		&ClusterStatusHistoryModel{},
 	}
 
 	var tableNames string
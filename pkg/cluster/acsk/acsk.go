@@ -24,6 +24,9 @@ type NodePool struct {
 	SystemDiskCategory string `json:"systemDiskCategory,omitempty"`
 	SystemDiskSize     int    `json:"systemDiskSize,omitempty"`
 	Count              int    `json:"count"`
+
+	// Autoscaling is not supported for ACSK node pools, requests enabling it are rejected instead of being ignored
+	Autoscaling bool `json:"autoscaling,omitempty"`
 }
 
 type NodePools map[string]*NodePool
@@ -88,6 +91,9 @@ func ValidateNodePools(nps NodePools) error {
 		if np.Count < 1 {
 			return pkgErrors.ErrorAlibabaMinNumberOfNodes
 		}
+		if np.Autoscaling {
+			return pkgErrors.ErrorAlibabaNoAutoscaling
+		}
 	}
 	return nil
 }
@@ -103,14 +103,18 @@ const (
 
 // CreateClusterRequest describes a create cluster request
 type CreateClusterRequest struct {
-	Name        string                   `json:"name" yaml:"name" binding:"required"`
-	Location    string                   `json:"location" yaml:"location"`
-	Cloud       string                   `json:"cloud" yaml:"cloud" binding:"required"`
-	SecretId    string                   `json:"secretId" yaml:"secretId"`
-	SecretName  string                   `json:"secretName" yaml:"secretName"`
-	ProfileName string                   `json:"profileName" yaml:"profileName"`
-	PostHooks   PostHooks                `json:"postHooks" yaml:"postHooks"`
-	Properties  *CreateClusterProperties `json:"properties" yaml:"properties" binding:"required"`
+	Name               string                   `json:"name" yaml:"name" binding:"required"`
+	Location           string                   `json:"location" yaml:"location"`
+	Cloud              string                   `json:"cloud" yaml:"cloud" binding:"required"`
+	SecretId           string                   `json:"secretId" yaml:"secretId"`
+	SecretName         string                   `json:"secretName" yaml:"secretName"`
+	ProfileName        string                   `json:"profileName" yaml:"profileName"`
+	PostHooks          PostHooks                `json:"postHooks" yaml:"postHooks"`
+	Labels             map[string]string        `json:"labels,omitempty" yaml:"labels,omitempty"`
+	TTL                string                   `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	ExpiresAt          *time.Time               `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	DeletionProtection bool                     `json:"deletionProtection,omitempty" yaml:"deletionProtection,omitempty"`
+	Properties         *CreateClusterProperties `json:"properties" yaml:"properties" binding:"required"`
 }
 
 // CreateClusterProperties contains the cluster flavor specific properties.
@@ -155,15 +159,18 @@ type PostHooks map[string]PostHookParam
 
 // GetClusterStatusResponse describes Pipeline's GetClusterStatus API response
 type GetClusterStatusResponse struct {
-	Status        string                     `json:"status"`
-	StatusMessage string                     `json:"statusMessage,omitempty"`
-	Name          string                     `json:"name"`
-	Location      string                     `json:"location"`
-	Cloud         string                     `json:"cloud"`
-	Distribution  string                     `json:"distribution"`
-	Version       string                     `json:"version,omitempty"`
-	ResourceID    uint                       `json:"id"`
-	NodePools     map[string]*NodePoolStatus `json:"nodePools,omitempty"`
+	Status             string                     `json:"status"`
+	StatusMessage      string                     `json:"statusMessage,omitempty"`
+	Name               string                     `json:"name"`
+	Location           string                     `json:"location"`
+	Cloud              string                     `json:"cloud"`
+	Distribution       string                     `json:"distribution"`
+	Version            string                     `json:"version,omitempty"`
+	ResourceID         uint                       `json:"id"`
+	NodePools          map[string]*NodePoolStatus `json:"nodePools,omitempty"`
+	Labels             map[string]string          `json:"labels,omitempty"`
+	ExpiresAt          *time.Time                 `json:"expiresAt,omitempty"`
+	DeletionProtection bool                       `json:"deletionProtection"`
 	pkgCommon.CreatorBaseFields
 
 	// ONLY in case of GKE
@@ -190,8 +197,10 @@ type GetClusterConfigResponse struct {
 
 // UpdateClusterRequest describes an update cluster request
 type UpdateClusterRequest struct {
-	Cloud            string `json:"cloud" binding:"required"`
-	UpdateProperties `json:"properties"`
+	Cloud              string            `json:"cloud" binding:"required"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	DeletionProtection *bool             `json:"deletionProtection,omitempty"`
+	UpdateProperties   `json:"properties"`
 }
 
 // UpdateProperties describes Pipeline's UpdateCluster request properties
@@ -204,6 +213,11 @@ type UpdateProperties struct {
 	OKE   *oke.Cluster                `json:"oke,omitempty"`
 }
 
+// IsEmpty tells whether there are no provider specific properties to update
+func (p *UpdateProperties) IsEmpty() bool {
+	return p.ACSK == nil && p.EKS == nil && p.AKS == nil && p.GKE == nil && p.Dummy == nil && p.OKE == nil
+}
+
 // String method prints formatted update request fields
 func (r *UpdateClusterRequest) String() string { // todo expand
 	var buffer bytes.Buffer
@@ -262,55 +276,8 @@ func (r *UpdateClusterRequest) String() string { // todo expand
 	return buffer.String()
 }
 
-// AddDefaults puts default values to optional field(s)
-func (r *CreateClusterRequest) AddDefaults() error {
-	switch r.Cloud {
-	case Amazon:
-		return r.Properties.CreateClusterEKS.AddDefaults(r.Location)
-	case Oracle:
-		return r.Properties.CreateClusterOKE.AddDefaults()
-	default:
-		return nil
-	}
-}
-
-// Validate checks the request fields
-func (r *CreateClusterRequest) Validate() error {
-
-	if err := r.validateMainFields(); err != nil {
-		return err
-	}
-
-	switch r.Cloud {
-	case Alibaba:
-		// alibaba validate
-		return r.Properties.CreateClusterACSK.Validate()
-	case Amazon:
-		// eks validate
-		return r.Properties.CreateClusterEKS.Validate()
-	case Azure:
-		// aks validate
-		return r.Properties.CreateClusterAKS.Validate()
-	case Google:
-		// gke validate
-		return r.Properties.CreateClusterGKE.Validate()
-	case Dummy:
-		// dummy validate
-		return r.Properties.CreateClusterDummy.Validate()
-	case Kubernetes:
-		// kubernetes validate
-		return r.Properties.CreateClusterKubernetes.Validate()
-	case Oracle:
-		// oracle validate
-		return r.Properties.CreateClusterOKE.Validate(false)
-	default:
-		// not supported cloud type
-		return pkgErrors.ErrorNotSupportedCloudType
-	}
-}
-
-// validateMainFields checks the request's main fields
-func (r *CreateClusterRequest) validateMainFields() error {
+// ValidateMainFields checks the request's main fields, the cloud specific properties are validated by the distribution of the cluster
+func (r *CreateClusterRequest) ValidateMainFields() error {
 	if r.Cloud != Kubernetes {
 		if len(r.Location) == 0 {
 			return pkgErrors.ErrorLocationEmpty
@@ -319,38 +286,8 @@ func (r *CreateClusterRequest) validateMainFields() error {
 	return nil
 }
 
-// Validate checks the request fields
-func (r *UpdateClusterRequest) Validate() error {
-
-	r.preValidate()
-
-	switch r.Cloud {
-	case Alibaba:
-		// alibaba validate
-		return r.ACSK.Validate()
-	case Amazon:
-		// eks validate
-		return r.EKS.Validate()
-	case Azure:
-		// aks validate
-		return r.AKS.Validate()
-	case Google:
-		// gke validate
-		return r.GKE.Validate()
-	case Dummy:
-		return r.Dummy.Validate()
-	case Oracle:
-		// oracle validate
-		return r.OKE.Validate(true)
-	default:
-		// not supported cloud type
-		return pkgErrors.ErrorNotSupportedCloudType
-	}
-
-}
-
-// preValidate resets other cloud type fields
-func (r *UpdateClusterRequest) preValidate() {
+// ResetOtherClouds resets the fields of other cloud types, the cloud specific properties are validated by the distribution of the cluster
+func (r *UpdateClusterRequest) ResetOtherClouds() {
 
 	switch r.Cloud {
 	case Alibaba:
@@ -472,6 +409,20 @@ type CreateClusterResponse struct {
 	ResourceID uint   `json:"id"`
 }
 
+// ### [ Apply actions ] ### //
+const (
+	ApplyCreated   = "created"
+	ApplyUpdated   = "updated"
+	ApplyUnchanged = "unchanged"
+)
+
+// ApplyClusterResponse describes Pipeline's ApplyCluster API response
+type ApplyClusterResponse struct {
+	Name       string `json:"name"`
+	ResourceID uint   `json:"id"`
+	Action     string `json:"action"`
+}
+
 // DetailsResponse describes Pipeline's GetClusterDetails API response
 type DetailsResponse struct {
 	pkgCommon.CreatorBaseFields
@@ -545,7 +496,12 @@ func (p *ClusterProfileResponse) CreateClusterRequest(createRequest *CreateClust
 		Cloud:       p.Cloud,
 		SecretId:    createRequest.SecretId,
 		ProfileName: p.Name,
+		Labels:      createRequest.Labels,
+		TTL:         createRequest.TTL,
+		ExpiresAt:   createRequest.ExpiresAt,
 		Properties:  &CreateClusterProperties{},
+
+		DeletionProtection: createRequest.DeletionProtection,
 	}
 
 	switch p.Cloud { // todo distribution???
@@ -0,0 +1,36 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// CloneClusterRequest describes Pipeline's cluster clone API request
+type CloneClusterRequest struct {
+	Name       string                       `json:"name" binding:"required"`
+	Location   string                       `json:"location,omitempty"`
+	SecretId   string                       `json:"secretId,omitempty"`
+	SecretName string                       `json:"secretName,omitempty"`
+	NodePools  map[string]*NodePoolOverride `json:"nodePools,omitempty"`
+}
+
+// NodePoolOverride describes the changes of a node pool in a cluster clone request,
+// fields which are not set are kept from the source cluster
+type NodePoolOverride struct {
+	Autoscaling  *bool  `json:"autoscaling,omitempty"`
+	Count        *int   `json:"count,omitempty"`
+	MinCount     *int   `json:"minCount,omitempty"`
+	MaxCount     *int   `json:"maxCount,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	SpotPrice    string `json:"spotPrice,omitempty"`
+	Image        string `json:"image,omitempty"`
+}
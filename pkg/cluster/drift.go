@@ -0,0 +1,43 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import "time"
+
+// ### [ Drift fields ] ### //
+const (
+	DriftFieldStatus       = "status"
+	DriftFieldVersion      = "version"
+	DriftFieldNodePool     = "nodePool"
+	DriftFieldCount        = "count"
+	DriftFieldInstanceType = "instanceType"
+)
+
+// ClusterDriftResponse describes Pipeline's cluster drift API response
+type ClusterDriftResponse struct {
+	CheckedAt *time.Time     `json:"checkedAt,omitempty"`
+	Drifted   bool           `json:"drifted"`
+	Error     string         `json:"error,omitempty"`
+	Findings  []DriftFinding `json:"findings"`
+}
+
+// DriftFinding describes a difference between the recorded and the observed state of a cluster
+type DriftFinding struct {
+	NodePool   string    `json:"nodePool,omitempty"`
+	Field      string    `json:"field"`
+	Expected   string    `json:"expected"`
+	Actual     string    `json:"actual"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
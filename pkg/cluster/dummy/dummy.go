@@ -14,9 +14,33 @@
 
 package dummy
 
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Simulated operations
+const (
+	OperationCreate  = "create"
+	OperationUpdate  = "update"
+	OperationDelete  = "delete"
+	OperationUpgrade = "upgrade"
+)
+
+// DefaultNodePoolName is the name of the node pool of dummy clusters created without node pools
+const DefaultNodePoolName = "pool1"
+
+// maxLatency is the maximum simulated latency of an operation
+const maxLatency = time.Hour
+
 // CreateClusterDummy describes Pipeline's Dummy fields of a CreateCluster request
 type CreateClusterDummy struct {
-	Node *Node `json:"node,omitempty"`
+	Node       *Node                `json:"node,omitempty" yaml:"node,omitempty"`
+	NodePools  map[string]*NodePool `json:"nodePools,omitempty" yaml:"nodePools,omitempty"`
+	Simulation *Simulation          `json:"simulation,omitempty" yaml:"simulation,omitempty"`
 }
 
 // Node describes Dummy's node fields of a CreateCluster/Update request
@@ -25,9 +49,47 @@ type Node struct {
 	Count             int    `json:"count" yaml:"count"`
 }
 
+// NodePool describes a simulated node pool of a Dummy cluster
+type NodePool struct {
+	InstanceType string `json:"instanceType,omitempty" yaml:"instanceType,omitempty"`
+	Autoscaling  bool   `json:"autoscaling" yaml:"autoscaling"`
+	MinCount     int    `json:"minCount" yaml:"minCount"`
+	MaxCount     int    `json:"maxCount" yaml:"maxCount"`
+	Count        int    `json:"count" yaml:"count"`
+	Version      string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// Simulation describes how the cloud provider behaves for a Dummy cluster
+type Simulation struct {
+	// Latencies of the operations (eg. 30s), the status of the cluster moves through the phases of an operation meanwhile
+	CreateLatency string `json:"createLatency,omitempty" yaml:"createLatency,omitempty"`
+	UpdateLatency string `json:"updateLatency,omitempty" yaml:"updateLatency,omitempty"`
+	DeleteLatency string `json:"deleteLatency,omitempty" yaml:"deleteLatency,omitempty"`
+
+	// Failures injected into the operations (create, update, delete or upgrade)
+	Failures map[string]*Failure `json:"failures,omitempty" yaml:"failures,omitempty"`
+
+	// KubernetesVersions are the versions the cluster can be upgraded to
+	KubernetesVersions []string `json:"kubernetesVersions,omitempty" yaml:"kubernetesVersions,omitempty"`
+
+	// APIServer is the address of a (fake) Kubernetes API server the kubeconfig of the cluster points to
+	APIServer string `json:"apiServer,omitempty" yaml:"apiServer,omitempty"`
+	APIToken  string `json:"apiToken,omitempty" yaml:"apiToken,omitempty"`
+}
+
+// Failure describes a failure injected into an operation
+type Failure struct {
+	Message string `json:"message" yaml:"message"`
+
+	// Count is the number of times the operation fails before succeeding, 0 means that it always fails
+	Count int `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
 // UpdateClusterDummy describes Dummy's node fields of an UpdateCluster request
 type UpdateClusterDummy struct {
-	Node *Node `json:"node,omitempty"`
+	Node       *Node                `json:"node,omitempty"`
+	NodePools  map[string]*NodePool `json:"nodePools,omitempty"`
+	Simulation *Simulation          `json:"simulation,omitempty"`
 }
 
 // Validate validates cluster create request
@@ -40,16 +102,211 @@ func (d *CreateClusterDummy) Validate() error {
 		}
 	}
 
-	return nil
+	if len(d.NodePools) == 0 {
+		d.NodePools = map[string]*NodePool{
+			DefaultNodePoolName: {Count: d.Node.Count},
+		}
+	}
+
+	if err := validateNodePools(d.NodePools); err != nil {
+		return err
+	}
+
+	d.Node.Count = NodeCount(d.NodePools)
+
+	return d.Simulation.Validate()
 }
 
 // Validate validates the update request
 func (r *UpdateClusterDummy) Validate() error {
-	if r.Node == nil {
+	if r.Node == nil && r.NodePools == nil && r.Simulation == nil {
 		r.Node = &Node{
 			KubernetesVersion: "DummyKubernetesVersion",
 			Count:             1,
 		}
 	}
+
+	if r.NodePools != nil {
+		if len(r.NodePools) == 0 {
+			return errors.New("at least one node pool is required")
+		}
+
+		if err := validateNodePools(r.NodePools); err != nil {
+			return err
+		}
+	}
+
+	return r.Simulation.Validate()
+}
+
+func validateNodePools(nodePools map[string]*NodePool) error {
+	for name, nodePool := range nodePools {
+		if nodePool == nil {
+			return errors.Errorf("node pool [%s] is empty", name)
+		}
+
+		if nodePool.Count < 0 {
+			return errors.Errorf("node count of node pool [%s] must not be negative", name)
+		}
+
+		if nodePool.Autoscaling {
+			if nodePool.MinCount < 0 || nodePool.MaxCount < nodePool.MinCount {
+				return errors.Errorf("invalid autoscaling bounds of node pool [%s]", name)
+			}
+
+			if nodePool.Count < nodePool.MinCount || nodePool.Count > nodePool.MaxCount {
+				return errors.Errorf("node count of node pool [%s] must be between its minimum and maximum count", name)
+			}
+		}
+	}
+
 	return nil
 }
+
+// Validate checks the latencies and failures of a simulation.
+func (s *Simulation) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	for _, latency := range []string{s.CreateLatency, s.UpdateLatency, s.DeleteLatency} {
+		if latency == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(latency)
+		if err != nil {
+			return errors.Wrap(err, "invalid latency")
+		}
+
+		if duration < 0 || duration > maxLatency {
+			return errors.Errorf("latency must be between 0 and %s", maxLatency)
+		}
+	}
+
+	for operation, failure := range s.Failures {
+		switch operation {
+		case OperationCreate, OperationUpdate, OperationDelete, OperationUpgrade:
+		default:
+			return errors.Errorf("failures cannot be injected into the [%s] operation", operation)
+		}
+
+		if failure == nil || failure.Count < 0 {
+			return errors.Errorf("invalid failure of the [%s] operation", operation)
+		}
+	}
+
+	return nil
+}
+
+// Latency returns the simulated latency of an operation.
+func (s *Simulation) Latency(operation string) time.Duration {
+	if s == nil {
+		return 0
+	}
+
+	var latency string
+
+	switch operation {
+	case OperationCreate:
+		latency = s.CreateLatency
+	case OperationUpdate, OperationUpgrade:
+		latency = s.UpdateLatency
+	case OperationDelete:
+		latency = s.DeleteLatency
+	}
+
+	duration, _ := time.ParseDuration(latency)
+
+	return duration
+}
+
+// Fail returns the error injected into an operation (or nil) and counts the failure.
+// The failure is removed from the simulation once it happened as many times as configured.
+func (s *Simulation) Fail(operation string) error {
+	if s == nil {
+		return nil
+	}
+
+	failure, ok := s.Failures[operation]
+	if !ok || failure == nil {
+		return nil
+	}
+
+	if failure.Count > 0 {
+		failure.Count--
+
+		if failure.Count == 0 {
+			delete(s.Failures, operation)
+		}
+	}
+
+	message := failure.Message
+	if message == "" {
+		message = fmt.Sprintf("simulated %s failure", operation)
+	}
+
+	return errors.New(message)
+}
+
+// UpdatedNodePools returns the node pools of a cluster after applying an update request.
+// Node pools missing from the request are removed, requests without node pools resize the only node pool of the cluster.
+// The instance type and version of existing node pools are kept.
+func UpdatedNodePools(current map[string]*NodePool, request *UpdateClusterDummy, version string) map[string]*NodePool {
+	updated := make(map[string]*NodePool, len(current))
+
+	if request.NodePools == nil {
+		for name, nodePool := range current {
+			np := *nodePool
+			updated[name] = &np
+		}
+
+		if request.Node != nil && len(updated) == 1 {
+			for _, nodePool := range updated {
+				if !nodePool.Autoscaling {
+					nodePool.Count = request.Node.Count
+				}
+			}
+		}
+
+		return updated
+	}
+
+	for name, nodePool := range request.NodePools {
+		np := *nodePool
+
+		if currentNodePool, ok := current[name]; ok {
+			np.InstanceType = currentNodePool.InstanceType
+			np.Version = currentNodePool.Version
+		} else if np.Version == "" {
+			np.Version = version
+		}
+
+		updated[name] = &np
+	}
+
+	return updated
+}
+
+// NodeCount returns the number of nodes of node pools.
+func NodeCount(nodePools map[string]*NodePool) int {
+	var count int
+
+	for _, nodePool := range nodePools {
+		count += nodePool.Count
+	}
+
+	return count
+}
+
+// NodePoolNames returns the sorted names of node pools.
+func NodePoolNames(nodePools map[string]*NodePool) []string {
+	names := make([]string, 0, len(nodePools))
+	for name := range nodePools {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
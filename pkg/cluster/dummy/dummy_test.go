@@ -0,0 +1,106 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dummy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimulationFail(t *testing.T) {
+	simulation := &Simulation{
+		Failures: map[string]*Failure{
+			OperationCreate: {Message: "quota exceeded", Count: 2},
+			OperationDelete: {},
+		},
+	}
+
+	tests := []struct {
+		operation string
+		message   string
+	}{
+		{operation: OperationCreate, message: "quota exceeded"},
+		{operation: OperationCreate, message: "quota exceeded"},
+		{operation: OperationCreate},
+		{operation: OperationUpdate},
+		{operation: OperationDelete, message: "simulated delete failure"},
+		{operation: OperationDelete, message: "simulated delete failure"},
+	}
+
+	for _, test := range tests {
+		err := simulation.Fail(test.operation)
+
+		if test.message == "" && err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.operation, err.Error())
+		}
+
+		if test.message != "" && (err == nil || err.Error() != test.message) {
+			t.Fatalf("%s: expected error %q, got %v", test.operation, test.message, err)
+		}
+	}
+}
+
+func TestUpdatedNodePools(t *testing.T) {
+	current := map[string]*NodePool{
+		"pool1": {InstanceType: "small", Count: 2, Version: "1.10.11"},
+		"pool2": {InstanceType: "large", Autoscaling: true, MinCount: 1, MaxCount: 3, Count: 1, Version: "1.10.11"},
+	}
+
+	tests := []struct {
+		name     string
+		current  map[string]*NodePool
+		request  *UpdateClusterDummy
+		expected map[string]*NodePool
+	}{
+		{
+			name:     "unchanged",
+			current:  current,
+			request:  &UpdateClusterDummy{},
+			expected: current,
+		},
+		{
+			name:    "resize single node pool",
+			current: map[string]*NodePool{"pool1": {Count: 2}},
+			request: &UpdateClusterDummy{Node: &Node{Count: 5}},
+			expected: map[string]*NodePool{
+				"pool1": {Count: 5},
+			},
+		},
+		{
+			name:    "add, resize and remove node pools",
+			current: current,
+			request: &UpdateClusterDummy{
+				NodePools: map[string]*NodePool{
+					"pool1": {InstanceType: "medium", Count: 4},
+					"pool3": {InstanceType: "medium", Count: 1},
+				},
+			},
+			expected: map[string]*NodePool{
+				"pool1": {InstanceType: "small", Count: 4, Version: "1.10.11"},
+				"pool3": {InstanceType: "medium", Count: 1, Version: "1.11.5"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := UpdatedNodePools(test.current, test.request, "1.11.5")
+
+			if !reflect.DeepEqual(updated, test.expected) {
+				t.Fatalf("unexpected node pools: %v", updated)
+			}
+		})
+	}
+}
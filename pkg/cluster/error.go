@@ -0,0 +1,53 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// ValidationError is returned when a request or a resource definition is invalid
+type ValidationError struct {
+	msg string
+}
+
+// NewValidationError returns a new ValidationError with the given message.
+func NewValidationError(msg string) error {
+	return &ValidationError{msg: msg}
+}
+
+func (e *ValidationError) Error() string {
+	return e.msg
+}
+
+// IsInvalid tells the API to respond with 400.
+func (e *ValidationError) IsInvalid() bool {
+	return true
+}
+
+// PreconditionFailedError is returned when an operation cannot be performed in the current state of a cluster
+type PreconditionFailedError struct {
+	msg string
+}
+
+// NewPreconditionFailedError returns a new PreconditionFailedError with the given message.
+func NewPreconditionFailedError(msg string) error {
+	return &PreconditionFailedError{msg: msg}
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return e.msg
+}
+
+// PreconditionFailed tells the API to respond with 412.
+func (e *PreconditionFailedError) PreconditionFailed() bool {
+	return true
+}
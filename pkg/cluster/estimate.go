@@ -0,0 +1,61 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// HoursPerMonth is the average number of hours in a month used for monthly cost estimates
+const HoursPerMonth = 730
+
+// EstimateClusterRequest describes a cost estimation request:
+// either a cluster to be created, or an existing cluster with an update applied to it.
+type EstimateClusterRequest struct {
+	Create    *CreateClusterRequest `json:"create,omitempty"`
+	ClusterID uint                  `json:"clusterId,omitempty"`
+	Update    *UpdateClusterRequest `json:"update,omitempty"`
+}
+
+// EstimatedNodePool describes the cost related properties of a node pool
+type EstimatedNodePool struct {
+	NodePoolPlan
+
+	// SpotPrice is the maximum hourly price of spot instances, empty for on-demand node pools
+	SpotPrice string `json:"spotPrice,omitempty"`
+}
+
+// NodePoolCostEstimate describes the estimated cost of a node pool
+type NodePoolCostEstimate struct {
+	EstimatedNodePool
+
+	Spot          bool    `json:"spot"`
+	OnDemandPrice float64 `json:"onDemandPrice"`
+	NodePrice     float64 `json:"nodePrice"`
+	HourlyCost    float64 `json:"hourlyCost"`
+	MonthlyCost   float64 `json:"monthlyCost"`
+	MinHourlyCost float64 `json:"minHourlyCost"`
+	MaxHourlyCost float64 `json:"maxHourlyCost"`
+	PriceKnown    bool    `json:"priceKnown"`
+}
+
+// ClusterCostEstimate describes Pipeline's cost estimation API response
+type ClusterCostEstimate struct {
+	Cloud         string                 `json:"cloud"`
+	Location      string                 `json:"location"`
+	Currency      string                 `json:"currency"`
+	HourlyCost    float64                `json:"hourlyCost"`
+	MonthlyCost   float64                `json:"monthlyCost"`
+	MinHourlyCost float64                `json:"minHourlyCost"`
+	MaxHourlyCost float64                `json:"maxHourlyCost"`
+	NodePools     []NodePoolCostEstimate `json:"nodePools"`
+	Warnings      []string               `json:"warnings,omitempty"`
+}
@@ -0,0 +1,29 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import "time"
+
+// ClusterExpirationRequest describes a cluster expiration change request.
+// Either a TTL (counted from the time of the request) or an absolute expiration time has to be set.
+type ClusterExpirationRequest struct {
+	TTL       string     `json:"ttl,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ClusterExpirationResponse describes when a cluster expires.
+type ClusterExpirationResponse struct {
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
@@ -0,0 +1,43 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// ### [ Cluster features ] ### //
+const (
+	FeatureMonitoring = "monitoring"
+	FeatureLogging    = "logging"
+	FeatureDashboard  = "dashboard"
+	FeatureAnchore    = "anchore"
+)
+
+// FeatureRequest describes a request enabling or disabling a feature of a cluster
+type FeatureRequest struct {
+	Enabled bool          `json:"enabled"`
+	Params  PostHookParam `json:"params,omitempty"`
+}
+
+// FeatureResponse describes a feature of a cluster and the Helm releases it consists of
+type FeatureResponse struct {
+	Name     string                   `json:"name"`
+	Enabled  bool                     `json:"enabled"`
+	PostHook string                   `json:"postHook"`
+	Releases []FeatureReleaseResponse `json:"releases,omitempty"`
+}
+
+// FeatureReleaseResponse describes a Helm release installed by a cluster feature
+type FeatureReleaseResponse struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
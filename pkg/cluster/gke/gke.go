@@ -87,7 +87,22 @@ func (g *CreateClusterGKE) Validate() error {
 		return pkgErrors.ErrorDifferentKubernetesVersion
 	}
 
+	if err := validateNodePools(g.NodePools); err != nil {
+		return err
+	}
+
 	for _, nodePool := range g.NodePools {
+		if nodePool.Count == 0 {
+			nodePool.Count = pkgCommon.DefaultNodeMinCount
+		}
+	}
+
+	return nil
+}
+
+// validateNodePools checks the autoscaling bounds of node pools, GKE autoscales node pools natively within them
+func validateNodePools(nodePools map[string]*NodePool) error {
+	for _, nodePool := range nodePools {
 
 		// ---- [ Min & Max count fields are required in case of autoscaling ] ---- //
 		if nodePool.Autoscaling {
@@ -101,11 +116,6 @@ func (g *CreateClusterGKE) Validate() error {
 				return pkgErrors.ErrorNodePoolMinMaxFieldError
 			}
 		}
-
-		if nodePool.Count == 0 {
-			nodePool.Count = pkgCommon.DefaultNodeMinCount
-		}
-
 	}
 
 	return nil
@@ -135,7 +145,7 @@ func (a *UpdateClusterGoogle) Validate() error {
 		return pkgErrors.ErrorNodePoolNotProvided
 	}
 
-	return nil
+	return validateNodePools(a.NodePools)
 }
 
 // ClusterProfileGKE describes an Amazon profile
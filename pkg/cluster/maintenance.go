@@ -0,0 +1,186 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/banzaicloud/pipeline/pkg/cron"
+)
+
+// maxMaintenanceWindowDuration is the longest maintenance window accepted
+const maxMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+// ### [ Queued operation types ] ### //
+const (
+	QueuedOperationUpdate         = "update"
+	QueuedOperationUpgrade        = "upgrade"
+	QueuedOperationNodePoolUpdate = "nodepool"
+)
+
+// ### [ Queued operation states ] ### //
+const (
+	QueuedOperationQueued    = "QUEUED"
+	QueuedOperationStarted   = "STARTED"
+	QueuedOperationFailed    = "FAILED"
+	QueuedOperationCancelled = "CANCELLED"
+)
+
+// MaintenanceWindowRequest describes a maintenance window creation request
+type MaintenanceWindowRequest struct {
+	Schedule string `json:"schedule" binding:"required"`
+	Duration string `json:"duration" binding:"required"`
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// MaintenanceWindowResponse describes a maintenance window of an organization or a cluster
+type MaintenanceWindowResponse struct {
+	ID        uint       `json:"id"`
+	ClusterID uint       `json:"clusterId,omitempty"`
+	Schedule  string     `json:"schedule"`
+	Duration  string     `json:"duration"`
+	TimeZone  string     `json:"timeZone"`
+	NextStart *time.Time `json:"nextStart,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy uint       `json:"createdBy,omitempty"`
+}
+
+// QueuedOperationResponse describes an operation waiting for the next maintenance window of a cluster
+type QueuedOperationResponse struct {
+	ID          uint       `json:"id"`
+	ClusterID   uint       `json:"clusterId"`
+	Type        string     `json:"type"`
+	State       string     `json:"state"`
+	Actor       uint       `json:"actor,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// UpgradeQueuedOperation describes a queued Kubernetes version upgrade
+type UpgradeQueuedOperation struct {
+	Version string `json:"version"`
+}
+
+// NodePoolQueuedOperation describes a queued node pool update or deletion (when NodePool is nil)
+type NodePoolQueuedOperation struct {
+	Name     string           `json:"name"`
+	NodePool *NodePoolRequest `json:"nodePool,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period starting at the times matching a cron schedule in a time zone.
+type MaintenanceWindow struct {
+	Schedule string
+	Duration string
+	TimeZone string
+}
+
+type parsedMaintenanceWindow struct {
+	schedule *cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+func (w MaintenanceWindow) parse() (*parsedMaintenanceWindow, error) {
+	schedule, err := cron.Parse(w.Schedule)
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("invalid schedule: %s", err.Error()))
+	}
+
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("invalid duration: %s", err.Error()))
+	}
+
+	if duration < time.Minute || duration > maxMaintenanceWindowDuration {
+		return nil, NewValidationError(fmt.Sprintf("duration must be between 1m and %s", maxMaintenanceWindowDuration))
+	}
+
+	location, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("invalid time zone: %s", err.Error()))
+	}
+
+	return &parsedMaintenanceWindow{
+		schedule: schedule,
+		duration: duration,
+		location: location,
+	}, nil
+}
+
+// Validate checks the schedule, the duration and the time zone of the window.
+func (w MaintenanceWindow) Validate() error {
+	_, err := w.parse()
+
+	return err
+}
+
+// NextStart returns the next time the window opens after the given time.
+func (w MaintenanceWindow) NextStart(now time.Time) (time.Time, error) {
+	window, err := w.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return window.schedule.Next(now.In(window.location)), nil
+}
+
+// IsOpen tells whether the given time falls into the window.
+func (w MaintenanceWindow) IsOpen(now time.Time) (bool, error) {
+	window, err := w.parse()
+	if err != nil {
+		return false, err
+	}
+
+	// the window is open if it started less than its duration ago
+	start := window.schedule.Next(now.In(window.location).Add(-window.duration))
+
+	return !start.IsZero() && !start.After(now), nil
+}
+
+// CheckMaintenanceWindows tells whether an operation can run at the given time:
+// operations can always run if no window is defined, otherwise only when one of the windows is open.
+// When none of the windows is open the next start of a window is returned as well.
+func CheckMaintenanceWindows(windows []MaintenanceWindow, now time.Time) (bool, *time.Time, error) {
+	if len(windows) == 0 {
+		return true, nil, nil
+	}
+
+	var next *time.Time
+
+	for _, window := range windows {
+		open, err := window.IsOpen(now)
+		if err != nil {
+			return false, nil, err
+		}
+
+		if open {
+			return true, nil, nil
+		}
+
+		start, err := window.NextStart(now)
+		if err != nil {
+			return false, nil, err
+		}
+
+		if !start.IsZero() && (next == nil || start.Before(*next)) {
+			next = &start
+		}
+	}
+
+	return false, next, nil
+}
@@ -0,0 +1,76 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckMaintenanceWindows(t *testing.T) {
+	nightly := MaintenanceWindow{Schedule: "0 2 * * *", Duration: "2h", TimeZone: "UTC"}
+	weekend := MaintenanceWindow{Schedule: "0 0 * * sat", Duration: "48h", TimeZone: "UTC"}
+	budapest := MaintenanceWindow{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Europe/Budapest"}
+
+	tests := []struct {
+		name    string
+		windows []MaintenanceWindow
+		now     time.Time
+		open    bool
+		next    time.Time
+		invalid bool
+	}{
+		{name: "no windows", now: time.Date(2018, 11, 20, 10, 0, 0, 0, time.UTC), open: true},
+		{name: "start of window", windows: []MaintenanceWindow{nightly}, now: time.Date(2018, 11, 20, 2, 0, 0, 0, time.UTC), open: true},
+		{name: "inside window", windows: []MaintenanceWindow{nightly}, now: time.Date(2018, 11, 20, 3, 59, 59, 0, time.UTC), open: true},
+		{name: "end of window", windows: []MaintenanceWindow{nightly}, now: time.Date(2018, 11, 20, 4, 0, 0, 0, time.UTC), next: time.Date(2018, 11, 21, 2, 0, 0, 0, time.UTC)},
+		{name: "before window", windows: []MaintenanceWindow{nightly}, now: time.Date(2018, 11, 20, 1, 0, 0, 0, time.UTC), next: time.Date(2018, 11, 20, 2, 0, 0, 0, time.UTC)},
+		{name: "any window", windows: []MaintenanceWindow{nightly, weekend}, now: time.Date(2018, 11, 25, 12, 0, 0, 0, time.UTC), open: true},
+		{name: "earliest window", windows: []MaintenanceWindow{weekend, nightly}, now: time.Date(2018, 11, 20, 12, 0, 0, 0, time.UTC), next: time.Date(2018, 11, 21, 2, 0, 0, 0, time.UTC)},
+		{name: "time zone", windows: []MaintenanceWindow{budapest}, now: time.Date(2018, 11, 20, 1, 30, 0, 0, time.UTC), open: true},
+		{name: "invalid schedule", windows: []MaintenanceWindow{{Schedule: "0 2 * *", Duration: "1h"}}, invalid: true},
+		{name: "invalid duration", windows: []MaintenanceWindow{{Schedule: "0 2 * * *", Duration: "0s"}}, invalid: true},
+		{name: "invalid time zone", windows: []MaintenanceWindow{{Schedule: "0 2 * * *", Duration: "1h", TimeZone: "Mars/Olympus"}}, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			open, next, err := CheckMaintenanceWindows(test.windows, test.now)
+			if test.invalid {
+				if _, ok := err.(*ValidationError); !ok {
+					t.Fatalf("expected a validation error, got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if open != test.open {
+				t.Fatalf("expected open to be %t", test.open)
+			}
+
+			if test.next.IsZero() {
+				if next != nil {
+					t.Fatalf("unexpected next start: %s", next)
+				}
+			} else if next == nil || !next.Equal(test.next) {
+				t.Fatalf("expected next start %s, got %v", test.next, next)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// NodePoolRequest describes a single node pool of the node pool API
+type NodePoolRequest struct {
+	Name         string `json:"name,omitempty"`
+	Autoscaling  bool   `json:"autoscaling"`
+	Count        int    `json:"count"`
+	MinCount     int    `json:"minCount,omitempty"`
+	MaxCount     int    `json:"maxCount,omitempty"`
+	InstanceType string `json:"instanceType,omitempty"`
+	SpotPrice    string `json:"spotPrice,omitempty"`
+	Image        string `json:"image,omitempty"`
+}
+
+// NodePoolResponse describes Pipeline's node pool API responses
+type NodePoolResponse struct {
+	Status int    `json:"status"`
+	Name   string `json:"name"`
+}
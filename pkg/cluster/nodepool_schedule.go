@@ -0,0 +1,122 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/banzaicloud/pipeline/pkg/cron"
+)
+
+// NodePoolScheduleRequest describes a node pool scaling schedule creation request
+type NodePoolScheduleRequest struct {
+	Schedule string `json:"schedule" binding:"required"`
+	TimeZone string `json:"timeZone,omitempty"`
+	Count    int    `json:"count"`
+}
+
+// NodePoolScheduleResponse describes a scaling schedule of a node pool
+type NodePoolScheduleResponse struct {
+	ID        uint       `json:"id"`
+	ClusterID uint       `json:"clusterId"`
+	NodePool  string     `json:"nodePool"`
+	Schedule  string     `json:"schedule"`
+	TimeZone  string     `json:"timeZone"`
+	Count     int        `json:"count"`
+	NextRun   *time.Time `json:"nextRun,omitempty"`
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+	LastError string     `json:"lastError,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy uint       `json:"createdBy,omitempty"`
+}
+
+// NodePoolSchedule scales a node pool to a node count at the times matching a cron schedule in a time zone.
+type NodePoolSchedule struct {
+	Schedule string
+	TimeZone string
+	Count    int
+}
+
+func (s NodePoolSchedule) parse() (*cron.Schedule, *time.Location, error) {
+	schedule, err := cron.Parse(s.Schedule)
+	if err != nil {
+		return nil, nil, NewValidationError(fmt.Sprintf("invalid schedule: %s", err.Error()))
+	}
+
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, nil, NewValidationError(fmt.Sprintf("invalid time zone: %s", err.Error()))
+	}
+
+	return schedule, location, nil
+}
+
+// Validate checks the schedule, the time zone and the node count of the schedule.
+func (s NodePoolSchedule) Validate() error {
+	if s.Count < 0 {
+		return NewValidationError("node count must not be negative")
+	}
+
+	_, _, err := s.parse()
+
+	return err
+}
+
+// NextRun returns the next time the schedule runs after the given time.
+func (s NodePoolSchedule) NextRun(now time.Time) (time.Time, error) {
+	schedule, location, err := s.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return schedule.Next(now.In(location)), nil
+}
+
+// DueRun returns the latest run of the schedule after the given time (eg. the last run) and not after now.
+// Runs missed in between are skipped, since only the latest one determines the node count.
+// The zero time is returned if the schedule is not due.
+func (s NodePoolSchedule) DueRun(since time.Time, now time.Time) (time.Time, error) {
+	schedule, location, err := s.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var due time.Time
+
+	for run := schedule.Next(since.In(location)); !run.IsZero() && !run.After(now); run = schedule.Next(run) {
+		due = run
+	}
+
+	return due, nil
+}
+
+// ScheduledNodeCount returns the node count a node pool is scaled to by a schedule.
+// The count of node pools with autoscaling enabled is kept between the minimum and the maximum size of the node pool.
+func ScheduledNodeCount(current *NodePoolStatus, count int) int {
+	if current == nil || !current.Autoscaling {
+		return count
+	}
+
+	if count < current.MinCount {
+		return current.MinCount
+	}
+
+	if current.MaxCount > 0 && count > current.MaxCount {
+		return current.MaxCount
+	}
+
+	return count
+}
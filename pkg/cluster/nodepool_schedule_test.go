@@ -0,0 +1,74 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNodePoolScheduleDueRun(t *testing.T) {
+	evening := NodePoolSchedule{Schedule: "0 20 * * mon-fri", TimeZone: "UTC"}
+
+	tests := []struct {
+		name     string
+		schedule NodePoolSchedule
+		since    time.Time
+		now      time.Time
+		due      time.Time
+	}{
+		{name: "not due", schedule: evening, since: time.Date(2018, 11, 20, 8, 0, 0, 0, time.UTC), now: time.Date(2018, 11, 20, 19, 59, 0, 0, time.UTC)},
+		{name: "due", schedule: evening, since: time.Date(2018, 11, 20, 8, 0, 0, 0, time.UTC), now: time.Date(2018, 11, 20, 20, 0, 30, 0, time.UTC), due: time.Date(2018, 11, 20, 20, 0, 0, 0, time.UTC)},
+		{name: "already run", schedule: evening, since: time.Date(2018, 11, 20, 20, 0, 0, 0, time.UTC), now: time.Date(2018, 11, 20, 20, 1, 0, 0, time.UTC)},
+		{name: "latest missed run", schedule: evening, since: time.Date(2018, 11, 19, 8, 0, 0, 0, time.UTC), now: time.Date(2018, 11, 21, 21, 0, 0, 0, time.UTC), due: time.Date(2018, 11, 21, 20, 0, 0, 0, time.UTC)},
+		{name: "weekend", schedule: evening, since: time.Date(2018, 11, 24, 8, 0, 0, 0, time.UTC), now: time.Date(2018, 11, 25, 21, 0, 0, 0, time.UTC)},
+		{name: "time zone", schedule: NodePoolSchedule{Schedule: "0 7 * * *", TimeZone: "America/New_York"}, since: time.Date(2018, 11, 20, 0, 0, 0, 0, time.UTC), now: time.Date(2018, 11, 20, 12, 0, 0, 0, time.UTC), due: time.Date(2018, 11, 20, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			due, err := test.schedule.DueRun(test.since, test.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if !due.Equal(test.due) {
+				t.Fatalf("expected due run %s, got %s", test.due, due)
+			}
+		})
+	}
+}
+
+func TestScheduledNodeCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  *NodePoolStatus
+		count    int
+		expected int
+	}{
+		{name: "fixed size", current: &NodePoolStatus{Count: 3}, count: 0, expected: 0},
+		{name: "within limits", current: &NodePoolStatus{Autoscaling: true, Count: 3, MinCount: 1, MaxCount: 5}, count: 2, expected: 2},
+		{name: "below minimum", current: &NodePoolStatus{Autoscaling: true, Count: 3, MinCount: 1, MaxCount: 5}, count: 0, expected: 1},
+		{name: "above maximum", current: &NodePoolStatus{Autoscaling: true, Count: 3, MinCount: 1, MaxCount: 5}, count: 8, expected: 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if count := ScheduledNodeCount(test.current, test.count); count != test.expected {
+				t.Fatalf("expected %d nodes, got %d", test.expected, count)
+			}
+		})
+	}
+}
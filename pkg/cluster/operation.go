@@ -0,0 +1,82 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+)
+
+// ### [ Cluster operation types ] ### //
+const (
+	OperationCreate   = "create"
+	OperationUpdate   = "update"
+	OperationDelete   = "delete"
+	OperationUpgrade  = "upgrade"
+	OperationPostHook = "posthook"
+	OperationRestore  = "restore"
+	OperationFeature  = "feature"
+)
+
+// ### [ Cluster operation states ] ### //
+const (
+	OperationRunning   = "RUNNING"
+	OperationSucceeded = "SUCCEEDED"
+	OperationFailed    = "FAILED"
+)
+
+// OperationResponse describes a cluster operation (create, update, delete, upgrade, posthook, restore, feature) and its progress
+type OperationResponse struct {
+	ID         uint                    `json:"id"`
+	ClusterID  uint                    `json:"clusterId"`
+	Type       string                  `json:"type"`
+	State      string                  `json:"state"`
+	Actor      uint                    `json:"actor,omitempty"`
+	StartedAt  time.Time               `json:"startedAt"`
+	FinishedAt *time.Time              `json:"finishedAt,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+	Steps      []OperationStepResponse `json:"steps,omitempty"`
+}
+
+// OperationStepResponse describes a single step of a cluster operation
+type OperationStepResponse struct {
+	Name      string    `json:"name"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OperationInProgressError is returned when another operation holds the lock of a cluster
+type OperationInProgressError struct {
+	ClusterID     uint
+	OperationID   uint
+	OperationType string
+}
+
+func (e *OperationInProgressError) Error() string {
+	return fmt.Sprintf("another operation (%s) is in progress on the cluster", e.OperationType)
+}
+
+// Conflict tells the API to respond with 409.
+func (e *OperationInProgressError) Conflict() bool {
+	return true
+}
+
+// OperationConflictResponse describes Pipeline's response when another operation is in progress on a cluster
+type OperationConflictResponse struct {
+	Code          int    `json:"code"`
+	Message       string `json:"message"`
+	OperationID   uint   `json:"operationId,omitempty"`
+	OperationType string `json:"operationType"`
+}
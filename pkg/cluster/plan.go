@@ -0,0 +1,58 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+// UpdatePlanResponse describes what an UpdateCluster request would do to a cluster
+type UpdatePlanResponse struct {
+	NodePools NodePoolsDiff `json:"nodePools"`
+	Actions   []string      `json:"actions"`
+}
+
+// NodePoolsDiff describes the node pool changes of an UpdateCluster request
+type NodePoolsDiff struct {
+	Added               []NodePoolPlan               `json:"added"`
+	Removed             []NodePoolPlan               `json:"removed"`
+	Resized             []NodePoolResize             `json:"resized"`
+	InstanceTypeChanged []NodePoolInstanceTypeChange `json:"instanceTypeChanged"`
+}
+
+// NodePoolPlan describes the cloud independent properties of a node pool
+type NodePoolPlan struct {
+	Name         string `json:"name"`
+	InstanceType string `json:"instanceType,omitempty"`
+	NodePoolSize
+}
+
+// NodePoolSize describes the size related properties of a node pool
+type NodePoolSize struct {
+	Autoscaling bool `json:"autoscaling"`
+	MinCount    int  `json:"minCount"`
+	MaxCount    int  `json:"maxCount"`
+	Count       int  `json:"count"`
+}
+
+// NodePoolResize describes a size change of a node pool
+type NodePoolResize struct {
+	Name string       `json:"name"`
+	From NodePoolSize `json:"from"`
+	To   NodePoolSize `json:"to"`
+}
+
+// NodePoolInstanceTypeChange describes an instance type change of a node pool
+type NodePoolInstanceTypeChange struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
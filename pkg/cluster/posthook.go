@@ -0,0 +1,179 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"text/template"
+	"time"
+)
+
+// ### [ Posthook states ] ### //
+const (
+	PostHookPending   = "PENDING"
+	PostHookRunning   = "RUNNING"
+	PostHookSucceeded = "SUCCEEDED"
+	PostHookFailed    = "FAILED"
+)
+
+// PostHookStatus describes the state of a single posthook of a cluster
+type PostHookStatus struct {
+	Name       string     `json:"name"`
+	State      string     `json:"state"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Duration   string     `json:"duration,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// CustomPostHook describes a user-defined posthook of an organization which installs a Helm chart.
+// Values is a Go template rendered with the params of the posthook and the details of the cluster.
+type CustomPostHook struct {
+	Name       string   `json:"name"`
+	Chart      string   `json:"chart"`
+	Version    string   `json:"version,omitempty"`
+	Repository string   `json:"repository"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Values     string   `json:"values,omitempty"`
+	Params     []string `json:"params,omitempty"`
+
+	// DependsOn lists the posthooks which have to succeed before the posthook runs (Helm is always installed before)
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// CustomPostHookResponse describes a user-defined posthook of an organization
+type CustomPostHookResponse struct {
+	CustomPostHook
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy uint      `json:"createdBy,omitempty"`
+}
+
+// CustomPostHookValues describes the data the values template of a user-defined posthook is rendered with
+type CustomPostHookValues struct {
+	Cluster CustomPostHookCluster
+	Params  map[string]interface{}
+}
+
+// CustomPostHookCluster describes the cluster details available in the values template of a user-defined posthook
+type CustomPostHookCluster struct {
+	ID           uint
+	UID          string
+	Name         string
+	Cloud        string
+	Distribution string
+	Location     string
+}
+
+// custom posthook names are used as Helm release names
+var customPostHookNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+const customPostHookNameMaxLength = 53
+
+// Validate checks the fields of a user-defined posthook and parses its values template
+func (p *CustomPostHook) Validate() error {
+	if len(p.Name) > customPostHookNameMaxLength || !customPostHookNameRegexp.MatchString(p.Name) {
+		return NewValidationError(
+			fmt.Sprintf("name must consist of lower case alphanumeric characters or '-' and be at most %d characters long", customPostHookNameMaxLength),
+		)
+	}
+
+	if p.Chart == "" {
+		return NewValidationError("chart must be set")
+	}
+
+	if p.Repository == "" {
+		return NewValidationError("repository must be set")
+	}
+
+	params := make(map[string]bool, len(p.Params))
+	for _, param := range p.Params {
+		if param == "" {
+			return NewValidationError("param names cannot be empty")
+		}
+
+		if params[param] {
+			return NewValidationError(fmt.Sprintf("param %q is listed more than once", param))
+		}
+
+		params[param] = true
+	}
+
+	for _, dependency := range p.DependsOn {
+		if dependency == "" || dependency == p.Name {
+			return NewValidationError(fmt.Sprintf("invalid dependency %q", dependency))
+		}
+	}
+
+	if _, err := p.parseValues(); err != nil {
+		return NewValidationError(fmt.Sprintf("invalid values template: %s", err.Error()))
+	}
+
+	return nil
+}
+
+// CheckParams checks that every required param of a user-defined posthook is given
+func (p *CustomPostHook) CheckParams(params map[string]interface{}) error {
+	for _, param := range p.Params {
+		if _, ok := params[param]; !ok {
+			return NewValidationError(fmt.Sprintf("posthook %q requires param %q", p.Name, param))
+		}
+	}
+
+	return nil
+}
+
+// CustomPostHookParams converts posthook params to the map the values template of a user-defined posthook is rendered with
+func CustomPostHookParams(params PostHookParam) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	if params == nil {
+		return values, nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("invalid posthook params: %s", err.Error()))
+	}
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, NewValidationError("posthook params must be an object")
+	}
+
+	return values, nil
+}
+
+// RenderValues renders the values template of a user-defined posthook
+func (p *CustomPostHook) RenderValues(values CustomPostHookValues) ([]byte, error) {
+	tmpl, err := p.parseValues()
+	if err != nil {
+		return nil, fmt.Errorf("invalid values template: %s", err.Error())
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, values); err != nil {
+		return nil, fmt.Errorf("could not render values template: %s", err.Error())
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (p *CustomPostHook) parseValues() (*template.Template, error) {
+	return template.New(fmt.Sprintf("%s-values", p.Name)).Option("missingkey=error").Parse(p.Values)
+}
@@ -0,0 +1,111 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+)
+
+func TestCustomPostHookValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		postHook CustomPostHook
+		valid    bool
+	}{
+		{
+			name:     "valid",
+			postHook: CustomPostHook{Name: "ingress", Chart: "nginx-ingress", Repository: "stable", Values: "replicas: {{ .Params.replicas }}", Params: []string{"replicas"}},
+			valid:    true,
+		},
+		{
+			name:     "invalid name",
+			postHook: CustomPostHook{Name: "My_Ingress", Chart: "nginx-ingress", Repository: "stable"},
+		},
+		{
+			name:     "missing chart",
+			postHook: CustomPostHook{Name: "ingress", Repository: "stable"},
+		},
+		{
+			name:     "missing repository",
+			postHook: CustomPostHook{Name: "ingress", Chart: "nginx-ingress"},
+		},
+		{
+			name:     "duplicate param",
+			postHook: CustomPostHook{Name: "ingress", Chart: "nginx-ingress", Repository: "stable", Params: []string{"domain", "domain"}},
+		},
+		{
+			name:     "self dependency",
+			postHook: CustomPostHook{Name: "ingress", Chart: "nginx-ingress", Repository: "stable", DependsOn: []string{"ingress"}},
+		},
+		{
+			name:     "invalid template",
+			postHook: CustomPostHook{Name: "ingress", Chart: "nginx-ingress", Repository: "stable", Values: "host: {{ .Params.domain"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.postHook.Validate()
+			if test.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if !test.valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCustomPostHookRenderValues(t *testing.T) {
+	postHook := CustomPostHook{
+		Name:   "cert",
+		Values: "issuer: {{ .Params.issuer }}\ncommonName: {{ .Cluster.Name }}.example.com\n",
+		Params: []string{"issuer"},
+	}
+
+	if _, err := CustomPostHookParams("letsencrypt"); err == nil {
+		t.Error("expected an error for params which are not an object")
+	}
+
+	if err := postHook.CheckParams(map[string]interface{}{}); err == nil {
+		t.Error("expected an error for a missing param")
+	}
+
+	params, err := CustomPostHookParams(map[string]string{"issuer": "letsencrypt"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := postHook.CheckParams(params); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	values, err := postHook.RenderValues(CustomPostHookValues{
+		Cluster: CustomPostHookCluster{Name: "demo"},
+		Params:  params,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "issuer: letsencrypt\ncommonName: demo.example.com\n"
+	if string(values) != expected {
+		t.Errorf("expected values %q, got %q", expected, string(values))
+	}
+
+	postHook.Values = "domain: {{ .Params.domain }}"
+	if _, err := postHook.RenderValues(CustomPostHookValues{Params: params}); err == nil {
+		t.Error("expected an error for an unknown param")
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Quota limits
+const (
+	QuotaMaxClusters          = "maxClusters"
+	QuotaMaxNodes             = "maxNodes"
+	QuotaMaxCPUs              = "maxVCPUs"
+	QuotaAllowedInstanceTypes = "allowedInstanceTypes"
+	QuotaAllowedRegions       = "allowedRegions"
+)
+
+// Quota describes the limits of the clusters of an organization.
+// Unset limits are not enforced, per cloud limits only apply to the clouds listed.
+type Quota struct {
+	MaxClusters          *int                `json:"maxClusters,omitempty"`
+	MaxNodes             *int                `json:"maxNodes,omitempty"`
+	MaxCPUs              map[string]int      `json:"maxVCPUs,omitempty"`
+	AllowedInstanceTypes map[string][]string `json:"allowedInstanceTypes,omitempty"`
+	AllowedRegions       map[string][]string `json:"allowedRegions,omitempty"`
+}
+
+// QuotaUsage describes the resources used by the clusters of an organization
+type QuotaUsage struct {
+	Clusters int            `json:"clusters"`
+	Nodes    int            `json:"nodes"`
+	CPUs     map[string]int `json:"vcpus"`
+}
+
+// QuotaResponse describes the quota of an organization together with its current usage
+type QuotaResponse struct {
+	Quota
+	Usage     QuotaUsage `json:"usage"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	UpdatedBy uint       `json:"updatedBy,omitempty"`
+}
+
+// QuotaViolation describes a limit which would be exceeded by an operation
+type QuotaViolation struct {
+	Limit     string `json:"limit"`
+	Cloud     string `json:"cloud,omitempty"`
+	Max       *int   `json:"max,omitempty"`
+	Current   *int   `json:"current,omitempty"`
+	Requested *int   `json:"requested,omitempty"`
+	Value     string `json:"value,omitempty"`
+	Message   string `json:"message"`
+}
+
+// QuotaExceededError is returned when an operation would exceed the quota of an organization
+type QuotaExceededError struct {
+	Violations []QuotaViolation
+}
+
+func (e *QuotaExceededError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+
+	return fmt.Sprintf("organization quota exceeded: %s", strings.Join(messages, "; "))
+}
+
+// Forbidden tells the API to respond with 403.
+func (e *QuotaExceededError) Forbidden() bool {
+	return true
+}
+
+// QuotaExceededResponse describes Pipeline's response when an operation would exceed the quota of an organization
+type QuotaExceededResponse struct {
+	Code       int              `json:"code"`
+	Message    string           `json:"message"`
+	Violations []QuotaViolation `json:"violations"`
+}
+
+// ClusterResources describes the resources of a cluster counted against the quota of its organization.
+type ClusterResources struct {
+	Cloud     string
+	Location  string
+	NodePools []NodePoolPlan
+
+	// InstanceTypeCPUs are the vCPU counts of the instance types of the node pools, unknown ones are missing.
+	InstanceTypeCPUs map[string]int
+}
+
+// Nodes returns the number of nodes the node pools may have: the maximum size of autoscaling node pools.
+func (r *ClusterResources) Nodes() int {
+	var nodes int
+
+	for _, nodePool := range r.NodePools {
+		nodes += nodePoolMaxNodes(nodePool)
+	}
+
+	return nodes
+}
+
+// CPUs returns the number of vCPUs the node pools may have and the instance types with an unknown vCPU count.
+func (r *ClusterResources) CPUs() (int, []string) {
+	var cpus int
+	var unknown []string
+
+	for _, nodePool := range r.NodePools {
+		nodeCPUs, ok := r.InstanceTypeCPUs[nodePool.InstanceType]
+		if !ok {
+			unknown = append(unknown, nodePool.InstanceType)
+
+			continue
+		}
+
+		cpus += nodeCPUs * nodePoolMaxNodes(nodePool)
+	}
+
+	return cpus, unknown
+}
+
+func nodePoolMaxNodes(nodePool NodePoolPlan) int {
+	if nodePool.Autoscaling && nodePool.MaxCount > nodePool.Count {
+		return nodePool.MaxCount
+	}
+
+	return nodePool.Count
+}
+
+// NewQuotaUsage sums the resources of the clusters of an organization.
+func NewQuotaUsage(clusters []ClusterResources) QuotaUsage {
+	usage := QuotaUsage{
+		Clusters: len(clusters),
+		CPUs:     make(map[string]int),
+	}
+
+	for _, cluster := range clusters {
+		usage.Nodes += cluster.Nodes()
+
+		cpus, _ := cluster.CPUs()
+		usage.CPUs[cluster.Cloud] += cpus
+	}
+
+	return usage
+}
+
+// Validate checks that the limits of a quota are valid.
+func (q *Quota) Validate() error {
+	if q.MaxClusters != nil && *q.MaxClusters < 0 {
+		return NewValidationError("maximum number of clusters must not be negative")
+	}
+
+	if q.MaxNodes != nil && *q.MaxNodes < 0 {
+		return NewValidationError("maximum number of nodes must not be negative")
+	}
+
+	for cloud, cpus := range q.MaxCPUs {
+		if err := validateQuotaCloud(cloud); err != nil {
+			return err
+		}
+
+		if cpus < 0 {
+			return NewValidationError(fmt.Sprintf("maximum number of %s vCPUs must not be negative", cloud))
+		}
+	}
+
+	for cloud := range q.AllowedInstanceTypes {
+		if err := validateQuotaCloud(cloud); err != nil {
+			return err
+		}
+	}
+
+	for cloud := range q.AllowedRegions {
+		if err := validateQuotaCloud(cloud); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateQuotaCloud(cloud string) error {
+	switch cloud {
+	case Alibaba, Amazon, Azure, Google, Oracle:
+		return nil
+	}
+
+	return NewValidationError(fmt.Sprintf("quotas of %s clusters are not supported", cloud))
+}
+
+// Check returns the limits exceeded by creating a cluster (current is nil) or by updating the current
+// resources of a cluster to the requested ones.
+// Limits which are already exceeded (eg. after lowering a quota) only fail operations increasing the usage further,
+// instance types and regions are only checked for new node pools and clusters.
+func (q *Quota) Check(usage QuotaUsage, current *ClusterResources, requested *ClusterResources) []QuotaViolation {
+	var violations []QuotaViolation
+
+	if q.MaxClusters != nil && current == nil && usage.Clusters+1 > *q.MaxClusters {
+		violations = append(violations, newQuotaViolation(
+			QuotaMaxClusters, "", *q.MaxClusters, usage.Clusters, usage.Clusters+1,
+			fmt.Sprintf("the organization may have at most %d clusters", *q.MaxClusters),
+		))
+	}
+
+	if q.MaxNodes != nil {
+		nodes := usage.Nodes + requested.Nodes()
+		if current != nil {
+			nodes -= current.Nodes()
+		}
+
+		if nodes > *q.MaxNodes && nodes > usage.Nodes {
+			violations = append(violations, newQuotaViolation(
+				QuotaMaxNodes, "", *q.MaxNodes, usage.Nodes, nodes,
+				fmt.Sprintf("the clusters of the organization may have at most %d nodes, %d requested", *q.MaxNodes, nodes),
+			))
+		}
+	}
+
+	if maxCPUs, ok := q.MaxCPUs[requested.Cloud]; ok {
+		requestedCPUs, unknown := requested.CPUs()
+
+		for _, instanceType := range uniqueStrings(unknown) {
+			violations = append(violations, QuotaViolation{
+				Limit:   QuotaMaxCPUs,
+				Cloud:   requested.Cloud,
+				Value:   instanceType,
+				Message: fmt.Sprintf("the number of vCPUs of instance type [%s] is unknown", instanceType),
+			})
+		}
+
+		cpus := usage.CPUs[requested.Cloud] + requestedCPUs
+		if current != nil {
+			currentCPUs, _ := current.CPUs()
+			cpus -= currentCPUs
+		}
+
+		if len(unknown) == 0 && cpus > maxCPUs && cpus > usage.CPUs[requested.Cloud] {
+			violations = append(violations, newQuotaViolation(
+				QuotaMaxCPUs, requested.Cloud, maxCPUs, usage.CPUs[requested.Cloud], cpus,
+				fmt.Sprintf("the %s clusters of the organization may have at most %d vCPUs, %d requested", requested.Cloud, maxCPUs, cpus),
+			))
+		}
+	}
+
+	if allowed, ok := q.AllowedInstanceTypes[requested.Cloud]; ok {
+		currentInstanceTypes := make(map[string]string)
+		if current != nil {
+			for _, nodePool := range current.NodePools {
+				currentInstanceTypes[nodePool.Name] = nodePool.InstanceType
+			}
+		}
+
+		var denied []string
+		for _, nodePool := range requested.NodePools {
+			if nodePool.InstanceType == "" || nodePool.InstanceType == currentInstanceTypes[nodePool.Name] {
+				continue
+			}
+
+			if !containsString(allowed, nodePool.InstanceType) {
+				denied = append(denied, nodePool.InstanceType)
+			}
+		}
+
+		for _, instanceType := range uniqueStrings(denied) {
+			violations = append(violations, QuotaViolation{
+				Limit:   QuotaAllowedInstanceTypes,
+				Cloud:   requested.Cloud,
+				Value:   instanceType,
+				Message: fmt.Sprintf("instance type [%s] is not allowed for %s clusters", instanceType, requested.Cloud),
+			})
+		}
+	}
+
+	if allowed, ok := q.AllowedRegions[requested.Cloud]; ok && current == nil && !isAllowedRegion(allowed, requested.Location) {
+		violations = append(violations, QuotaViolation{
+			Limit:   QuotaAllowedRegions,
+			Cloud:   requested.Cloud,
+			Value:   requested.Location,
+			Message: fmt.Sprintf("location [%s] is not allowed for %s clusters", requested.Location, requested.Cloud),
+		})
+	}
+
+	return violations
+}
+
+func newQuotaViolation(limit string, cloud string, max int, current int, requested int, message string) QuotaViolation {
+	return QuotaViolation{
+		Limit:     limit,
+		Cloud:     cloud,
+		Max:       &max,
+		Current:   &current,
+		Requested: &requested,
+		Message:   message,
+	}
+}
+
+// isAllowedRegion tells whether a location is an allowed region or a zone of one
+// (eg. europe-west1-b of europe-west1 or us-east-1a of us-east-1).
+func isAllowedRegion(allowed []string, location string) bool {
+	for _, region := range allowed {
+		if location == region || strings.HasPrefix(location, region+"-") {
+			return true
+		}
+
+		if len(location) == len(region)+1 && strings.HasPrefix(location, region) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func uniqueStrings(values []string) []string {
+	var unique []string
+
+	for _, value := range values {
+		if !containsString(unique, value) {
+			unique = append(unique, value)
+		}
+	}
+
+	sort.Strings(unique)
+
+	return unique
+}
@@ -0,0 +1,180 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuotaCheck(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	nodePool := func(name string, instanceType string, count int, maxCount int) NodePoolPlan {
+		return NodePoolPlan{
+			Name:         name,
+			InstanceType: instanceType,
+			NodePoolSize: NodePoolSize{Autoscaling: maxCount > 0, MinCount: count, MaxCount: maxCount, Count: count},
+		}
+	}
+
+	cpus := map[string]int{"n1-standard-2": 2, "n1-standard-4": 4, "n1-highmem-2": 2}
+
+	existing := ClusterResources{
+		Cloud:            Google,
+		Location:         "europe-west1-b",
+		NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 3, 0)},
+		InstanceTypeCPUs: cpus,
+	}
+	usage := NewQuotaUsage([]ClusterResources{existing})
+
+	quota := Quota{
+		MaxClusters:          intPtr(2),
+		MaxNodes:             intPtr(10),
+		MaxCPUs:              map[string]int{Google: 16},
+		AllowedInstanceTypes: map[string][]string{Google: {"n1-standard-2", "n1-standard-4"}},
+		AllowedRegions:       map[string][]string{Google: {"europe-west1"}},
+	}
+
+	tests := []struct {
+		name      string
+		quota     Quota
+		usage     QuotaUsage
+		current   *ClusterResources
+		requested ClusterResources
+		limits    []string
+	}{
+		{
+			name:  "create within quota",
+			quota: quota,
+			usage: usage,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1-c",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 2, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+		},
+		{
+			name:  "too many clusters",
+			quota: quota,
+			usage: NewQuotaUsage([]ClusterResources{existing, existing}),
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 1, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+			limits: []string{QuotaMaxClusters},
+		},
+		{
+			name:  "autoscaling maximum counts",
+			quota: quota,
+			usage: usage,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 1, 60)},
+				InstanceTypeCPUs: cpus,
+			},
+			limits: []string{QuotaMaxNodes, QuotaMaxCPUs},
+		},
+		{
+			name:  "instance type and region",
+			quota: quota,
+			usage: usage,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "us-central1-a",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-highmem-2", 1, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+			limits: []string{QuotaAllowedInstanceTypes, QuotaAllowedRegions},
+		},
+		{
+			name:  "unknown vCPUs",
+			quota: quota,
+			usage: usage,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "weird", 1, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+			limits: []string{QuotaMaxCPUs, QuotaAllowedInstanceTypes},
+		},
+		{
+			name:    "update within quota",
+			quota:   quota,
+			usage:   usage,
+			current: &existing,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1-b",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 8, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+		},
+		{
+			name:    "update over quota",
+			quota:   quota,
+			usage:   usage,
+			current: &existing,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1-b",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 11, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+			limits: []string{QuotaMaxNodes, QuotaMaxCPUs},
+		},
+		{
+			name:    "shrinking over a lowered quota",
+			quota:   Quota{MaxNodes: intPtr(1)},
+			usage:   usage,
+			current: &existing,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "europe-west1-b",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 2, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+		},
+		{
+			name:  "other cloud",
+			quota: Quota{MaxCPUs: map[string]int{Amazon: 0}, AllowedRegions: map[string][]string{Amazon: {"eu-west-1"}}},
+			usage: usage,
+			requested: ClusterResources{
+				Cloud:            Google,
+				Location:         "us-central1-a",
+				NodePools:        []NodePoolPlan{nodePool("pool1", "n1-standard-2", 1, 0)},
+				InstanceTypeCPUs: cpus,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var limits []string
+			for _, violation := range test.quota.Check(test.usage, test.current, &test.requested) {
+				limits = append(limits, violation.Limit)
+			}
+
+			if !reflect.DeepEqual(limits, test.limits) {
+				t.Fatalf("expected violations %v, got %v", test.limits, limits)
+			}
+		})
+	}
+}
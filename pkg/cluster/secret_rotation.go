@@ -0,0 +1,83 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxSecretRotationIntervalDays is the longest rotation interval accepted
+const maxSecretRotationIntervalDays = 3650
+
+// ### [ Secret rotation states ] ### //
+const (
+	SecretRotationRunning   = "RUNNING"
+	SecretRotationSucceeded = "SUCCEEDED"
+	SecretRotationFailed    = "FAILED"
+)
+
+// SecretRotationPolicy describes how often the values of a secret are regenerated
+type SecretRotationPolicy struct {
+	IntervalDays int `json:"intervalDays" binding:"required"`
+}
+
+// SecretRotationPolicyResponse describes the rotation policy of a secret
+type SecretRotationPolicyResponse struct {
+	SecretID       string     `json:"secretId"`
+	IntervalDays   int        `json:"intervalDays"`
+	LastRotatedAt  *time.Time `json:"lastRotatedAt,omitempty"`
+	NextRotationAt time.Time  `json:"nextRotationAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	CreatedBy      uint       `json:"createdBy,omitempty"`
+}
+
+// SecretRotationResponse describes a rotation of a secret and its propagation to the clusters the secret is installed to
+type SecretRotationResponse struct {
+	ID         uint                            `json:"id"`
+	SecretID   string                          `json:"secretId"`
+	Version    int                             `json:"version,omitempty"`
+	State      string                          `json:"state"`
+	Actor      uint                            `json:"actor,omitempty"`
+	StartedAt  time.Time                       `json:"startedAt"`
+	FinishedAt *time.Time                      `json:"finishedAt,omitempty"`
+	Error      string                          `json:"error,omitempty"`
+	Clusters   []SecretRotationClusterResponse `json:"clusters,omitempty"`
+}
+
+// SecretRotationClusterResponse describes the propagation of a rotated secret to a Kubernetes secret of a cluster
+type SecretRotationClusterResponse struct {
+	ClusterID uint   `json:"clusterId"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	State     string `json:"state"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Validate checks that the interval of a rotation policy is valid.
+func (p SecretRotationPolicy) Validate() error {
+	if p.IntervalDays < 1 || p.IntervalDays > maxSecretRotationIntervalDays {
+		return NewValidationError(
+			fmt.Sprintf("rotation interval must be between 1 and %d days", maxSecretRotationIntervalDays),
+		)
+	}
+
+	return nil
+}
+
+// Interval returns the time between two rotations.
+func (p SecretRotationPolicy) Interval() time.Duration {
+	return time.Duration(p.IntervalDays) * 24 * time.Hour
+}
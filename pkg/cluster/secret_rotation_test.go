@@ -0,0 +1,55 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSecretRotationPolicyValidate(t *testing.T) {
+	tests := []struct {
+		intervalDays int
+		valid        bool
+	}{
+		{intervalDays: -1},
+		{intervalDays: 0},
+		{intervalDays: 1, valid: true},
+		{intervalDays: 30, valid: true},
+		{intervalDays: 3650, valid: true},
+		{intervalDays: 3651},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprint(test.intervalDays), func(t *testing.T) {
+			err := SecretRotationPolicy{IntervalDays: test.intervalDays}.Validate()
+
+			if test.valid && err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			} else if !test.valid && err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+}
+
+func TestSecretRotationPolicyInterval(t *testing.T) {
+	interval := SecretRotationPolicy{IntervalDays: 30}.Interval()
+
+	if interval != 30*24*time.Hour {
+		t.Fatalf("expected 720h, got %s", interval)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+)
+
+// statusTransitions defines the statuses a cluster can move to from each status.
+// Every status can be kept to report progress with a new status message.
+// Clusters in ERROR status can be recovered through an update or by reconciling them back to RUNNING.
+var statusTransitions = map[string][]string{
+	"":       {Creating},
+	Creating: {Creating, Running, Warning, Error, Deleting},
+	Running:  {Running, Updating, Warning, Error, Deleting},
+	Updating: {Updating, Running, Warning, Error, Deleting},
+	Warning:  {Warning, Updating, Running, Error, Deleting},
+	Error:    {Error, Updating, Running, Deleting},
+	Deleting: {Deleting, Error},
+}
+
+// StatusTransitionError is returned when the status of a cluster cannot be changed.
+type StatusTransitionError struct {
+	From string
+	To   string
+
+	// Conflict is set when the status has been changed by someone else in the meantime.
+	Conflict bool
+}
+
+func (e *StatusTransitionError) Error() string {
+	if e.Conflict {
+		return fmt.Sprintf("cluster status has been changed concurrently, it is not %s anymore", e.From)
+	}
+
+	return fmt.Sprintf("cluster status cannot be changed from %s to %s", e.From, e.To)
+}
+
+// PreconditionFailed tells the API to respond with 412.
+func (e *StatusTransitionError) PreconditionFailed() bool {
+	return true
+}
+
+// ValidateStatusTransition checks whether a cluster can move from one status to another.
+func ValidateStatusTransition(from string, to string) error {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return nil
+		}
+	}
+
+	return &StatusTransitionError{From: from, To: to}
+}
+
+// StatusHistoryItem describes a status transition of a cluster
+type StatusHistoryItem struct {
+	FromStatus    string    `json:"fromStatus,omitempty"`
+	ToStatus      string    `json:"toStatus"`
+	StatusMessage string    `json:"statusMessage,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
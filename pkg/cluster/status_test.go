@@ -0,0 +1,76 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+)
+
+func TestValidateStatusTransition(t *testing.T) {
+	tests := []struct {
+		from  string
+		to    string
+		valid bool
+	}{
+		{from: "", to: Creating, valid: true},
+		{from: "", to: Running},
+		{from: Creating, to: Creating, valid: true},
+		{from: Creating, to: Running, valid: true},
+		{from: Creating, to: Updating},
+		{from: Running, to: Updating, valid: true},
+		{from: Running, to: Creating},
+		{from: Updating, to: Running, valid: true},
+		{from: Updating, to: Deleting, valid: true},
+		{from: Updating, to: Creating},
+		{from: Warning, to: Updating, valid: true},
+		{from: Error, to: Updating, valid: true},
+		{from: Error, to: Running, valid: true},
+		{from: Error, to: Warning},
+		{from: Error, to: Creating},
+		{from: Error, to: Deleting, valid: true},
+		{from: Deleting, to: Error, valid: true},
+		{from: Deleting, to: Running},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+"->"+test.to, func(t *testing.T) {
+			err := ValidateStatusTransition(test.from, test.to)
+			if test.valid {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err.Error())
+				}
+
+				return
+			}
+
+			if _, ok := err.(*StatusTransitionError); !ok {
+				t.Fatalf("expected a status transition error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateStatusTransition_ErrorRecovery(t *testing.T) {
+	path := []string{Creating, Error, Updating, Running}
+
+	from := ""
+	for _, to := range path {
+		if err := ValidateStatusTransition(from, to); err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+
+		from = to
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		invalid bool
+	}{
+		{spec: "* * * * *"},
+		{spec: "0 2 * * sat,sun"},
+		{spec: "*/15 1-5 1,15 jan-jun 1-5"},
+		{spec: "@daily"},
+		{spec: "0 0 * * 7"},
+		{spec: "* * * *", invalid: true},
+		{spec: "60 * * * *", invalid: true},
+		{spec: "0 24 * * *", invalid: true},
+		{spec: "0 0 0 * *", invalid: true},
+		{spec: "0 0 * 13 *", invalid: true},
+		{spec: "0 0 * * 8", invalid: true},
+		{spec: "5-1 * * * *", invalid: true},
+		{spec: "*/0 * * * *", invalid: true},
+		{spec: "a * * * *", invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			_, err := Parse(test.spec)
+			if test.invalid && err == nil {
+				t.Fatal("expected an error")
+			} else if !test.invalid && err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	utc := time.UTC
+
+	tests := []struct {
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{spec: "* * * * *", from: time.Date(2018, 11, 20, 10, 30, 15, 0, utc), expected: time.Date(2018, 11, 20, 10, 31, 0, 0, utc)},
+		{spec: "0 2 * * *", from: time.Date(2018, 11, 20, 2, 0, 0, 0, utc), expected: time.Date(2018, 11, 21, 2, 0, 0, 0, utc)},
+		{spec: "0 2 * * *", from: time.Date(2018, 11, 20, 1, 59, 0, 0, utc), expected: time.Date(2018, 11, 20, 2, 0, 0, 0, utc)},
+		{spec: "30 22 * * sat", from: time.Date(2018, 11, 20, 10, 0, 0, 0, utc), expected: time.Date(2018, 11, 24, 22, 30, 0, 0, utc)},
+		{spec: "0 0 * * 7", from: time.Date(2018, 11, 20, 10, 0, 0, 0, utc), expected: time.Date(2018, 11, 25, 0, 0, 0, 0, utc)},
+		{spec: "0 0 1 * *", from: time.Date(2018, 12, 15, 0, 0, 0, 0, utc), expected: time.Date(2019, 1, 1, 0, 0, 0, 0, utc)},
+		{spec: "0 0 13 * fri", from: time.Date(2018, 11, 10, 0, 0, 0, 0, utc), expected: time.Date(2018, 11, 13, 0, 0, 0, 0, utc)},
+		{spec: "0 0 29 2 *", from: time.Date(2018, 3, 1, 0, 0, 0, 0, utc), expected: time.Date(2020, 2, 29, 0, 0, 0, 0, utc)},
+		{spec: "0 0 30 2 *", from: time.Date(2018, 3, 1, 0, 0, 0, 0, utc), expected: time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			schedule, err := Parse(test.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if next := schedule.Next(test.from); !next.Equal(test.expected) {
+				t.Fatalf("expected %s, got %s", test.expected, next)
+			}
+		})
+	}
+}
@@ -72,4 +72,5 @@ var (
 	ErrorAlibabaNodePoolFieldIsEmpty  = errors.New("At least one 'nodePool' is required.")
 	ErrorAlibabaNodePoolFieldLenError = errors.New("Only one 'nodePool' is supported.")
 	ErrorAlibabaMinNumberOfNodes      = errors.New("'num_of_nodes' must be greater than zero.")
+	ErrorAlibabaNoAutoscaling         = errors.New("Autoscaling is not supported for ACSK node pools.")
 )
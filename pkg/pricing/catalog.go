@@ -0,0 +1,127 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pricing
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/goph/emperror"
+)
+
+// defaultRegion is used in the catalog for prices which are the same in every region of a provider.
+const defaultRegion = "*"
+
+// Catalog is a static price source loaded from a YAML or JSON file:
+//
+//	currency: USD
+//	prices:
+//	  amazon:
+//	    us-east-1:
+//	      m4.xlarge: {onDemand: 0.2, spot: 0.06}
+//	    "*":
+//	      m4.xlarge: {onDemand: 0.22, cpus: 4}
+//
+// Prices listed under the "*" region apply to the regions without a price of their own,
+// the vCPU count of an instance type is looked up the same way.
+type Catalog struct {
+	CurrencyCode string                                 `json:"currency"`
+	Prices       map[string]map[string]map[string]Price `json:"prices"`
+}
+
+// NewCatalog returns an empty catalog.
+func NewCatalog(currency string) *Catalog {
+	return &Catalog{
+		CurrencyCode: currency,
+		Prices:       make(map[string]map[string]map[string]Price),
+	}
+}
+
+// LoadCatalog parses a catalog.
+func LoadCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, emperror.Wrap(err, "could not parse price catalog")
+	}
+
+	if catalog.CurrencyCode == "" {
+		catalog.CurrencyCode = "USD"
+	}
+
+	return &catalog, nil
+}
+
+// LoadCatalogFile reads and parses a catalog file.
+func LoadCatalogFile(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, emperror.With(emperror.Wrap(err, "could not read price catalog"), "path", path)
+	}
+
+	catalog, err := LoadCatalog(data)
+	if err != nil {
+		return nil, emperror.With(err, "path", path)
+	}
+
+	return catalog, nil
+}
+
+// Currency implements the Source interface.
+func (c *Catalog) Currency() string {
+	return c.CurrencyCode
+}
+
+// GetPrice implements the Source interface.
+// The price of a zone (eg. us-central1-a) is looked up in its region (us-central1) as well.
+func (c *Catalog) GetPrice(cloud string, region string, instanceType string) (Price, error) {
+	regions := c.Prices[cloud]
+
+	for _, r := range []string{region, zoneRegion(region), defaultRegion} {
+		if price, ok := regions[r][instanceType]; ok {
+			return price, nil
+		}
+	}
+
+	return Price{}, &PriceNotFoundError{
+		Cloud:        cloud,
+		Region:       region,
+		InstanceType: instanceType,
+	}
+}
+
+// GetCPUs implements the Source interface.
+func (c *Catalog) GetCPUs(cloud string, region string, instanceType string) (int, bool) {
+	regions := c.Prices[cloud]
+
+	for _, r := range []string{region, zoneRegion(region), defaultRegion} {
+		if price, ok := regions[r][instanceType]; ok && price.CPUs > 0 {
+			return price.CPUs, true
+		}
+	}
+
+	return 0, false
+}
+
+// zoneRegion returns the region of a zone named after its region with a single letter suffix.
+func zoneRegion(zone string) string {
+	i := strings.LastIndex(zone, "-")
+	if i < 0 || len(zone)-i != 2 {
+		return zone
+	}
+
+	return zone[:i]
+}
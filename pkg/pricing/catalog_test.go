@@ -0,0 +1,110 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pricing
+
+import (
+	"testing"
+)
+
+func TestCatalogGetPrice(t *testing.T) {
+	catalog, err := LoadCatalog([]byte(`{
+		"currency": "USD",
+		"prices": {
+			"amazon": {
+				"us-east-1": {"m4.xlarge": {"onDemand": 0.2, "spot": 0.06}},
+				"*": {"m4.xlarge": {"onDemand": 0.25}}
+			},
+			"google": {
+				"us-central1": {"n1-standard-2": {"onDemand": 0.095, "spot": 0.02}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name         string
+		cloud        string
+		region       string
+		instanceType string
+		expected     Price
+		notFound     bool
+	}{
+		{name: "region", cloud: "amazon", region: "us-east-1", instanceType: "m4.xlarge", expected: Price{OnDemand: 0.2, Spot: 0.06}},
+		{name: "default region", cloud: "amazon", region: "eu-west-1", instanceType: "m4.xlarge", expected: Price{OnDemand: 0.25}},
+		{name: "zone", cloud: "google", region: "us-central1-a", instanceType: "n1-standard-2", expected: Price{OnDemand: 0.095, Spot: 0.02}},
+		{name: "unknown instance type", cloud: "amazon", region: "us-east-1", instanceType: "m5.xlarge", notFound: true},
+		{name: "unknown cloud", cloud: "azure", region: "westeurope", instanceType: "Standard_B2s", notFound: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			price, err := catalog.GetPrice(test.cloud, test.region, test.instanceType)
+			if test.notFound {
+				if _, ok := err.(*PriceNotFoundError); !ok {
+					t.Fatalf("expected a not found error, got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err.Error())
+			}
+
+			if price != test.expected {
+				t.Fatalf("expected %+v, got %+v", test.expected, price)
+			}
+		})
+	}
+}
+
+func TestCatalogGetCPUs(t *testing.T) {
+	catalog, err := LoadCatalog([]byte(`{
+		"prices": {
+			"amazon": {
+				"us-east-1": {"m4.xlarge": {"onDemand": 0.2}},
+				"*": {"m4.xlarge": {"onDemand": 0.25, "cpus": 4}, "m5.metal": {"onDemand": 4.608}}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	tests := []struct {
+		name         string
+		region       string
+		instanceType string
+		cpus         int
+		known        bool
+	}{
+		{name: "default region", region: "us-east-1", instanceType: "m4.xlarge", cpus: 4, known: true},
+		{name: "zone", region: "us-east-1a", instanceType: "m4.xlarge", cpus: 4, known: true},
+		{name: "unknown vCPUs", region: "us-east-1", instanceType: "m5.metal"},
+		{name: "unknown instance type", region: "us-east-1", instanceType: "t3.nano"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpus, known := catalog.GetCPUs("amazon", test.region, test.instanceType)
+
+			if cpus != test.cpus || known != test.known {
+				t.Fatalf("expected %d (%t), got %d (%t)", test.cpus, test.known, cpus, known)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pricing provides the hourly prices and the sizes of the instance types offered by cloud providers.
+package pricing
+
+import (
+	"fmt"
+)
+
+// Price describes the hourly price of an instance type in a region.
+type Price struct {
+	// OnDemand is the hourly on-demand price of the instance type.
+	OnDemand float64 `json:"onDemand"`
+
+	// Spot is the typical hourly spot (or preemptible) price of the instance type, zero if unknown.
+	Spot float64 `json:"spot,omitempty"`
+
+	// CPUs is the number of vCPUs of the instance type, zero if unknown.
+	CPUs int `json:"cpus,omitempty"`
+}
+
+// Source provides instance type prices, eg. from a static catalog or from the API of a provider.
+type Source interface {
+	// Currency returns the currency of the prices.
+	Currency() string
+
+	// GetPrice returns the price of an instance type of a cloud provider in a region.
+	GetPrice(cloud string, region string, instanceType string) (Price, error)
+
+	// GetCPUs returns the number of vCPUs of an instance type of a cloud provider in a region.
+	// The second return value is false when the vCPU count is unknown.
+	GetCPUs(cloud string, region string, instanceType string) (int, bool)
+}
+
+// PriceNotFoundError is returned when the price of an instance type is not known.
+type PriceNotFoundError struct {
+	Cloud        string
+	Region       string
+	InstanceType string
+}
+
+func (e *PriceNotFoundError) Error() string {
+	return fmt.Sprintf("price of %s instance type [%s] in region [%s] is not known", e.Cloud, e.InstanceType, e.Region)
+}
+
+// NotFound tells the caller that the price is missing from the source.
+func (e *PriceNotFoundError) NotFound() bool {
+	return true
+}
@@ -39,6 +39,9 @@ type NodePool struct {
 	Image   string            `json:"image,omitempty" yaml:"image,omitempty"`
 	Shape   string            `json:"shape,omitempty" yaml:"shape,omitempty"`
 
+	// Autoscaling is not supported for OKE node pools, requests enabling it are rejected instead of being ignored
+	Autoscaling bool `json:"autoscaling,omitempty" yaml:"autoscaling,omitempty"`
+
 	subnetIds         []string
 	quantityPerSubnet uint
 }
@@ -163,6 +166,9 @@ func (c *Cluster) Validate(update bool) error {
 		if nodePool.Shape == "" && !update {
 			return fmt.Errorf("NodePool[%s]: Node shape must be specified", name)
 		}
+		if nodePool.Autoscaling {
+			return fmt.Errorf("NodePool[%s]: Autoscaling is not supported for OKE node pools", name)
+		}
 	}
 
 	return nil
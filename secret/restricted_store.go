@@ -51,6 +51,32 @@ func (s *restrictedSecretStore) Update(organizationID uint, secretID string, val
 	return s.secretStore.Update(organizationID, secretID, value)
 }
 
+func (s *restrictedSecretStore) Rollback(organizationID uint, secretID string, version int, updatedBy string) error {
+	if err := s.checkBlockingTags(organizationID, secretID); err != nil {
+		return err
+	}
+
+	previous, err := s.secretStore.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		return err
+	}
+
+	// check forbidden tags of the restored version as well
+	if err := HasForbiddenTag(previous.Tags); err != nil {
+		return err
+	}
+
+	return s.secretStore.Rollback(organizationID, secretID, version, updatedBy)
+}
+
+func (s *restrictedSecretStore) Rotate(organizationID uint, secretID string, updatedBy string) (int, error) {
+	if err := s.checkBlockingTags(organizationID, secretID); err != nil {
+		return 0, err
+	}
+
+	return s.secretStore.Rotate(organizationID, secretID, updatedBy)
+}
+
 func (s *restrictedSecretStore) Delete(organizationID uint, secretID string) error {
 	if err := s.checkBlockingTags(organizationID, secretID); err != nil {
 		return err
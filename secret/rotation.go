@@ -0,0 +1,118 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+// NotRotatableError describes a secret error where the values of the secret cannot be generated by Pipeline
+type NotRotatableError struct {
+	SecretID string
+	Reason   string
+}
+
+func (e NotRotatableError) Error() string {
+	return fmt.Sprintf("secret [%s] cannot be rotated: %s", e.SecretID, e.Reason)
+}
+
+// IsRotatableSecretType checks whether Pipeline is able to generate new values for secrets of a type
+func IsRotatableSecretType(secretType string) bool {
+	switch secretType {
+	case secretTypes.PasswordSecretType, secretTypes.HtpasswdSecretType, secretTypes.TLSSecretType:
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckRotatable checks whether new values can be generated for a secret
+func CheckRotatable(secretItem *SecretItemResponse) error {
+	if !IsRotatableSecretType(secretItem.Type) {
+		return NotRotatableError{SecretID: secretItem.ID, Reason: fmt.Sprintf("values of %s secrets are not generated", secretItem.Type)}
+	}
+
+	switch secretItem.Type {
+	case secretTypes.HtpasswdSecretType:
+		if secretItem.Values[secretTypes.Username] == "" {
+			return NotRotatableError{SecretID: secretItem.ID, Reason: "the htpasswd file is not generated"}
+		}
+
+	case secretTypes.TLSSecretType:
+		if secretItem.Values[secretTypes.TLSHosts] == "" {
+			return NotRotatableError{SecretID: secretItem.ID, Reason: "the certificates are not generated"}
+		}
+	}
+
+	return nil
+}
+
+// Rotate generates new values for a secret and stores them as its new version.
+// It returns the new version of the secret.
+func (ss *secretStore) Rotate(organizationID uint, secretID string, updatedBy string) (int, error) {
+
+	current, err := ss.Get(organizationID, secretID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := CheckRotatable(current); err != nil {
+		return 0, err
+	}
+
+	request := &CreateSecretRequest{
+		Name:      current.Name,
+		Type:      current.Type,
+		Values:    map[string]string{},
+		Tags:      current.Tags,
+		Version:   &current.Version,
+		UpdatedBy: updatedBy,
+	}
+
+	// keep the inputs of the generation only, generateValuesIfNeeded does the rest
+	switch current.Type {
+	case secretTypes.PasswordSecretType:
+		request.Values[secretTypes.Username] = current.Values[secretTypes.Username]
+		request.Values[secretTypes.Password] = DefaultPasswordFormat
+
+	case secretTypes.HtpasswdSecretType:
+		request.Values[secretTypes.Username] = current.Values[secretTypes.Username]
+
+	case secretTypes.TLSSecretType:
+		request.Values[secretTypes.TLSHosts] = current.Values[secretTypes.TLSHosts]
+		if validity := current.Values[secretTypes.TLSValidity]; validity != "" {
+			request.Values[secretTypes.TLSValidity] = validity
+		}
+	}
+
+	if err := generateValuesIfNeeded(request); err != nil {
+		return 0, err
+	}
+
+	log.Debugf("Rotate secret %d/%s (version %d)", organizationID, secretID, current.Version)
+
+	if err := ss.Update(organizationID, secretID, request); err != nil {
+		return 0, err
+	}
+
+	rotated, err := ss.Get(organizationID, secretID)
+	if err != nil {
+		return 0, err
+	}
+
+	return rotated.Version, nil
+}
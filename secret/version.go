@@ -0,0 +1,191 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// ErrSecretVersionNotExists denotes 'Not Found' errors for secret versions
+var ErrSecretVersionNotExists = fmt.Errorf("There's no secret version with this number")
+
+// SecretVersionResponse describes a version of a secret without its values
+type SecretVersionResponse struct {
+	Version   int        `json:"version"`
+	Current   bool       `json:"current"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	UpdatedBy string     `json:"updatedBy,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	Destroyed bool       `json:"destroyed,omitempty"`
+}
+
+// RollbackSecretRequest describes a request restoring a previous version of a secret
+type RollbackSecretRequest struct {
+	Version int `json:"version" binding:"required"`
+}
+
+// ListVersions returns the versions of a secret kept in Vault, the latest first.
+// Deleted and destroyed versions are listed without their author and tags.
+func (ss *secretStore) ListVersions(organizationID uint, secretID string) ([]*SecretVersionResponse, error) {
+
+	path := secretMetadataPath(organizationID, secretID)
+
+	log.Debugln("List secret versions:", path)
+
+	metadata, err := ss.Logical.Read(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret metadata")
+	}
+
+	if metadata == nil {
+		return nil, ErrSecretNotExists
+	}
+
+	response, err := parseSecretVersions(metadata.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range response {
+		if item.DeletedAt != nil || item.Destroyed {
+			continue
+		}
+
+		secret, err := ss.getVersion(organizationID, secretID, item.Version, false)
+		if err != nil && err != ErrSecretVersionNotExists {
+			return nil, err
+		} else if secret != nil {
+			item.UpdatedBy = secret.UpdatedBy
+			item.Tags = secret.Tags
+		}
+	}
+
+	return response, nil
+}
+
+// parseSecretVersions parses the metadata of a secret read from Vault into its versions, the latest first.
+func parseSecretVersions(metadata map[string]interface{}) ([]*SecretVersionResponse, error) {
+
+	var currentVersion int64
+	if v, ok := metadata["current_version"].(json.Number); ok {
+		currentVersion, _ = v.Int64()
+	}
+
+	versions := cast.ToStringMap(metadata["versions"])
+
+	response := make([]*SecretVersionResponse, 0, len(versions))
+
+	for key, value := range versions {
+		version, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid secret version: %s", key)
+		}
+
+		versionMetadata := cast.ToStringMap(value)
+
+		item := &SecretVersionResponse{
+			Version:   version,
+			Current:   int64(version) == currentVersion,
+			Destroyed: cast.ToBool(versionMetadata["destroyed"]),
+		}
+
+		item.UpdatedAt, err = time.Parse(time.RFC3339, cast.ToString(versionMetadata["created_time"]))
+		if err != nil {
+			return nil, err
+		}
+
+		if deletionTime := cast.ToString(versionMetadata["deletion_time"]); deletionTime != "" {
+			deletedAt, err := time.Parse(time.RFC3339, deletionTime)
+			if err != nil {
+				return nil, err
+			}
+
+			item.DeletedAt = &deletedAt
+		}
+
+		response = append(response, item)
+	}
+
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Version > response[j].Version
+	})
+
+	return response, nil
+}
+
+// GetVersion returns a version of a secret with its values
+func (ss *secretStore) GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	return ss.getVersion(organizationID, secretID, version, true)
+}
+
+func (ss *secretStore) getVersion(organizationID uint, secretID string, version int, values bool) (*SecretItemResponse, error) {
+
+	path := secretDataPath(organizationID, secretID)
+
+	log.Debugf("Get secret version: %s (%d)", path, version)
+
+	secret, err := ss.Logical.ReadWithData(path, map[string][]string{
+		"version": {strconv.Itoa(version)},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret version")
+	}
+
+	// deleted and destroyed versions are returned with their metadata only
+	if secret == nil || secret.Data["data"] == nil {
+		return nil, ErrSecretVersionNotExists
+	}
+
+	return parseSecret(secretID, secret, values)
+}
+
+// Rollback stores the values and tags of a previous version of a secret as its new version
+func (ss *secretStore) Rollback(organizationID uint, secretID string, version int, updatedBy string) error {
+
+	current, err := ss.Get(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	previous, err := ss.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Rollback secret %d/%s from version %d to version %d", organizationID, secretID, current.Version, version)
+
+	return ss.Update(organizationID, secretID, newRollbackRequest(current, previous, updatedBy))
+}
+
+// newRollbackRequest returns the update request storing a previous version of a secret as its new version.
+// The current version is used for check-and-set, so that concurrent updates are not overwritten.
+func newRollbackRequest(current *SecretItemResponse, previous *SecretItemResponse, updatedBy string) *CreateSecretRequest {
+	return &CreateSecretRequest{
+		Name:      previous.Name,
+		Type:      previous.Type,
+		Values:    previous.Values,
+		Tags:      previous.Tags,
+		Version:   &current.Version,
+		UpdatedBy: updatedBy,
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+func TestParseSecretVersions(t *testing.T) {
+
+	metadata := map[string]interface{}{
+		"current_version": json.Number("3"),
+		"versions": map[string]interface{}{
+			"1": map[string]interface{}{
+				"created_time":  "2018-10-01T10:00:00Z",
+				"deletion_time": "",
+				"destroyed":     true,
+			},
+			"3": map[string]interface{}{
+				"created_time":  "2018-10-03T10:00:00Z",
+				"deletion_time": "",
+				"destroyed":     false,
+			},
+			"2": map[string]interface{}{
+				"created_time":  "2018-10-02T10:00:00Z",
+				"deletion_time": "2018-10-02T12:00:00Z",
+				"destroyed":     false,
+			},
+		},
+	}
+
+	versions, err := parseSecretVersions(metadata)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	deletedAt := time.Date(2018, 10, 2, 12, 0, 0, 0, time.UTC)
+
+	expected := []*SecretVersionResponse{
+		{Version: 3, Current: true, UpdatedAt: time.Date(2018, 10, 3, 10, 0, 0, 0, time.UTC)},
+		{Version: 2, UpdatedAt: time.Date(2018, 10, 2, 10, 0, 0, 0, time.UTC), DeletedAt: &deletedAt},
+		{Version: 1, UpdatedAt: time.Date(2018, 10, 1, 10, 0, 0, 0, time.UTC), Destroyed: true},
+	}
+
+	if len(versions) != len(expected) {
+		t.Fatalf("expected %d versions, got %d", len(expected), len(versions))
+	}
+
+	for i, version := range versions {
+		if !reflect.DeepEqual(version, expected[i]) {
+			t.Errorf("expected version: %+v, got: %+v", expected[i], version)
+		}
+	}
+}
+
+func TestParseSecretVersions_InvalidVersion(t *testing.T) {
+
+	metadata := map[string]interface{}{
+		"versions": map[string]interface{}{
+			"latest": map[string]interface{}{
+				"created_time": "2018-10-01T10:00:00Z",
+			},
+		},
+	}
+
+	if _, err := parseSecretVersions(metadata); err == nil {
+		t.Error("expected an error for an invalid version")
+	}
+}
+
+func TestNewRollbackRequest(t *testing.T) {
+
+	current := &SecretItemResponse{
+		Name:    "db-password",
+		Type:    pkgSecret.Password,
+		Values:  map[string]string{"password": "new"},
+		Tags:    []string{"new"},
+		Version: 5,
+	}
+
+	previous := &SecretItemResponse{
+		Name:    "db-password",
+		Type:    pkgSecret.Password,
+		Values:  map[string]string{"password": "old"},
+		Tags:    []string{"old"},
+		Version: 2,
+	}
+
+	request := newRollbackRequest(current, previous, "admin")
+
+	if request.Version == nil || *request.Version != current.Version {
+		t.Errorf("expected the current version %d to be used for check-and-set, got %v", current.Version, request.Version)
+	}
+
+	if !reflect.DeepEqual(request.Values, previous.Values) {
+		t.Errorf("expected the values of the previous version, got: %v", request.Values)
+	}
+
+	if !reflect.DeepEqual(request.Tags, previous.Tags) {
+		t.Errorf("expected the tags of the previous version, got: %v", request.Tags)
+	}
+
+	if request.UpdatedBy != "admin" {
+		t.Errorf("expected the request to be updated by admin, got: %s", request.UpdatedBy)
+	}
+}